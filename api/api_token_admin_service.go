@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 )
 
 type AllApiTokensResponse struct {
@@ -23,7 +24,14 @@ type ApiTokenService struct {
 }
 
 func (p *ApiTokenService) GetAllApiTokens() (*AllApiTokensResponse, *Response, error) {
-	req, _ := p.client.newRequest("admin/api-tokens", "GET", nil)
+	return p.GetAllApiTokensWithContext(context.Background())
+}
+
+func (p *ApiTokenService) GetAllApiTokensWithContext(ctx context.Context) (*AllApiTokensResponse, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/api-tokens", "GET", nil)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	var tokens AllApiTokensResponse
 
@@ -34,8 +42,34 @@ func (p *ApiTokenService) GetAllApiTokens() (*AllApiTokensResponse, *Response, e
 	return &tokens, resp, err
 }
 
+// IterateApiTokens iterates over all API tokens. Unleash returns them all at
+// once, so the iterator yields a single page.
+func (p *ApiTokenService) IterateApiTokens() *ApiTokenIterator {
+	return p.IterateApiTokensWithContext(context.Background())
+}
+
+func (p *ApiTokenService) IterateApiTokensWithContext(ctx context.Context) *ApiTokenIterator {
+	it := &ApiTokenIterator{}
+	it.pages = newPageIterator(ctx, 0, 0, func(ctx context.Context, offset int, limit int) (int, int, error) {
+		tokens, _, err := p.GetAllApiTokensWithContext(ctx)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = tokens.Tokens
+		return len(it.page), len(it.page), nil
+	})
+	return it
+}
+
 func (p *ApiTokenService) CreateApiToken(token ApiToken) (*ApiToken, *Response, error) {
-	req, _ := p.client.newRequest("admin/api-tokens", "POST", token)
+	return p.CreateApiTokenWithContext(context.Background(), token)
+}
+
+func (p *ApiTokenService) CreateApiTokenWithContext(ctx context.Context, token ApiToken) (*ApiToken, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/api-tokens", "POST", token)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	var tokenDetails ApiToken
 
@@ -47,10 +81,14 @@ func (p *ApiTokenService) CreateApiToken(token ApiToken) (*ApiToken, *Response,
 }
 
 func (p *ApiTokenService) UpdateApiToken(secret string, token ApiToken) (bool, *Response, error) {
+	return p.UpdateApiTokenWithContext(context.Background(), secret, token)
+}
+
+func (p *ApiTokenService) UpdateApiTokenWithContext(ctx context.Context, secret string, token ApiToken) (bool, *Response, error) {
 	if secret == "" {
 		return false, nil, ErrRequiredParam("token")
 	}
-	req, err := p.client.newRequest("admin/api-tokens/"+secret, "PUT", token)
+	req, err := p.client.newRequest(ctx, "admin/api-tokens/"+secret, "PUT", token)
 	if err != nil {
 		return false, nil, err
 	}
@@ -58,22 +96,29 @@ func (p *ApiTokenService) UpdateApiToken(secret string, token ApiToken) (bool, *
 	var putResponse bytes.Buffer
 
 	resp, err := p.client.do(req, &putResponse)
-	if resp == nil {
+	if err != nil {
 		return false, resp, err
 	}
 	return true, resp, nil
 }
 
 func (p *ApiTokenService) DeleteApiToken(secret string) (bool, *Response, error) {
+	return p.DeleteApiTokenWithContext(context.Background(), secret)
+}
+
+func (p *ApiTokenService) DeleteApiTokenWithContext(ctx context.Context, secret string) (bool, *Response, error) {
 	if secret == "" {
 		return false, nil, ErrRequiredParam("secret")
 	}
-	req, _ := p.client.newRequest("admin/api-tokens/"+secret, "DELETE", nil)
+	req, err := p.client.newRequest(ctx, "admin/api-tokens/"+secret, "DELETE", nil)
+	if err != nil {
+		return false, nil, err
+	}
 
 	var deleteResponse bytes.Buffer
 
 	resp, err := p.client.do(req, &deleteResponse)
-	if resp == nil {
+	if err != nil {
 		return false, resp, err
 	}
 	return true, resp, nil
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestApiTokenService_UpdateAndDeleteReportErrorResponses(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodPut, "admin/api-tokens/:secret").Reply(http.StatusForbidden, `{"name":"NoAccessError","message":"no access"}`)
+	mock.On(http.MethodDelete, "admin/api-tokens/:secret").Reply(http.StatusNotFound, `{"name":"NotFoundError","message":"no such token"}`)
+
+	ok, resp, err := client.ApiTokens.UpdateApiToken("secret", ApiToken{ExpiresAt: "2030-01-01T00:00:00Z"})
+	if ok || !errors.Is(err, ErrForbidden) || resp == nil {
+		t.Errorf("UpdateApiToken() = %v, %v, %v, want false with ErrForbidden and the response", ok, resp, err)
+	}
+	ok, resp, err = client.ApiTokens.DeleteApiToken("secret")
+	if ok || !errors.Is(err, ErrNotFound) || resp == nil {
+		t.Errorf("DeleteApiToken() = %v, %v, %v, want false with ErrNotFound and the response", ok, resp, err)
+	}
+}
+
+func TestApiTokenService_UpdateAndDelete(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodPut, "admin/api-tokens/:secret").Reply(http.StatusOK, "")
+	mock.On(http.MethodDelete, "admin/api-tokens/:secret").Reply(http.StatusOK, "")
+
+	if ok, _, err := client.ApiTokens.UpdateApiToken("secret", ApiToken{ExpiresAt: "2030-01-01T00:00:00Z"}); !ok || err != nil {
+		t.Errorf("UpdateApiToken() = %v, %v, want true, nil", ok, err)
+	}
+	if ok, _, err := client.ApiTokens.DeleteApiToken("secret"); !ok || err != nil {
+		t.Errorf("DeleteApiToken() = %v, %v, want true, nil", ok, err)
+	}
+}
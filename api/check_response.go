@@ -2,12 +2,92 @@ package api
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
-// CheckResponse checks the API response for errors, and returns them if present.
+// APIError is returned by every service method when Unleash answers with a
+// non-successful status code. It supports errors.Is against ErrNotFound,
+// ErrConflict, ErrUnauthorized, ErrForbidden and ErrValidation.
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+
+	// ID, Name and Message are taken from the Unleash error body when present.
+	ID      string
+	Name    string
+	Message string
+	// Details holds the individual validation failures reported by Unleash.
+	Details []ErrorDetail
+
+	// Body is the raw response body.
+	Body []byte
+}
+
+// ErrorDetail describes a single validation failure.
+type ErrorDetail struct {
+	Message     string `json:"message"`
+	Description string `json:"description,omitempty"`
+	Path        string `json:"path,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s: StatusCode %d", e.Method, e.URL, e.StatusCode)
+	switch {
+	case e.Name != "" && e.Message != "":
+		msg += ", " + e.Name + ": " + e.Message
+	case e.Message != "":
+		msg += ", " + e.Message
+	default:
+		body := string(e.Body)
+		if body == "" {
+			body = "empty"
+		}
+		msg += ", Body: " + body
+	}
+	for _, detail := range e.Details {
+		if detail.Description != "" && detail.Description != e.Message {
+			msg += "; " + detail.Description
+		} else if detail.Message != "" && detail.Message != e.Message {
+			msg += "; " + detail.Message
+		}
+	}
+	return msg
+}
+
+// Is reports whether the error matches one of the exported sentinel errors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.Name == "ValidationError" || e.Name == "BadDataError"
+	}
+	return false
+}
+
+// errorBody covers the error shapes returned by the different Unleash versions.
+type errorBody struct {
+	ID      string        `json:"id"`
+	Name    string        `json:"name"`
+	Message string        `json:"message"`
+	Details []ErrorDetail `json:"details"`
+	ErrorResponse
+}
+
+// CheckResponse checks the API response for errors, and returns them as an
+// *APIError if present.
 func CheckResponse(r *http.Response) error {
 	switch r.StatusCode {
 	case 200, 201, 202, 204, 207, 304:
@@ -18,9 +98,50 @@ func CheckResponse(r *http.Response) error {
 	if err != nil {
 		data = []byte(err.Error())
 	}
-	if data == nil {
-		data = []byte("empty")
-	}
 	r.Body = ioutil.NopCloser(bytes.NewBuffer(data)) // Preserve body
-	return fmt.Errorf("%s %s: StatusCode %d, Body: %s", r.Request.Method, r.Request.RequestURI, r.StatusCode, string(data))
+
+	apiErr := &APIError{
+		StatusCode: r.StatusCode,
+		Body:       data,
+	}
+	if r.Request != nil {
+		apiErr.Method = r.Request.Method
+		apiErr.URL = r.Request.RequestURI
+		if r.Request.URL != nil {
+			apiErr.URL = displayURL(r.Request.URL)
+		}
+	}
+
+	var body errorBody
+	if len(data) > 0 && json.Unmarshal(data, &body) == nil {
+		apiErr.ID = body.ID
+		apiErr.Name = body.Name
+		apiErr.Message = body.Message
+		apiErr.Details = body.Details
+		if apiErr.Name == "" {
+			apiErr.Name = body.Error.Name
+		}
+		if apiErr.Message == "" {
+			apiErr.Message = body.Error.Message
+		}
+	}
+	apiErr.Message = strings.TrimSpace(apiErr.Message)
+
+	return apiErr
+}
+
+// displayURL renders a request URL built by newRequest, which keeps the path
+// in Opaque, including its scheme and host.
+func displayURL(u *url.URL) string {
+	if u.Opaque == "" || strings.HasPrefix(u.Opaque, "//") {
+		return u.String()
+	}
+	s := u.Opaque
+	if u.Host != "" {
+		s = u.Scheme + "://" + u.Host + s
+	}
+	if u.RawQuery != "" {
+		s += "?" + u.RawQuery
+	}
+	return s
 }
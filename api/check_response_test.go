@@ -0,0 +1,107 @@
+package api
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestCheckResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		response   *http.Response
+		wantErr    *APIError
+		wantTarget error
+	}{
+		{
+			"Success",
+			createHttpResponseMock(http.StatusOK, `{}`, http.MethodGet),
+			nil,
+			nil,
+		},
+		{
+			"NotFound",
+			createHttpResponseMock(http.StatusNotFound, `{"id":"abc","name":"NotFoundError","message":"Could not find feature"}`, http.MethodGet),
+			&APIError{
+				StatusCode: http.StatusNotFound,
+				Method:     http.MethodGet,
+				URL:        "local",
+				ID:         "abc",
+				Name:       "NotFoundError",
+				Message:    "Could not find feature",
+				Body:       []byte(`{"id":"abc","name":"NotFoundError","message":"Could not find feature"}`),
+			},
+			ErrNotFound,
+		},
+		{
+			"ValidationWithDetails",
+			createHttpResponseMock(http.StatusBadRequest, `{"name":"ValidationError","message":"Bad request","details":[{"message":"name is required","description":"name is required"}]}`, http.MethodPost),
+			&APIError{
+				StatusCode: http.StatusBadRequest,
+				Method:     http.MethodPost,
+				URL:        "local",
+				Name:       "ValidationError",
+				Message:    "Bad request",
+				Details:    []ErrorDetail{{Message: "name is required", Description: "name is required"}},
+				Body:       []byte(`{"name":"ValidationError","message":"Bad request","details":[{"message":"name is required","description":"name is required"}]}`),
+			},
+			ErrValidation,
+		},
+		{
+			"LegacyEnvelope",
+			createHttpResponseMock(http.StatusConflict, `{"error":{"name":"NameExistsError","message":"Project already exists"}}`, http.MethodPost),
+			&APIError{
+				StatusCode: http.StatusConflict,
+				Method:     http.MethodPost,
+				URL:        "local",
+				Name:       "NameExistsError",
+				Message:    "Project already exists",
+				Body:       []byte(`{"error":{"name":"NameExistsError","message":"Project already exists"}}`),
+			},
+			ErrConflict,
+		},
+		{
+			"UnparseableBody",
+			createHttpResponseMock(http.StatusUnauthorized, `Unauthorized`, http.MethodGet),
+			&APIError{
+				StatusCode: http.StatusUnauthorized,
+				Method:     http.MethodGet,
+				URL:        "local",
+				Body:       []byte(`Unauthorized`),
+			},
+			ErrUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckResponse(tt.response)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("CheckResponse() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("CheckResponse() error = %v, want *APIError", err)
+			}
+			if !reflect.DeepEqual(apiErr, tt.wantErr) {
+				t.Errorf("CheckResponse() got = %#v, want %#v", apiErr, tt.wantErr)
+			}
+			if !errors.Is(err, tt.wantTarget) {
+				t.Errorf("errors.Is(%v, %v) = false, want true", err, tt.wantTarget)
+			}
+			if errors.Is(err, ErrForbidden) {
+				t.Errorf("errors.Is(%v, ErrForbidden) = true, want false", err)
+			}
+
+			body, _ := ioutil.ReadAll(tt.response.Body)
+			if string(body) != string(tt.wantErr.Body) {
+				t.Errorf("response body not preserved, got %q", body)
+			}
+		})
+	}
+}
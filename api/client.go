@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"io/ioutil"
@@ -9,15 +10,25 @@ import (
 	"net/url"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/google/go-querystring/query"
 )
 
+// ApiClient talks to the Unleash Admin API. It is configured once through
+// NewClient and its options and is safe for concurrent use afterwards.
 type ApiClient struct {
-	apiUrl    *url.URL
-	authToken string
-	client    HTTPClient
-	UserAgent string
+	apiUrl      *url.URL
+	authToken   string
+	client      HTTPClient
+	transport   http.RoundTripper
+	userAgent   string
+	timeout     time.Duration
+	retryPolicy *RetryPolicy
+	logger      Logger
+	rateLimiter RateLimiter
+	headers     http.Header
+	stickiness  []string
 
 	FeatureTags    *FeatureTagsService
 	FeatureToggles *FeatureTogglesService
@@ -27,6 +38,9 @@ type ApiClient struct {
 	Variants       *VariantsService
 	Users          *UsersService
 	ApiTokens      *ApiTokenService
+	TagTypes       *TagTypesService
+	ContextFields  *ContextFieldsService
+	Segments       *SegmentsService
 }
 
 // HTTPClient interface
@@ -61,24 +75,38 @@ func (c *ApiClient) setAuthToken(authToken string) error {
 	return nil
 }
 
-func NewClient(httpClient HTTPClient, apiUrl string, authToken string) (*ApiClient, error) {
-	if httpClient == nil {
-		httpClient = &http.Client{
-			Transport: &http.Transport{
-				Proxy: http.ProxyFromEnvironment,
-			},
-		}
+// NewClient returns a client for the Unleash instance at apiUrl, authenticating
+// with authToken. Behaviour can be customised with Option values such as
+// WithHTTPClient, WithTimeout or WithRetryPolicy.
+func NewClient(apiUrl string, authToken string, opts ...Option) (*ApiClient, error) {
+	c := &ApiClient{
+		userAgent: userAgent,
+		headers:   make(http.Header),
 	}
-	c := &ApiClient{}
 	if err := c.setApiUrl(apiUrl); err != nil {
 		return nil, err
 	}
 	if err := c.setAuthToken(authToken); err != nil {
 		return nil, err
 	}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.client == nil {
+		transport := c.transport
+		if transport == nil {
+			transport = &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+			}
+		}
+		c.client = &http.Client{Transport: transport}
+	} else if c.transport != nil {
+		return nil, ErrTransportWithHTTPClient
+	}
 
-	c.client = httpClient
-	c.UserAgent = userAgent
 	c.FeatureTags = &FeatureTagsService{client: c}
 	c.FeatureToggles = &FeatureTogglesService{client: c}
 	c.Projects = &ProjectsService{client: c}
@@ -87,11 +115,18 @@ func NewClient(httpClient HTTPClient, apiUrl string, authToken string) (*ApiClie
 	c.Variants = &VariantsService{client: c}
 	c.Users = &UsersService{client: c}
 	c.ApiTokens = &ApiTokenService{client: c}
+	c.TagTypes = &TagTypesService{client: c}
+	c.ContextFields = &ContextFieldsService{client: c}
+	c.Segments = &SegmentsService{client: c}
 
 	return c, nil
 }
 
-func (c *ApiClient) newRequest(path string, method string, opt interface{}) (*http.Request, error) {
+func (c *ApiClient) newRequest(ctx context.Context, path string, method string, opt interface{}) (*http.Request, error) {
+	if ctx == nil {
+		return nil, ErrContextCannotBeNil
+	}
+
 	var u = *c.apiUrl
 	u.Opaque = c.apiUrl.Path + path
 
@@ -103,7 +138,7 @@ func (c *ApiClient) newRequest(path string, method string, opt interface{}) (*ht
 		u.RawQuery = q.Encode()
 	}
 
-	req := &http.Request{
+	req := (&http.Request{
 		Method:     method,
 		URL:        &u,
 		Proto:      "HTTP/1.1",
@@ -111,10 +146,17 @@ func (c *ApiClient) newRequest(path string, method string, opt interface{}) (*ht
 		ProtoMinor: 1,
 		Header:     make(http.Header),
 		Host:       u.Host,
+	}).WithContext(ctx)
+	for key, values := range c.headers {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	} else {
+		req.Header.Set("User-Agent", userAgent)
 	}
-	req.Header.Set("User-Agent", userAgent)
 
-	if method == "POST" || method == "PUT" {
+	if opt != nil && (method == "POST" || method == "PUT" || method == "PATCH") {
 		bodyBytes, err := json.Marshal(opt)
 		if err != nil {
 			return nil, err
@@ -123,6 +165,9 @@ func (c *ApiClient) newRequest(path string, method string, opt interface{}) (*ht
 
 		u.RawQuery = ""
 		req.Body = ioutil.NopCloser(bodyReader)
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(bodyBytes)), nil
+		}
 		req.ContentLength = int64(bodyReader.Len())
 		req.Header.Set("Content-Type", "application/json")
 	}
@@ -143,7 +188,13 @@ type Response struct {
 }
 
 func (c *ApiClient) do(req *http.Request, v interface{}) (*Response, error) {
-	resp, err := c.client.Do(req)
+	if c.timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), c.timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/sighphyre/go-unleash-api/mocks"
+)
+
+type contextKey string
+
+func TestApiClient_PropagatesContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextKey("request-id"), "abc123")
+
+	var gotRequest *http.Request
+	mocks.GetDoFunc = func(req *http.Request) (*http.Response, error) {
+		gotRequest = req
+		return createHttpResponseMock(200, `{"name":"MyToggle","project":"default"}`, "GET"), nil
+	}
+
+	_, _, err := featureTogglesService.GetFeatureByNameWithContext(ctx, "default", "MyToggle")
+	if err != nil {
+		t.Fatalf("FeatureTogglesService.GetFeatureByNameWithContext() error = %v", err)
+	}
+	if gotRequest == nil {
+		t.Fatal("expected a request to be sent")
+	}
+	if got := gotRequest.Context().Value(contextKey("request-id")); got != "abc123" {
+		t.Errorf("request context value = %v, want %v", got, "abc123")
+	}
+}
+
+func TestApiClient_RejectsNilContext(t *testing.T) {
+	mocks.GetDoFunc = func(req *http.Request) (*http.Response, error) {
+		t.Fatal("no request should be sent with a nil context")
+		return nil, nil
+	}
+
+	_, _, err := featureTogglesService.GetFeatureByNameWithContext(nil, "default", "MyToggle")
+	if err != ErrContextCannotBeNil {
+		t.Errorf("FeatureTogglesService.GetFeatureByNameWithContext() error = %v, want %v", err, ErrContextCannotBeNil)
+	}
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/sighphyre/go-unleash-api/internal/semver"
+)
+
+// Operator is the comparison a Constraint applies to a context field.
+type Operator string
+
+const (
+	OperatorIn            Operator = "IN"
+	OperatorNotIn         Operator = "NOT_IN"
+	OperatorStrContains   Operator = "STR_CONTAINS"
+	OperatorStrStartsWith Operator = "STR_STARTS_WITH"
+	OperatorStrEndsWith   Operator = "STR_ENDS_WITH"
+	OperatorNumEq         Operator = "NUM_EQ"
+	OperatorNumGt         Operator = "NUM_GT"
+	OperatorNumGte        Operator = "NUM_GTE"
+	OperatorNumLt         Operator = "NUM_LT"
+	OperatorNumLte        Operator = "NUM_LTE"
+	OperatorDateAfter     Operator = "DATE_AFTER"
+	OperatorDateBefore    Operator = "DATE_BEFORE"
+	OperatorSemverEq      Operator = "SEMVER_EQ"
+	OperatorSemverGt      Operator = "SEMVER_GT"
+	OperatorSemverLt      Operator = "SEMVER_LT"
+)
+
+// Constraint restricts a strategy to contexts whose field ContextName matches
+// Values (for IN, NOT_IN and the STR_ operators) or Value (for the NUM_,
+// DATE_ and SEMVER_ operators).
+type Constraint struct {
+	ContextName     string   `json:"contextName"`
+	Operator        Operator `json:"operator"`
+	Values          []string `json:"values,omitempty"`
+	Value           string   `json:"value,omitempty"`
+	CaseInsensitive bool     `json:"caseInsensitive,omitempty"`
+	Inverted        bool     `json:"inverted,omitempty"`
+}
+
+// IsMultiValue reports whether the operator compares against Values rather
+// than a single Value.
+func (o Operator) IsMultiValue() bool {
+	switch o {
+	case OperatorIn, OperatorNotIn, OperatorStrContains, OperatorStrStartsWith, OperatorStrEndsWith:
+		return true
+	}
+	return false
+}
+
+// IsValid reports whether the operator is one Unleash knows about.
+func (o Operator) IsValid() bool {
+	switch o {
+	case OperatorIn, OperatorNotIn,
+		OperatorStrContains, OperatorStrStartsWith, OperatorStrEndsWith,
+		OperatorNumEq, OperatorNumGt, OperatorNumGte, OperatorNumLt, OperatorNumLte,
+		OperatorDateAfter, OperatorDateBefore,
+		OperatorSemverEq, OperatorSemverGt, OperatorSemverLt:
+		return true
+	}
+	return false
+}
+
+// Validate checks that the operator is known and that the values fit it.
+// Errors wrap ErrInvalidConstraint.
+func (c Constraint) Validate() error {
+	if c.ContextName == "" {
+		return fmt.Errorf("%w: contextName is required", ErrInvalidConstraint)
+	}
+	if !c.Operator.IsValid() {
+		return fmt.Errorf("%w: unknown operator %q on %s", ErrInvalidConstraint, c.Operator, c.ContextName)
+	}
+
+	if c.Operator.IsMultiValue() {
+		if len(c.Values) == 0 {
+			return fmt.Errorf("%w: operator %s on %s needs at least one value", ErrInvalidConstraint, c.Operator, c.ContextName)
+		}
+		return nil
+	}
+
+	if c.Value == "" {
+		return fmt.Errorf("%w: operator %s on %s needs a value", ErrInvalidConstraint, c.Operator, c.ContextName)
+	}
+	switch c.Operator {
+	case OperatorNumEq, OperatorNumGt, OperatorNumGte, OperatorNumLt, OperatorNumLte:
+		if _, err := strconv.ParseFloat(c.Value, 64); err != nil {
+			return fmt.Errorf("%w: operator %s on %s needs a number, got %q", ErrInvalidConstraint, c.Operator, c.ContextName, c.Value)
+		}
+	case OperatorDateAfter, OperatorDateBefore:
+		if _, err := time.Parse(time.RFC3339, c.Value); err != nil {
+			return fmt.Errorf("%w: operator %s on %s needs an RFC 3339 date, got %q", ErrInvalidConstraint, c.Operator, c.ContextName, c.Value)
+		}
+	case OperatorSemverEq, OperatorSemverGt, OperatorSemverLt:
+		if _, err := semver.Parse(c.Value); err != nil {
+			return fmt.Errorf("%w: operator %s on %s needs a semantic version, got %q", ErrInvalidConstraint, c.Operator, c.ContextName, c.Value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestConstraint_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		constraint Constraint
+		wantErr    bool
+	}{
+		{"In", Constraint{ContextName: "userId", Operator: OperatorIn, Values: []string{"1", "2"}}, false},
+		{"InWithoutValues", Constraint{ContextName: "userId", Operator: OperatorIn}, true},
+		{"StrContains", Constraint{ContextName: "email", Operator: OperatorStrContains, Values: []string{"@example.com"}, CaseInsensitive: true}, false},
+		{"NumGt", Constraint{ContextName: "age", Operator: OperatorNumGt, Value: "18.5"}, false},
+		{"NumGtNotANumber", Constraint{ContextName: "age", Operator: OperatorNumGt, Value: "eighteen"}, true},
+		{"DateAfter", Constraint{ContextName: "currentTime", Operator: OperatorDateAfter, Value: "2023-01-01T00:00:00.000Z"}, false},
+		{"DateAfterNotADate", Constraint{ContextName: "currentTime", Operator: OperatorDateAfter, Value: "yesterday"}, true},
+		{"SemverEq", Constraint{ContextName: "appVersion", Operator: OperatorSemverEq, Value: "1.2.3-beta.1", Inverted: true}, false},
+		{"SemverEqNotAVersion", Constraint{ContextName: "appVersion", Operator: OperatorSemverEq, Value: "1.2"}, true},
+		{"SingleValueMissing", Constraint{ContextName: "age", Operator: OperatorNumLte}, true},
+		{"UnknownOperator", Constraint{ContextName: "userId", Operator: "EQUALS", Values: []string{"1"}}, true},
+		{"MissingContextName", Constraint{Operator: OperatorIn, Values: []string{"1"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.constraint.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Constraint.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidConstraint) {
+				t.Errorf("Constraint.Validate() error = %v, want it to wrap ErrInvalidConstraint", err)
+			}
+		})
+	}
+}
+
+func TestFeatureStrategy_DecodesConstraints(t *testing.T) {
+	body := `{
+		"id": "6b5157cb-343a-41e7-bfa3-7b4ec3044840",
+		"name": "flexibleRollout",
+		"constraints": [
+			{"contextName": "appName", "operator": "IN", "values": ["web", "ios"]},
+			{"contextName": "appVersion", "operator": "SEMVER_GT", "value": "2.0.0", "inverted": true},
+			{"contextName": "email", "operator": "STR_ENDS_WITH", "values": ["@example.com"], "caseInsensitive": true}
+		],
+		"parameters": {"rollout": "50", "stickiness": "default", "groupId": "checkout"},
+		"sortOrder": 1
+	}`
+
+	var strategy FeatureStrategy
+	if err := json.Unmarshal([]byte(body), &strategy); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	want := []Constraint{
+		{ContextName: "appName", Operator: OperatorIn, Values: []string{"web", "ios"}},
+		{ContextName: "appVersion", Operator: OperatorSemverGt, Value: "2.0.0", Inverted: true},
+		{ContextName: "email", Operator: OperatorStrEndsWith, Values: []string{"@example.com"}, CaseInsensitive: true},
+	}
+	if !reflect.DeepEqual(strategy.Constraints, want) {
+		t.Errorf("decoded constraints = %+v, want %+v", strategy.Constraints, want)
+	}
+}
+
+func TestFeatureTogglesService_AddStrategyToFeature_ValidatesConstraints(t *testing.T) {
+	mock, client := newTestClient(t)
+	route := mock.On(http.MethodPost, "admin/projects/:project/features/:feature/environments/:environment/strategies").
+		Reply(http.StatusOK, `{"id":"1","name":"default"}`)
+
+	invalid := FeatureStrategy{
+		Name:        "default",
+		Constraints: []Constraint{{ContextName: "age", Operator: OperatorNumGt, Value: "old"}},
+	}
+	if _, _, err := client.FeatureToggles.AddStrategyToFeature("default", "checkout", "production", invalid); !errors.Is(err, ErrInvalidConstraint) {
+		t.Errorf("AddStrategyToFeature() error = %v, want ErrInvalidConstraint", err)
+	}
+	if route.Calls() != 0 {
+		t.Errorf("an invalid strategy was sent to the server")
+	}
+
+	valid := FeatureStrategy{
+		Name:        "default",
+		Constraints: []Constraint{{ContextName: "age", Operator: OperatorNumGt, Value: "18"}},
+	}
+	if _, _, err := client.FeatureToggles.AddStrategyToFeature("default", "checkout", "production", valid); err != nil {
+		t.Fatalf("AddStrategyToFeature() error = %v", err)
+	}
+	sent := mock.Requests()[0]
+	var got FeatureStrategy
+	if err := sent.DecodeBody(&got); err != nil {
+		t.Fatalf("DecodeBody() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Constraints, valid.Constraints) {
+		t.Errorf("sent constraints = %+v, want %+v", got.Constraints, valid.Constraints)
+	}
+}
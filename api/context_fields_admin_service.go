@@ -0,0 +1,138 @@
+package api
+
+import (
+	"bytes"
+	"context"
+)
+
+// ContextField is a field of the Unleash context that constraints and
+// stickiness can refer to.
+type ContextField struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Stickiness makes the field available as variant and rollout stickiness.
+	Stickiness bool `json:"stickiness"`
+	SortOrder  int  `json:"sortOrder"`
+	// LegalValues restricts the values constraints on the field may use.
+	LegalValues []LegalValue `json:"legalValues,omitempty"`
+	CreatedAt   string       `json:"createdAt,omitempty"`
+}
+
+// LegalValue is a value allowed for a context field.
+type LegalValue struct {
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+type ContextFieldsService struct {
+	client *ApiClient
+}
+
+// GetAllContextFields returns every context field, the built-in ones
+// included.
+func (p *ContextFieldsService) GetAllContextFields() (*[]ContextField, *Response, error) {
+	return p.GetAllContextFieldsWithContext(context.Background())
+}
+
+func (p *ContextFieldsService) GetAllContextFieldsWithContext(ctx context.Context) (*[]ContextField, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/context", "GET", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var fields []ContextField
+
+	resp, err := p.client.do(req, &fields)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &fields, resp, err
+}
+
+func (p *ContextFieldsService) GetContextField(name string) (*ContextField, *Response, error) {
+	return p.GetContextFieldWithContext(context.Background(), name)
+}
+
+func (p *ContextFieldsService) GetContextFieldWithContext(ctx context.Context, name string) (*ContextField, *Response, error) {
+	if name == "" {
+		return nil, nil, ErrRequiredParam("name")
+	}
+	req, err := p.client.newRequest(ctx, "admin/context/"+name, "GET", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var field ContextField
+
+	resp, err := p.client.do(req, &field)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &field, resp, err
+}
+
+func (p *ContextFieldsService) CreateContextField(field ContextField) (*ContextField, *Response, error) {
+	return p.CreateContextFieldWithContext(context.Background(), field)
+}
+
+func (p *ContextFieldsService) CreateContextFieldWithContext(ctx context.Context, field ContextField) (*ContextField, *Response, error) {
+	if field.Name == "" {
+		return nil, nil, ErrRequiredParam("name")
+	}
+	req, err := p.client.newRequest(ctx, "admin/context", "POST", field)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var created ContextField
+
+	resp, err := p.client.do(req, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &created, resp, err
+}
+
+func (p *ContextFieldsService) UpdateContextField(field ContextField) (bool, *Response, error) {
+	return p.UpdateContextFieldWithContext(context.Background(), field)
+}
+
+func (p *ContextFieldsService) UpdateContextFieldWithContext(ctx context.Context, field ContextField) (bool, *Response, error) {
+	if field.Name == "" {
+		return false, nil, ErrRequiredParam("name")
+	}
+	req, err := p.client.newRequest(ctx, "admin/context/"+field.Name, "PUT", field)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var updateResponse bytes.Buffer
+
+	resp, err := p.client.do(req, &updateResponse)
+	if err != nil {
+		return false, resp, err
+	}
+	return true, resp, nil
+}
+
+func (p *ContextFieldsService) DeleteContextField(name string) (bool, *Response, error) {
+	return p.DeleteContextFieldWithContext(context.Background(), name)
+}
+
+func (p *ContextFieldsService) DeleteContextFieldWithContext(ctx context.Context, name string) (bool, *Response, error) {
+	if name == "" {
+		return false, nil, ErrRequiredParam("name")
+	}
+	req, err := p.client.newRequest(ctx, "admin/context/"+name, "DELETE", nil)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var deleteResponse bytes.Buffer
+
+	resp, err := p.client.do(req, &deleteResponse)
+	if err != nil {
+		return false, resp, err
+	}
+	return true, resp, nil
+}
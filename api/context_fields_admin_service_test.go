@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestContextFieldsService(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodGet, "admin/context").Reply(http.StatusOK, `[
+		{"name":"appName","description":"Allows you to constrain on application name","stickiness":false,"sortOrder":2},
+		{"name":"region","description":"Deployment region","stickiness":true,"sortOrder":10,"legalValues":[{"value":"eu"},{"value":"us","description":"North America"}]}
+	]`)
+	mock.On(http.MethodGet, "admin/context/region").Reply(http.StatusOK, `{"name":"region","stickiness":true,"sortOrder":10}`)
+	mock.On(http.MethodPost, "admin/context").Reply(http.StatusCreated, `{"name":"region","stickiness":true,"sortOrder":10}`)
+	mock.On(http.MethodPut, "admin/context/region").Reply(http.StatusOK, "")
+	mock.On(http.MethodDelete, "admin/context/region").Reply(http.StatusOK, "")
+
+	all, _, err := client.ContextFields.GetAllContextFields()
+	if err != nil {
+		t.Fatalf("GetAllContextFields() error = %v", err)
+	}
+	if len(*all) != 2 {
+		t.Fatalf("GetAllContextFields() = %+v, want 2 fields", *all)
+	}
+	wantValues := []LegalValue{{Value: "eu"}, {Value: "us", Description: "North America"}}
+	if region := (*all)[1]; !region.Stickiness || region.SortOrder != 10 || !reflect.DeepEqual(region.LegalValues, wantValues) {
+		t.Errorf("GetAllContextFields()[1] = %+v", region)
+	}
+	if field, _, err := client.ContextFields.GetContextField("region"); err != nil || field.Name != "region" {
+		t.Errorf("GetContextField() = %+v, %v", field, err)
+	}
+
+	field := ContextField{Name: "region", Stickiness: true, SortOrder: 10, LegalValues: wantValues}
+	if _, _, err := client.ContextFields.CreateContextField(field); err != nil {
+		t.Errorf("CreateContextField() error = %v", err)
+	}
+	if ok, _, err := client.ContextFields.UpdateContextField(field); !ok || err != nil {
+		t.Errorf("UpdateContextField() = %v, %v", ok, err)
+	}
+	if ok, _, err := client.ContextFields.DeleteContextField("region"); !ok || err != nil {
+		t.Errorf("DeleteContextField() = %v, %v", ok, err)
+	}
+
+	var sent ContextField
+	if err := mock.RequestsTo(http.MethodPost, "admin/context")[0].DecodeBody(&sent); err != nil || !reflect.DeepEqual(sent, field) {
+		t.Errorf("CreateContextField() sent %+v, %v", sent, err)
+	}
+}
@@ -7,9 +7,20 @@ import (
 
 // Exported Errors
 var (
-	ErrNotFound               = errors.New("entity not found")
-	ErrApiUrlCannotBeEmpty    = errors.New("api_url cannot be empty")
-	ErrTokenAuthCannotBeEmpty = errors.New("auth_token cannot be empty")
+	ErrNotFound                = errors.New("entity not found")
+	ErrConflict                = errors.New("entity already exists or is in conflict")
+	ErrUnauthorized            = errors.New("unauthorized")
+	ErrForbidden               = errors.New("forbidden")
+	ErrValidation              = errors.New("validation failed")
+	ErrInvalidConstraint       = errors.New("invalid constraint")
+	ErrInvalidVariants         = errors.New("invalid variants")
+	ErrPayloadTypeMismatch     = errors.New("variant payload has a different type")
+	ErrIncompleteClone         = errors.New("feature cloned with failures")
+	ErrIncompleteMove          = errors.New("features moved with failures")
+	ErrApiUrlCannotBeEmpty     = errors.New("api_url cannot be empty")
+	ErrTokenAuthCannotBeEmpty  = errors.New("auth_token cannot be empty")
+	ErrContextCannotBeNil      = errors.New("context cannot be nil")
+	ErrTransportWithHTTPClient = errors.New("WithTransport cannot be combined with WithHTTPClient")
 )
 
 func ErrRequiredParam(param string) error {
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type archivedFeaturesResponse struct {
+	Version  int             `json:"version"`
+	Features []FeatureToggle `json:"features"`
+}
+
+type featureNamesBody struct {
+	Features []string `json:"features"`
+}
+
+// GetArchivedFeatures returns the archived features of every project.
+func (p *FeatureTogglesService) GetArchivedFeatures() (*[]FeatureToggle, *Response, error) {
+	return p.GetArchivedFeaturesWithContext(context.Background())
+}
+
+func (p *FeatureTogglesService) GetArchivedFeaturesWithContext(ctx context.Context) (*[]FeatureToggle, *Response, error) {
+	return p.getArchivedFeatures(ctx, "admin/archive/features")
+}
+
+// GetArchivedFeaturesByProject returns the archived features of a project.
+func (p *FeatureTogglesService) GetArchivedFeaturesByProject(projectId string) (*[]FeatureToggle, *Response, error) {
+	return p.GetArchivedFeaturesByProjectWithContext(context.Background(), projectId)
+}
+
+func (p *FeatureTogglesService) GetArchivedFeaturesByProjectWithContext(ctx context.Context, projectId string) (*[]FeatureToggle, *Response, error) {
+	if projectId == "" {
+		return nil, nil, ErrRequiredParam("projectId")
+	}
+	return p.getArchivedFeatures(ctx, "admin/archive/features/"+projectId)
+}
+
+func (p *FeatureTogglesService) getArchivedFeatures(ctx context.Context, path string) (*[]FeatureToggle, *Response, error) {
+	req, err := p.client.newRequest(ctx, path, "GET", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var archived archivedFeaturesResponse
+
+	resp, err := p.client.do(req, &archived)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &archived.Features, resp, err
+}
+
+// ReviveFeature moves an archived feature back into its project.
+func (p *FeatureTogglesService) ReviveFeature(featureName string) (bool, *Response, error) {
+	return p.ReviveFeatureWithContext(context.Background(), featureName)
+}
+
+func (p *FeatureTogglesService) ReviveFeatureWithContext(ctx context.Context, featureName string) (bool, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/archive/revive/"+featureName, "POST", nil)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var reviveResponse bytes.Buffer
+
+	resp, err := p.client.do(req, &reviveResponse)
+	if err != nil {
+		return false, resp, err
+	}
+	return true, resp, nil
+}
+
+// ReviveFeatures moves several archived features of a project back into it.
+func (p *FeatureTogglesService) ReviveFeatures(projectId string, featureNames []string) (bool, *Response, error) {
+	return p.ReviveFeaturesWithContext(context.Background(), projectId, featureNames)
+}
+
+func (p *FeatureTogglesService) ReviveFeaturesWithContext(ctx context.Context, projectId string, featureNames []string) (bool, *Response, error) {
+	if projectId == "" {
+		return false, nil, ErrRequiredParam("projectId")
+	}
+	return p.bulkArchiveAction(ctx, "admin/projects/"+projectId+"/revive", featureNames)
+}
+
+// DeleteArchivedFeatures permanently deletes several archived features of a
+// project.
+func (p *FeatureTogglesService) DeleteArchivedFeatures(projectId string, featureNames []string) (bool, *Response, error) {
+	return p.DeleteArchivedFeaturesWithContext(context.Background(), projectId, featureNames)
+}
+
+func (p *FeatureTogglesService) DeleteArchivedFeaturesWithContext(ctx context.Context, projectId string, featureNames []string) (bool, *Response, error) {
+	if projectId == "" {
+		return false, nil, ErrRequiredParam("projectId")
+	}
+	return p.bulkArchiveAction(ctx, "admin/projects/"+projectId+"/delete", featureNames)
+}
+
+func (p *FeatureTogglesService) bulkArchiveAction(ctx context.Context, path string, featureNames []string) (bool, *Response, error) {
+	if len(featureNames) == 0 {
+		return false, nil, ErrRequiredParam("featureNames")
+	}
+	req, err := p.client.newRequest(ctx, path, "POST", featureNamesBody{Features: featureNames})
+	if err != nil {
+		return false, nil, err
+	}
+
+	var actionResponse bytes.Buffer
+
+	resp, err := p.client.do(req, &actionResponse)
+	if err != nil {
+		return false, resp, err
+	}
+	return true, resp, nil
+}
+
+// DeleteArchivedFeaturesOlderThan permanently deletes the features archived
+// before cutoff, in one project or, when projectId is empty, in every project.
+// It returns the names of the deleted features. Features without an archive
+// date are kept.
+func (p *FeatureTogglesService) DeleteArchivedFeaturesOlderThan(projectId string, cutoff time.Time) ([]string, *Response, error) {
+	return p.DeleteArchivedFeaturesOlderThanWithContext(context.Background(), projectId, cutoff)
+}
+
+func (p *FeatureTogglesService) DeleteArchivedFeaturesOlderThanWithContext(ctx context.Context, projectId string, cutoff time.Time) ([]string, *Response, error) {
+	var archived *[]FeatureToggle
+	var resp *Response
+	var err error
+	if projectId == "" {
+		archived, resp, err = p.GetArchivedFeaturesWithContext(ctx)
+	} else {
+		archived, resp, err = p.GetArchivedFeaturesByProjectWithContext(ctx, projectId)
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	byProject := make(map[string][]string)
+	for _, feature := range *archived {
+		if feature.ArchivedAt == "" {
+			continue
+		}
+		archivedAt, err := time.Parse(time.RFC3339, feature.ArchivedAt)
+		if err != nil {
+			return nil, resp, fmt.Errorf("feature %s has an invalid archivedAt %q: %w", feature.Name, feature.ArchivedAt, err)
+		}
+		if archivedAt.Before(cutoff) {
+			project := feature.Project
+			if project == "" {
+				project = projectId
+			}
+			if project == "" {
+				// fail before deleting anything, deletes are sent per project
+				return nil, resp, fmt.Errorf("archived feature %s has no project", feature.Name)
+			}
+			byProject[project] = append(byProject[project], feature.Name)
+		}
+	}
+
+	projects := make([]string, 0, len(byProject))
+	for project := range byProject {
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+
+	var deleted []string
+	for _, project := range projects {
+		if _, resp, err = p.DeleteArchivedFeaturesWithContext(ctx, project, byProject[project]); err != nil {
+			return deleted, resp, err
+		}
+		deleted = append(deleted, byProject[project]...)
+	}
+	return deleted, resp, nil
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFeatureTogglesService_ReviveFeatures(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodPost, "admin/projects/default/revive").Reply(http.StatusOK, "")
+
+	if _, _, err := client.FeatureToggles.ReviveFeatures("default", nil); err == nil {
+		t.Error("ReviveFeatures() without features succeeded")
+	}
+	ok, _, err := client.FeatureToggles.ReviveFeatures("default", []string{"checkout", "search"})
+	if !ok || err != nil {
+		t.Fatalf("ReviveFeatures() = %v, %v", ok, err)
+	}
+	var sent featureNamesBody
+	if err := mock.Requests()[0].DecodeBody(&sent); err != nil {
+		t.Fatalf("DecodeBody() error = %v", err)
+	}
+	if !reflect.DeepEqual(sent.Features, []string{"checkout", "search"}) {
+		t.Errorf("sent features = %v", sent.Features)
+	}
+}
+
+func TestFeatureTogglesService_DeleteArchivedFeaturesOlderThan(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodGet, "admin/archive/features").Reply(http.StatusOK, `{"version":2,"features":[
+		{"name":"old-a","project":"default","archived":true,"archivedAt":"2023-01-01T00:00:00Z"},
+		{"name":"old-b","project":"payments","archived":true,"archivedAt":"2023-02-01T00:00:00Z"},
+		{"name":"recent","project":"default","archived":true,"archivedAt":"2024-06-01T00:00:00Z"},
+		{"name":"undated","project":"default","archived":true}
+	]}`)
+	mock.On(http.MethodPost, "admin/projects/:project/delete").Reply(http.StatusOK, "")
+
+	deleted, _, err := client.FeatureToggles.DeleteArchivedFeaturesOlderThan("", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("DeleteArchivedFeaturesOlderThan() error = %v", err)
+	}
+	if !reflect.DeepEqual(deleted, []string{"old-a", "old-b"}) {
+		t.Errorf("deleted = %v, want old-a and old-b", deleted)
+	}
+	requests := mock.RequestsTo(http.MethodPost, "admin/projects/:project/delete")
+	if len(requests) != 2 || requests[0].Path != "admin/projects/default/delete" || requests[1].Path != "admin/projects/payments/delete" {
+		t.Errorf("delete requests = %+v", requests)
+	}
+}
+
+func TestFeatureTogglesService_BulkArchiveActions_RequireProject(t *testing.T) {
+	mock, client := newTestClient(t)
+
+	if _, _, err := client.FeatureToggles.DeleteArchivedFeatures("", []string{"checkout"}); err == nil {
+		t.Error("DeleteArchivedFeatures() without a project error = nil")
+	}
+	if _, _, err := client.FeatureToggles.ReviveFeatures("", []string{"checkout"}); err == nil {
+		t.Error("ReviveFeatures() without a project error = nil")
+	}
+	if n := len(mock.Requests()); n != 0 {
+		t.Errorf("made %d requests, want none", n)
+	}
+}
+
+func TestFeatureTogglesService_DeleteArchivedFeaturesOlderThan_RequiresProjects(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodGet, "admin/archive/features").Reply(http.StatusOK, `{"version":2,"features":[
+		{"name":"old-a","project":"default","archived":true,"archivedAt":"2023-01-01T00:00:00Z"},
+		{"name":"orphan","archived":true,"archivedAt":"2023-01-01T00:00:00Z"}
+	]}`)
+	mock.On(http.MethodPost, "admin/projects/:project/delete").Reply(http.StatusOK, "")
+
+	deleted, _, err := client.FeatureToggles.DeleteArchivedFeaturesOlderThan("", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err == nil || len(deleted) != 0 {
+		t.Errorf("DeleteArchivedFeaturesOlderThan() = %v, %v, want an error and nothing deleted", deleted, err)
+	}
+	if n := len(mock.RequestsTo(http.MethodPost, "admin/projects/:project/delete")); n != 0 {
+		t.Errorf("sent %d delete requests, want none", n)
+	}
+}
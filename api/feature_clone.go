@@ -0,0 +1,176 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// CloneFeatureOptions configures CloneFeature.
+type CloneFeatureOptions struct {
+	// Name is the name of the new feature.
+	Name string
+	// ReplaceGroupId sets the groupId of flexibleRollout strategies to the new
+	// name, so the clone buckets users independently from the original.
+	ReplaceGroupId bool
+	// CopyEnabledState enables and disables the environments of the clone like
+	// those of the original. Clones start disabled everywhere otherwise.
+	CopyEnabledState bool
+}
+
+// CloneStepError is a step of CloneFeature that failed after the clone was
+// created.
+type CloneStepError struct {
+	// Step describes what failed, for example "add strategy flexibleRollout
+	// in production".
+	Step string
+	Err  error
+}
+
+func (e CloneStepError) Error() string {
+	return e.Step + ": " + e.Err.Error()
+}
+
+func (e CloneStepError) Unwrap() error {
+	return e.Err
+}
+
+// CloneResult describes a clone made by CloneFeature.
+type CloneResult struct {
+	// Feature is the clone as stored by Unleash after all steps ran.
+	Feature *FeatureToggle
+	// Replayed reports whether the clone was built step by step because the
+	// Unleash instance has no clone endpoint.
+	Replayed bool
+	// Failures lists the steps that failed after the clone was created.
+	Failures []CloneStepError
+}
+
+type cloneFeatureBody struct {
+	Name           string `json:"name"`
+	ReplaceGroupId bool   `json:"replaceGroupId"`
+}
+
+// CloneFeature copies a feature, with its strategies, variants and tags, into
+// a new feature in the same project. It uses the Unleash clone endpoint and
+// falls back to replaying the original step by step when the endpoint does not
+// exist. Failures after the clone was created do not stop the remaining steps;
+// they are listed in the result and reported as an error wrapping
+// ErrIncompleteClone.
+func (p *FeatureTogglesService) CloneFeature(projectId string, featureName string, opts CloneFeatureOptions) (*CloneResult, *Response, error) {
+	return p.CloneFeatureWithContext(context.Background(), projectId, featureName, opts)
+}
+
+func (p *FeatureTogglesService) CloneFeatureWithContext(ctx context.Context, projectId string, featureName string, opts CloneFeatureOptions) (*CloneResult, *Response, error) {
+	if opts.Name == "" {
+		return nil, nil, ErrRequiredParam("name")
+	}
+
+	result := &CloneResult{}
+	var source *FeatureToggle
+
+	body := cloneFeatureBody{Name: opts.Name, ReplaceGroupId: opts.ReplaceGroupId}
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/features/"+featureName+"/clone", "POST", body)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := p.client.do(req, nil)
+	if err != nil {
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || (apiErr.StatusCode != http.StatusNotFound && apiErr.StatusCode != http.StatusMethodNotAllowed) {
+			return nil, resp, err
+		}
+		// older instances have no clone endpoint; a missing original is
+		// reported by the replay as well
+		if source, resp, err = p.GetFeatureByNameWithContext(ctx, projectId, featureName); err != nil {
+			return nil, resp, err
+		}
+		result.Replayed = true
+		if resp, err = p.replayClone(ctx, projectId, *source, opts, result); err != nil {
+			return nil, resp, err
+		}
+	}
+
+	if opts.CopyEnabledState {
+		if source == nil {
+			if source, resp, err = p.GetFeatureByNameWithContext(ctx, projectId, featureName); err != nil {
+				result.fail("read the enabled state of "+featureName, err)
+			}
+		}
+		if source != nil {
+			for _, env := range source.Environments {
+				if _, _, err := p.EnableFeatureOnEnvironmentWithContext(ctx, projectId, opts.Name, env.Name, env.Enabled); err != nil {
+					result.fail("set the enabled state in "+env.Name, err)
+				}
+			}
+		}
+	}
+
+	if result.Feature, resp, err = p.GetFeatureByNameWithContext(ctx, projectId, opts.Name); err != nil {
+		result.fail("read the clone", err)
+	}
+	if len(result.Failures) > 0 {
+		return result, resp, fmt.Errorf("%w: %d steps failed, first: %v", ErrIncompleteClone, len(result.Failures), result.Failures[0])
+	}
+	return result, resp, nil
+}
+
+func (r *CloneResult) fail(step string, err error) {
+	r.Failures = append(r.Failures, CloneStepError{Step: step, Err: err})
+}
+
+// replayClone creates the clone and copies strategies, variants and tags one
+// request at a time. Only a failure to create the clone is returned.
+func (p *FeatureTogglesService) replayClone(ctx context.Context, projectId string, source FeatureToggle, opts CloneFeatureOptions, result *CloneResult) (*Response, error) {
+	clone := FeatureToggle{
+		Name:        opts.Name,
+		Description: source.Description,
+		Type:        source.Type,
+	}
+	_, resp, err := p.CreateFeatureWithContext(ctx, projectId, clone)
+	if err != nil {
+		return resp, err
+	}
+
+	for _, env := range source.Environments {
+		for _, strategy := range SortedStrategies(env.Strategies) {
+			strategy.ID = ""
+			if opts.ReplaceGroupId && strategy.Name == StrategyFlexibleRollout {
+				var params FlexibleRolloutParams
+				if err := strategy.DecodeParameters(&params); err != nil {
+					result.fail("read the parameters of "+strategy.Name+" in "+env.Name, err)
+					continue
+				}
+				params.GroupId = opts.Name
+				strategy.Parameters = params
+			}
+			if _, _, err := p.AddStrategyToFeatureWithContext(ctx, projectId, opts.Name, env.Name, strategy); err != nil {
+				result.fail("add strategy "+strategy.Name+" in "+env.Name, err)
+			}
+		}
+		if len(env.Variants) > 0 {
+			if _, _, err := p.client.Variants.SetEnvironmentVariantsWithContext(ctx, projectId, opts.Name, env.Name, env.Variants); err != nil {
+				result.fail("set variants in "+env.Name, err)
+			}
+		}
+	}
+
+	if len(source.Variants) > 0 {
+		if _, _, err := p.client.Variants.AddVariantsForFeatureToggleWithContext(ctx, projectId, opts.Name, source.Variants); err != nil {
+			result.fail("set feature variants", err)
+		}
+	}
+
+	tags, _, err := p.client.FeatureTags.GetAllFeatureTagsWithContext(ctx, source.Name)
+	if err != nil {
+		result.fail("read the tags of "+source.Name, err)
+		return resp, nil
+	}
+	for _, tag := range tags.Tags {
+		if _, _, err := p.client.FeatureTags.CreateFeatureTagsWithContext(ctx, opts.Name, tag); err != nil {
+			result.fail("add tag "+tag.Type+":"+tag.Value, err)
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/sighphyre/go-unleash-api/mocks"
+)
+
+func TestFeatureTogglesService_CloneFeature_ReplaysWithoutCloneEndpoint(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodPost, "admin/projects/default/features/checkout/clone").
+		Reply(http.StatusNotFound, `{"name":"NotFoundError","message":"The path you were looking for is not available."}`)
+	mock.On(http.MethodGet, "admin/projects/default/features/checkout").Reply(http.StatusOK, `{
+		"name": "checkout", "type": "experiment", "description": "New checkout",
+		"environments": [
+			{"name": "production", "enabled": true, "strategies": [
+				{"id": "2", "name": "default", "sortOrder": 2},
+				{"id": "1", "name": "flexibleRollout", "sortOrder": 1, "parameters": {"rollout": "50", "stickiness": "default", "groupId": "checkout"}}
+			], "variants": [{"name": "blue", "weight": 1000, "weightType": "variable", "stickiness": "default"}]},
+			{"name": "development", "enabled": false, "strategies": []}
+		],
+		"variants": []
+	}`)
+	create := mock.On(http.MethodPost, "admin/projects/default/features").Reply(http.StatusCreated, `{"name":"checkout-copy"}`)
+	strategies := mock.On(http.MethodPost, "admin/projects/default/features/checkout-copy/environments/production/strategies").
+		Reply(http.StatusOK, `{"id":"3"}`).
+		Reply(http.StatusBadRequest, `{"name":"ValidationError","message":"nope"}`)
+	mock.On(http.MethodPut, "admin/projects/default/features/checkout-copy/environments/production/variants").Reply(http.StatusOK, `{"version":1,"variants":[]}`)
+	mock.On(http.MethodGet, "admin/features/checkout/tags").Reply(http.StatusOK, `{"version":1,"tags":[{"type":"simple","value":"team-a"}]}`)
+	tags := mock.On(http.MethodPost, "admin/features/checkout-copy/tags").Reply(http.StatusCreated, `{"type":"simple","value":"team-a"}`)
+	mock.On(http.MethodPost, "admin/projects/default/features/checkout-copy/environments/:environment/:state").Reply(http.StatusOK, "")
+	mock.On(http.MethodGet, "admin/projects/default/features/checkout-copy").Reply(http.StatusOK, `{"name":"checkout-copy"}`)
+
+	result, _, err := client.FeatureToggles.CloneFeature("default", "checkout", CloneFeatureOptions{
+		Name:             "checkout-copy",
+		ReplaceGroupId:   true,
+		CopyEnabledState: true,
+	})
+	if !errors.Is(err, ErrIncompleteClone) {
+		t.Fatalf("CloneFeature() error = %v, want ErrIncompleteClone", err)
+	}
+	if !result.Replayed || result.Feature == nil || result.Feature.Name != "checkout-copy" {
+		t.Errorf("CloneFeature() result = %+v", result)
+	}
+	if len(result.Failures) != 1 || !errors.Is(result.Failures[0], ErrValidation) {
+		t.Errorf("CloneFeature() failures = %v, want the second strategy", result.Failures)
+	}
+
+	var created FeatureToggle
+	if err := mock.Requests()[2].DecodeBody(&created); err != nil || create.Calls() != 1 {
+		t.Fatalf("create request = %v, %v", created, err)
+	}
+	if created.Type != "experiment" || created.Description != "New checkout" {
+		t.Errorf("created feature = %+v", created)
+	}
+
+	var first FeatureStrategy
+	if err := mock.RequestsTo(http.MethodPost, "admin/projects/default/features/checkout-copy/environments/production/strategies")[0].DecodeBody(&first); err != nil {
+		t.Fatalf("DecodeBody() error = %v", err)
+	}
+	var params FlexibleRolloutParams
+	if err := first.DecodeParameters(&params); err != nil || first.ID != "" || params.GroupId != "checkout-copy" {
+		t.Errorf("first replayed strategy = %+v with %+v, want flexibleRollout grouped by the new name", first, params)
+	}
+	if strategies.Calls() != 2 || tags.Calls() != 1 {
+		t.Errorf("replayed %d strategies and %d tags, want 2 and 1", strategies.Calls(), tags.Calls())
+	}
+
+	var states []mocks.RecordedRequest
+	for _, req := range mock.RequestsTo(http.MethodPost, "admin/projects/default/features/checkout-copy/environments/:environment/:state") {
+		if strings.HasSuffix(req.Path, "/on") || strings.HasSuffix(req.Path, "/off") {
+			states = append(states, req)
+		}
+	}
+	if len(states) != 2 || states[0].Path != "admin/projects/default/features/checkout-copy/environments/production/on" ||
+		states[1].Path != "admin/projects/default/features/checkout-copy/environments/development/off" {
+		t.Errorf("enabled state requests = %+v", states)
+	}
+}
@@ -0,0 +1,199 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"time"
+)
+
+type staleFeaturesBody struct {
+	Features []string `json:"features"`
+	Stale    bool     `json:"stale"`
+}
+
+// MarkFeaturesStale marks the named features of a project stale, or active
+// again when stale is false.
+func (p *FeatureTogglesService) MarkFeaturesStale(projectId string, featureNames []string, stale bool) (bool, *Response, error) {
+	return p.MarkFeaturesStaleWithContext(context.Background(), projectId, featureNames, stale)
+}
+
+func (p *FeatureTogglesService) MarkFeaturesStaleWithContext(ctx context.Context, projectId string, featureNames []string, stale bool) (bool, *Response, error) {
+	if projectId == "" {
+		return false, nil, ErrRequiredParam("projectId")
+	}
+	if len(featureNames) == 0 {
+		return false, nil, ErrRequiredParam("featureNames")
+	}
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/stale", "POST", staleFeaturesBody{Features: featureNames, Stale: stale})
+	if err != nil {
+		return false, nil, err
+	}
+
+	var staleResponse bytes.Buffer
+
+	resp, err := p.client.do(req, &staleResponse)
+	if err != nil {
+		return false, resp, err
+	}
+	return true, resp, nil
+}
+
+// LifecycleOptions configures GetLifecycleReport.
+type LifecycleOptions struct {
+	// Projects limits the report to these projects. All projects are
+	// inspected when it is empty.
+	Projects []string
+	// Now is the time features are compared against. It defaults to the
+	// current time.
+	Now time.Time
+}
+
+// FeatureLifecycle is the lifecycle state of one feature.
+type FeatureLifecycle struct {
+	Name    string
+	Project string
+	Type    string
+	Stale   bool
+	// CreatedAt is zero when Unleash did not report a valid creation time.
+	CreatedAt time.Time
+	// LifetimeDays is the expected lifetime of the feature type; zero means
+	// the type is permanent.
+	LifetimeDays int
+	// DueAt is when the feature is expected to be removed, zero for
+	// permanent features and features without a creation time.
+	DueAt time.Time
+	// Overdue reports whether DueAt has passed.
+	Overdue bool
+	// DaysOverdue is the number of whole days since DueAt.
+	DaysOverdue int
+}
+
+// LifecycleGroup collects the features sharing a project and type.
+type LifecycleGroup struct {
+	Project      string
+	Type         string
+	LifetimeDays int
+	Features     []FeatureLifecycle
+	// Overdue counts the overdue features of the group.
+	Overdue int
+}
+
+// LifecycleReport compares features against the lifetime of their type.
+type LifecycleReport struct {
+	GeneratedAt time.Time
+	// Groups are sorted by project and type; features within a group are
+	// sorted by name.
+	Groups []LifecycleGroup
+}
+
+// Overdue returns the overdue features of every group, most overdue first.
+func (r *LifecycleReport) Overdue() []FeatureLifecycle {
+	var overdue []FeatureLifecycle
+	for _, group := range r.Groups {
+		for _, feature := range group.Features {
+			if feature.Overdue {
+				overdue = append(overdue, feature)
+			}
+		}
+	}
+	sort.SliceStable(overdue, func(i, j int) bool {
+		return overdue[i].DaysOverdue > overdue[j].DaysOverdue
+	})
+	return overdue
+}
+
+// GetLifecycleReport lists the features of the selected projects with the
+// lifetime of their type from GetAllFeatureTypes, flagging the ones that
+// outlived it. Features of unknown types are treated as permanent.
+func (p *FeatureTogglesService) GetLifecycleReport(opts LifecycleOptions) (*LifecycleReport, *Response, error) {
+	return p.GetLifecycleReportWithContext(context.Background(), opts)
+}
+
+func (p *FeatureTogglesService) GetLifecycleReportWithContext(ctx context.Context, opts LifecycleOptions) (*LifecycleReport, *Response, error) {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	types, resp, err := p.client.FeatureTypes.GetAllFeatureTypesWithContext(ctx)
+	if err != nil {
+		return nil, resp, err
+	}
+	lifetimes := make(map[string]int, len(types.Types))
+	for _, featureType := range types.Types {
+		lifetimes[featureType.ID] = featureType.LifetimeDays
+	}
+
+	projects := opts.Projects
+	if len(projects) == 0 {
+		all, resp, err := p.client.Projects.GetAllProjectsWithContext(ctx)
+		if err != nil {
+			return nil, resp, err
+		}
+		for _, project := range *all {
+			projects = append(projects, project.Id)
+		}
+	}
+
+	groups := make(map[[2]string]*LifecycleGroup)
+	for _, projectId := range projects {
+		var features *[]FeatureToggle
+		if features, resp, err = p.GetFeaturesByProjectWithContext(ctx, projectId); err != nil {
+			return nil, resp, err
+		}
+		for _, feature := range *features {
+			lifecycle := featureLifecycle(feature, projectId, lifetimes[feature.Type], now)
+			key := [2]string{projectId, feature.Type}
+			group, ok := groups[key]
+			if !ok {
+				group = &LifecycleGroup{Project: projectId, Type: feature.Type, LifetimeDays: lifecycle.LifetimeDays}
+				groups[key] = group
+			}
+			group.Features = append(group.Features, lifecycle)
+			if lifecycle.Overdue {
+				group.Overdue++
+			}
+		}
+	}
+
+	report := &LifecycleReport{GeneratedAt: now}
+	for _, group := range groups {
+		sort.Slice(group.Features, func(i, j int) bool {
+			return group.Features[i].Name < group.Features[j].Name
+		})
+		report.Groups = append(report.Groups, *group)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		a, b := report.Groups[i], report.Groups[j]
+		if a.Project != b.Project {
+			return a.Project < b.Project
+		}
+		return a.Type < b.Type
+	})
+	return report, resp, nil
+}
+
+func featureLifecycle(feature FeatureToggle, projectId string, lifetimeDays int, now time.Time) FeatureLifecycle {
+	lifecycle := FeatureLifecycle{
+		Name:         feature.Name,
+		Project:      projectId,
+		Type:         feature.Type,
+		Stale:        feature.Stale,
+		LifetimeDays: lifetimeDays,
+	}
+	createdAt, err := time.Parse(time.RFC3339, feature.CreatedAt)
+	if err != nil {
+		return lifecycle
+	}
+	lifecycle.CreatedAt = createdAt
+	if lifetimeDays <= 0 {
+		return lifecycle
+	}
+	lifecycle.DueAt = createdAt.AddDate(0, 0, lifetimeDays)
+	if now.After(lifecycle.DueAt) {
+		lifecycle.Overdue = true
+		lifecycle.DaysOverdue = int(now.Sub(lifecycle.DueAt) / (24 * time.Hour))
+	}
+	return lifecycle
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestFeatureTogglesService_GetLifecycleReport(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodGet, "admin/feature-types").Reply(http.StatusOK, `{"version":1,"types":[{"id":"release","lifetimeDays":40}]}`)
+	mock.On(http.MethodGet, "admin/projects").Reply(http.StatusOK, `{"version":1,"projects":[{"id":"default"}]}`)
+	mock.On(http.MethodGet, "admin/projects/default/features").Reply(http.StatusOK, `{"version":1,"features":[
+		{"name":"checkout","type":"release","createdAt":"2024-01-01T12:00:00Z","stale":true},
+		{"name":"legacy","type":"release"},
+		{"name":"custom","type":"migration","createdAt":"2020-01-01T00:00:00Z"}
+	]}`)
+
+	now := time.Date(2024, 2, 12, 0, 0, 0, 0, time.UTC)
+	report, _, err := client.FeatureToggles.GetLifecycleReport(LifecycleOptions{Now: now})
+	if err != nil {
+		t.Fatalf("GetLifecycleReport() error = %v", err)
+	}
+	if len(report.Groups) != 2 || report.Groups[0].Type != "migration" || report.Groups[1].LifetimeDays != 40 {
+		t.Fatalf("GetLifecycleReport() groups = %+v", report.Groups)
+	}
+
+	custom := report.Groups[0].Features[0]
+	if custom.Overdue || custom.LifetimeDays != 0 || !custom.DueAt.IsZero() {
+		t.Errorf("feature of an unknown type = %+v, want permanent", custom)
+	}
+	checkout, legacy := report.Groups[1].Features[0], report.Groups[1].Features[1]
+	if !checkout.Overdue || checkout.DaysOverdue != 1 || !checkout.Stale || !checkout.DueAt.Equal(time.Date(2024, 2, 10, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("checkout = %+v, want overdue by one day", checkout)
+	}
+	if legacy.Overdue || !legacy.CreatedAt.IsZero() {
+		t.Errorf("feature without a creation time = %+v, want not overdue", legacy)
+	}
+}
+
+func TestFeatureTogglesService_MarkFeaturesStale(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodPost, "admin/projects/default/stale").Reply(http.StatusAccepted, "")
+
+	if _, _, err := client.FeatureToggles.MarkFeaturesStale("default", nil, true); err == nil {
+		t.Error("MarkFeaturesStale() without features succeeded")
+	}
+	ok, _, err := client.FeatureToggles.MarkFeaturesStale("default", []string{"a", "b"}, false)
+	if err != nil || !ok {
+		t.Fatalf("MarkFeaturesStale() = %v, %v", ok, err)
+	}
+	var body staleFeaturesBody
+	if err := mock.Requests()[0].DecodeBody(&body); err != nil || len(body.Features) != 2 || body.Stale {
+		t.Errorf("MarkFeaturesStale() sent %+v, %v", body, err)
+	}
+	if len(mock.Requests()[0].JSON.(map[string]interface{})) != 2 {
+		t.Errorf("MarkFeaturesStale() sent %v, want features and stale", mock.Requests()[0].JSON)
+	}
+}
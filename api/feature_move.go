@@ -0,0 +1,134 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// FeatureMoveError describes a feature MoveFeatures did not move.
+type FeatureMoveError struct {
+	Feature string
+	// MissingEnvironments lists the environments of the feature that the
+	// target project does not have. Features with missing environments are
+	// not sent to Unleash.
+	MissingEnvironments []string
+	// Err is the error Unleash answered with, if the move was attempted.
+	Err error
+}
+
+func (e FeatureMoveError) Error() string {
+	if e.Err != nil {
+		return e.Feature + ": " + e.Err.Error()
+	}
+	return fmt.Sprintf("%s: target project lacks environments %v", e.Feature, e.MissingEnvironments)
+}
+
+func (e FeatureMoveError) Unwrap() error {
+	return e.Err
+}
+
+// MoveFeaturesResult reports what MoveFeatures did with every selected feature.
+type MoveFeaturesResult struct {
+	Moved []string
+	// Conflicts are features the target project cannot hold, either found
+	// beforehand or reported by Unleash.
+	Conflicts []FeatureMoveError
+	// Failures are features Unleash refused to move for other reasons.
+	Failures []FeatureMoveError
+}
+
+type changeProjectBody struct {
+	NewProjectId string `json:"newProjectId"`
+}
+
+// ChangeFeatureProject moves a feature to another project.
+func (p *FeatureTogglesService) ChangeFeatureProject(projectId string, featureName string, newProjectId string) (bool, *Response, error) {
+	return p.ChangeFeatureProjectWithContext(context.Background(), projectId, featureName, newProjectId)
+}
+
+func (p *FeatureTogglesService) ChangeFeatureProjectWithContext(ctx context.Context, projectId string, featureName string, newProjectId string) (bool, *Response, error) {
+	if featureName == "" {
+		return false, nil, ErrRequiredParam("featureName")
+	}
+	if newProjectId == "" {
+		return false, nil, ErrRequiredParam("newProjectId")
+	}
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/features/"+featureName+"/changeProject", "POST", changeProjectBody{NewProjectId: newProjectId})
+	if err != nil {
+		return false, nil, err
+	}
+
+	var changeResponse bytes.Buffer
+
+	resp, err := p.client.do(req, &changeResponse)
+	if err != nil {
+		return false, resp, err
+	}
+	return true, resp, nil
+}
+
+// MoveFeatures moves the features of a project for which filter returns true,
+// or all of them when filter is nil, to another project. Features having an
+// environment the target project lacks are reported as conflicts without
+// being sent. A failed move does not stop the others; when any feature was not
+// moved the result is returned with an error wrapping ErrIncompleteMove.
+func (p *FeatureTogglesService) MoveFeatures(projectId string, newProjectId string, filter func(FeatureToggle) bool) (*MoveFeaturesResult, *Response, error) {
+	return p.MoveFeaturesWithContext(context.Background(), projectId, newProjectId, filter)
+}
+
+func (p *FeatureTogglesService) MoveFeaturesWithContext(ctx context.Context, projectId string, newProjectId string, filter func(FeatureToggle) bool) (*MoveFeaturesResult, *Response, error) {
+	if newProjectId == "" {
+		return nil, nil, ErrRequiredParam("newProjectId")
+	}
+	target, resp, err := p.client.Projects.GetProjectByIdWithContext(ctx, newProjectId)
+	if err != nil {
+		return nil, resp, err
+	}
+	available := make(map[string]bool, len(target.Environments))
+	for _, env := range target.Environments {
+		available[env.Environment] = true
+	}
+
+	features, resp, err := p.GetFeaturesByProjectWithContext(ctx, projectId)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	result := &MoveFeaturesResult{}
+	for _, feature := range *features {
+		if filter != nil && !filter(feature) {
+			continue
+		}
+		var missing []string
+		for _, env := range feature.Environments {
+			if !available[env.Name] {
+				missing = append(missing, env.Name)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			result.Conflicts = append(result.Conflicts, FeatureMoveError{Feature: feature.Name, MissingEnvironments: missing})
+			continue
+		}
+
+		if _, resp, err = p.ChangeFeatureProjectWithContext(ctx, projectId, feature.Name, newProjectId); err != nil {
+			moveErr := FeatureMoveError{Feature: feature.Name, Err: err}
+			var apiErr *APIError
+			if errors.Is(err, ErrConflict) || errors.As(err, &apiErr) && apiErr.Name == "IncompatibleProjectError" {
+				result.Conflicts = append(result.Conflicts, moveErr)
+			} else {
+				result.Failures = append(result.Failures, moveErr)
+			}
+			continue
+		}
+		result.Moved = append(result.Moved, feature.Name)
+	}
+
+	if n := len(result.Conflicts) + len(result.Failures); n > 0 {
+		return result, resp, fmt.Errorf("%w: %d of %d features not moved", ErrIncompleteMove, n, n+len(result.Moved))
+	}
+	return result, resp, nil
+}
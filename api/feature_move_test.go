@@ -0,0 +1,53 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestFeatureTogglesService_MoveFeatures_ReportsEachFeature(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodGet, "admin/projects/payments").Reply(http.StatusOK, `{
+		"name": "Payments", "environments": [{"environment": "development"}, {"environment": "production"}]
+	}`)
+	mock.On(http.MethodGet, "admin/projects/default/features").Reply(http.StatusOK, `{"version": 1, "features": [
+		{"name": "checkout", "environments": [{"name": "development"}, {"name": "production"}]},
+		{"name": "refunds", "environments": [{"name": "development"}]},
+		{"name": "ledger", "environments": [{"name": "production"}]},
+		{"name": "canary", "environments": [{"name": "canary"}, {"name": "staging"}]}
+	]}`)
+	mock.On(http.MethodPost, "admin/projects/default/features/checkout/changeProject").Reply(http.StatusOK, "")
+	mock.On(http.MethodPost, "admin/projects/default/features/refunds/changeProject").
+		Reply(http.StatusConflict, `{"name":"IncompatibleProjectError","message":"Changing project not allowed."}`)
+	mock.On(http.MethodPost, "admin/projects/default/features/ledger/changeProject").
+		Reply(http.StatusForbidden, `{"name":"NoAccessError","message":"You need permission MOVE_FEATURE_TOGGLE"}`)
+
+	result, _, err := client.FeatureToggles.MoveFeatures("default", "payments", nil)
+	if !errors.Is(err, ErrIncompleteMove) {
+		t.Fatalf("MoveFeatures() error = %v, want ErrIncompleteMove", err)
+	}
+
+	if !reflect.DeepEqual(result.Moved, []string{"checkout"}) {
+		t.Errorf("Moved = %v, want [checkout]", result.Moved)
+	}
+	if len(result.Conflicts) != 2 || result.Conflicts[0].Feature != "refunds" || !errors.Is(result.Conflicts[0], ErrConflict) {
+		t.Fatalf("Conflicts = %+v", result.Conflicts)
+	}
+	if canary := result.Conflicts[1]; canary.Feature != "canary" || canary.Err != nil ||
+		!reflect.DeepEqual(canary.MissingEnvironments, []string{"canary", "staging"}) {
+		t.Errorf("Conflicts[1] = %+v, want canary missing canary and staging", canary)
+	}
+	if len(result.Failures) != 1 || result.Failures[0].Feature != "ledger" || !errors.Is(result.Failures[0], ErrForbidden) {
+		t.Errorf("Failures = %+v", result.Failures)
+	}
+	if n := len(mock.RequestsTo(http.MethodPost, "admin/projects/default/features/canary/changeProject")); n != 0 {
+		t.Errorf("canary was sent %d times despite the missing environments", n)
+	}
+
+	var body changeProjectBody
+	if err := mock.RequestsTo(http.MethodPost, "admin/projects/default/features/checkout/changeProject")[0].DecodeBody(&body); err != nil || body.NewProjectId != "payments" {
+		t.Errorf("changeProject body = %+v, %v", body, err)
+	}
+}
@@ -0,0 +1,244 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// JSON Patch operations, see RFC 6902.
+const (
+	PatchAdd     = "add"
+	PatchRemove  = "remove"
+	PatchReplace = "replace"
+	PatchMove    = "move"
+	PatchCopy    = "copy"
+	PatchTest    = "test"
+)
+
+// PatchOperation is a single RFC 6902 operation. Path and From are JSON
+// pointers such as /description or /parameters/rollout.
+type PatchOperation struct {
+	Op    string
+	Path  string
+	From  string
+	Value interface{}
+}
+
+// MarshalJSON always sends the value of add, replace and test operations, so
+// a patch can set a field to false, zero or null.
+func (o PatchOperation) MarshalJSON() ([]byte, error) {
+	op := struct {
+		Op    string       `json:"op"`
+		Path  string       `json:"path"`
+		From  string       `json:"from,omitempty"`
+		Value *interface{} `json:"value,omitempty"`
+	}{Op: o.Op, Path: o.Path, From: o.From}
+	switch o.Op {
+	case PatchAdd, PatchReplace, PatchTest:
+		op.Value = &o.Value
+	}
+	return json.Marshal(op)
+}
+
+func (o *PatchOperation) UnmarshalJSON(data []byte) error {
+	var op struct {
+		Op    string      `json:"op"`
+		Path  string      `json:"path"`
+		From  string      `json:"from"`
+		Value interface{} `json:"value"`
+	}
+	if err := json.Unmarshal(data, &op); err != nil {
+		return err
+	}
+	*o = PatchOperation{Op: op.Op, Path: op.Path, From: op.From, Value: op.Value}
+	return nil
+}
+
+// EscapePointerToken escapes a key for use as a JSON pointer segment.
+func EscapePointerToken(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// DiffFeature returns the operations that turn the patchable fields of old,
+// its description, type and stale flag, into those of new. Environments,
+// strategies and variants have endpoints of their own and are not compared.
+func DiffFeature(old FeatureToggle, new FeatureToggle) []PatchOperation {
+	ops := []PatchOperation{}
+	if old.Description != new.Description {
+		ops = append(ops, PatchOperation{Op: PatchReplace, Path: "/description", Value: new.Description})
+	}
+	if old.Type != new.Type {
+		ops = append(ops, PatchOperation{Op: PatchReplace, Path: "/type", Value: new.Type})
+	}
+	if old.Stale != new.Stale {
+		ops = append(ops, PatchOperation{Op: PatchReplace, Path: "/stale", Value: new.Stale})
+	}
+	return ops
+}
+
+// DiffFeatureStrategy returns the operations that turn old into new.
+// Parameters are compared one by one; constraints, segments and variants are
+// compared as a whole and sent as add operations, which replace existing
+// values and also work when the stored strategy omits them. It fails when the
+// parameters of either strategy cannot be decoded.
+func DiffFeatureStrategy(old FeatureStrategy, new FeatureStrategy) ([]PatchOperation, error) {
+	ops := []PatchOperation{}
+	if old.Name != new.Name {
+		ops = append(ops, PatchOperation{Op: PatchReplace, Path: "/name", Value: new.Name})
+	}
+	if old.SortOrder != new.SortOrder {
+		ops = append(ops, PatchOperation{Op: PatchReplace, Path: "/sortOrder", Value: new.SortOrder})
+	}
+
+	oldParams, err := parameterMap(old)
+	if err != nil {
+		return nil, err
+	}
+	newParams, err := parameterMap(new)
+	if err != nil {
+		return nil, err
+	}
+	if len(oldParams) == 0 && len(newParams) > 0 {
+		ops = append(ops, PatchOperation{Op: PatchAdd, Path: "/parameters", Value: newParams})
+	} else {
+		for _, name := range sortedParamNames(oldParams, newParams) {
+			oldValue, inOld := oldParams[name]
+			newValue, inNew := newParams[name]
+			path := "/parameters/" + EscapePointerToken(name)
+			switch {
+			case !inNew:
+				ops = append(ops, PatchOperation{Op: PatchRemove, Path: path})
+			case !inOld:
+				ops = append(ops, PatchOperation{Op: PatchAdd, Path: path, Value: newValue})
+			case oldValue != newValue:
+				ops = append(ops, PatchOperation{Op: PatchReplace, Path: path, Value: newValue})
+			}
+		}
+	}
+
+	if !jsonEqual(old.Constraints, new.Constraints) {
+		constraints := new.Constraints
+		if constraints == nil {
+			constraints = []Constraint{}
+		}
+		ops = append(ops, PatchOperation{Op: PatchAdd, Path: "/constraints", Value: constraints})
+	}
+	if !jsonEqual(old.Segments, new.Segments) {
+		segments := new.Segments
+		if segments == nil {
+			segments = []int{}
+		}
+		ops = append(ops, PatchOperation{Op: PatchAdd, Path: "/segments", Value: segments})
+	}
+	if !jsonEqual(old.Variants, new.Variants) {
+		variants := new.Variants
+		if variants == nil {
+			variants = []Variant{}
+		}
+		ops = append(ops, PatchOperation{Op: PatchAdd, Path: "/variants", Value: variants})
+	}
+	return ops, nil
+}
+
+func parameterMap(strategy FeatureStrategy) (map[string]string, error) {
+	var raw map[string]paramValue
+	if err := strategy.DecodeParameters(&raw); err != nil {
+		return nil, err
+	}
+	params := make(map[string]string, len(raw))
+	for name, value := range raw {
+		params[name] = string(value)
+	}
+	return params, nil
+}
+
+func sortedParamNames(a map[string]string, b map[string]string) []string {
+	var names []string
+	for name := range a {
+		names = append(names, name)
+	}
+	for name := range b {
+		if _, ok := a[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// jsonEqual treats nil and empty slices alike, as Unleash does.
+func jsonEqual(a interface{}, b interface{}) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	if string(ja) == "null" {
+		ja = []byte("[]")
+	}
+	if string(jb) == "null" {
+		jb = []byte("[]")
+	}
+	return bytes.Equal(ja, jb)
+}
+
+// PatchFeature applies JSON Patch operations to a feature, changing only the
+// fields they name. An empty patch is not sent; the feature is fetched
+// instead.
+func (p *FeatureTogglesService) PatchFeature(projectId string, featureName string, ops []PatchOperation) (*FeatureToggle, *Response, error) {
+	return p.PatchFeatureWithContext(context.Background(), projectId, featureName, ops)
+}
+
+func (p *FeatureTogglesService) PatchFeatureWithContext(ctx context.Context, projectId string, featureName string, ops []PatchOperation) (*FeatureToggle, *Response, error) {
+	if featureName == "" {
+		return nil, nil, ErrRequiredParam("featureName")
+	}
+	if len(ops) == 0 {
+		return p.GetFeatureByNameWithContext(ctx, projectId, featureName)
+	}
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/features/"+featureName, "PATCH", ops)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var feature FeatureToggle
+
+	resp, err := p.client.do(req, &feature)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &feature, resp, err
+}
+
+// PatchFeatureStrategy applies JSON Patch operations to a strategy of a
+// feature in an environment. An empty patch is not sent; the strategy is
+// fetched instead.
+func (p *FeatureTogglesService) PatchFeatureStrategy(projectId string, featureName string, environment string, strategyId string, ops []PatchOperation) (*FeatureStrategy, *Response, error) {
+	return p.PatchFeatureStrategyWithContext(context.Background(), projectId, featureName, environment, strategyId, ops)
+}
+
+func (p *FeatureTogglesService) PatchFeatureStrategyWithContext(ctx context.Context, projectId string, featureName string, environment string, strategyId string, ops []PatchOperation) (*FeatureStrategy, *Response, error) {
+	if strategyId == "" {
+		return nil, nil, ErrRequiredParam("strategyId")
+	}
+	method := "PATCH"
+	var body interface{} = ops
+	if len(ops) == 0 {
+		method, body = "GET", nil
+	}
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/features/"+featureName+"/environments/"+environment+"/strategies/"+strategyId, method, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var strategy FeatureStrategy
+
+	resp, err := p.client.do(req, &strategy)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &strategy, resp, err
+}
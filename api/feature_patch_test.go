@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestPatchOperation_MarshalJSON(t *testing.T) {
+	ops := []PatchOperation{
+		{Op: PatchReplace, Path: "/stale", Value: false},
+		{Op: PatchRemove, Path: "/parameters/groupId"},
+		{Op: PatchMove, From: "/a", Path: "/b"},
+	}
+	data, err := json.Marshal(ops)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	want := `[{"op":"replace","path":"/stale","value":false},{"op":"remove","path":"/parameters/groupId"},{"op":"move","path":"/b","from":"/a"}]`
+	if string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+}
+
+func TestDiffFeature(t *testing.T) {
+	old := FeatureToggle{Name: "checkout", Description: "Old", Type: "release", Stale: true}
+	new := old
+	new.Description = ""
+	new.Stale = false
+	new.Environments = []Environment{{Name: "production", Enabled: true}}
+
+	want := []PatchOperation{
+		{Op: PatchReplace, Path: "/description", Value: ""},
+		{Op: PatchReplace, Path: "/stale", Value: false},
+	}
+	if got := DiffFeature(old, new); !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffFeature() = %+v, want %+v", got, want)
+	}
+	if got := DiffFeature(old, old); len(got) != 0 {
+		t.Errorf("DiffFeature() of equal features = %+v, want none", got)
+	}
+}
+
+func TestDiffFeatureStrategy(t *testing.T) {
+	old := FeatureStrategy{
+		ID:         "1",
+		Name:       StrategyFlexibleRollout,
+		Parameters: map[string]interface{}{"rollout": 25, "stickiness": "default", "a/b": "x"},
+	}
+	new := FeatureStrategy{
+		ID:          "1",
+		Name:        StrategyFlexibleRollout,
+		Parameters:  FlexibleRolloutParams{Rollout: 50, Stickiness: "default", GroupId: "checkout"},
+		Constraints: []Constraint{{ContextName: "userId", Operator: OperatorIn, Values: []string{"1"}}},
+		SortOrder:   2,
+	}
+
+	got, err := DiffFeatureStrategy(old, new)
+	if err != nil {
+		t.Fatalf("DiffFeatureStrategy() error = %v", err)
+	}
+	want := []PatchOperation{
+		{Op: PatchReplace, Path: "/sortOrder", Value: 2},
+		{Op: PatchRemove, Path: "/parameters/a~1b"},
+		{Op: PatchAdd, Path: "/parameters/groupId", Value: "checkout"},
+		{Op: PatchReplace, Path: "/parameters/rollout", Value: "50"},
+		{Op: PatchAdd, Path: "/constraints", Value: new.Constraints},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffFeatureStrategy() = %+v, want %+v", got, want)
+	}
+
+	unchanged := old
+	unchanged.Constraints = []Constraint{}
+	if got, _ := DiffFeatureStrategy(old, unchanged); len(got) != 0 {
+		t.Errorf("DiffFeatureStrategy() with empty instead of nil constraints = %+v, want none", got)
+	}
+}
+
+func TestFeatureTogglesService_PatchFeature(t *testing.T) {
+	mock, client := newTestClient(t)
+	patch := mock.On(http.MethodPatch, "admin/projects/default/features/checkout").Reply(http.StatusOK, `{"name":"checkout","stale":true}`)
+	get := mock.On(http.MethodGet, "admin/projects/default/features/checkout").Reply(http.StatusOK, `{"name":"checkout"}`)
+
+	feature, _, err := client.FeatureToggles.PatchFeature("default", "checkout", []PatchOperation{{Op: PatchReplace, Path: "/stale", Value: true}})
+	if err != nil || !feature.Stale {
+		t.Fatalf("PatchFeature() = %+v, %v", feature, err)
+	}
+	sent := mock.Requests()[0]
+	if sent.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("PatchFeature() sent Content-Type %q", sent.Header.Get("Content-Type"))
+	}
+	var ops []PatchOperation
+	if err := sent.DecodeBody(&ops); err != nil || len(ops) != 1 || ops[0].Path != "/stale" || ops[0].Value != true {
+		t.Errorf("PatchFeature() sent %+v, %v", ops, err)
+	}
+
+	if _, _, err := client.FeatureToggles.PatchFeature("default", "checkout", nil); err != nil {
+		t.Fatalf("PatchFeature() with no operations error = %v", err)
+	}
+	if patch.Calls() != 1 || get.Calls() != 1 {
+		t.Errorf("an empty patch made %d PATCH and %d GET requests, want 0 and 1", patch.Calls()-1, get.Calls())
+	}
+}
@@ -0,0 +1,227 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Kinds of PromotionChange.
+const (
+	PromotionAddStrategy    = "add-strategy"
+	PromotionUpdateStrategy = "update-strategy"
+	PromotionDeleteStrategy = "delete-strategy"
+	PromotionSetEnabled     = "set-enabled"
+)
+
+// PromoteOptions configures PromoteFeature.
+type PromoteOptions struct {
+	// CopyEnabledState enables or disables the target environment like the
+	// source environment.
+	CopyEnabledState bool
+	// DryRun plans the changes without applying them.
+	DryRun bool
+}
+
+// PromotionChange is one step of a promotion. Strategies are matched by their
+// position in the evaluation order of both environments.
+type PromotionChange struct {
+	Kind string
+	// Strategy is the target strategy after the change, nil for deletions
+	// and state changes.
+	Strategy *FeatureStrategy
+	// Previous is the target strategy before the change, nil for additions
+	// and state changes.
+	Previous *FeatureStrategy
+	// Diff lists what an update changes.
+	Diff []PatchOperation
+	// Enabled is the new state of the target environment for
+	// PromotionSetEnabled.
+	Enabled bool
+}
+
+func (c PromotionChange) String() string {
+	switch c.Kind {
+	case PromotionAddStrategy:
+		return "add strategy " + c.Strategy.Name
+	case PromotionUpdateStrategy:
+		return fmt.Sprintf("update strategy %s (%s), %d changes", c.Previous.Name, c.Previous.ID, len(c.Diff))
+	case PromotionDeleteStrategy:
+		return "delete strategy " + c.Previous.Name + " (" + c.Previous.ID + ")"
+	case PromotionSetEnabled:
+		if c.Enabled {
+			return "enable the environment"
+		}
+		return "disable the environment"
+	}
+	return c.Kind
+}
+
+// PromoteResult describes a promotion planned or applied by PromoteFeature.
+type PromoteResult struct {
+	// Changes are the planned steps, in the order they are applied. It is
+	// empty when the environments already match.
+	Changes []PromotionChange
+	// Applied counts the changes that were applied and not rolled back.
+	Applied int
+	// RolledBack reports whether a failed promotion was undone.
+	RolledBack bool
+	// RollbackErrors lists the undo steps that failed, leaving the target
+	// environment partially promoted.
+	RollbackErrors []error
+}
+
+// PromoteFeature copies the strategies of a feature, with their constraints,
+// parameters, segments and variants, from one environment to another, and
+// optionally the enabled state. Target strategies are updated in place where
+// possible, extra ones are deleted. With DryRun the changes are only planned.
+// When a step fails the applied ones are undone and the step error is
+// returned; RollbackErrors lists undo steps that failed in turn.
+func (p *FeatureTogglesService) PromoteFeature(projectId string, featureName string, sourceEnvironment string, targetEnvironment string, opts PromoteOptions) (*PromoteResult, *Response, error) {
+	return p.PromoteFeatureWithContext(context.Background(), projectId, featureName, sourceEnvironment, targetEnvironment, opts)
+}
+
+func (p *FeatureTogglesService) PromoteFeatureWithContext(ctx context.Context, projectId string, featureName string, sourceEnvironment string, targetEnvironment string, opts PromoteOptions) (*PromoteResult, *Response, error) {
+	if sourceEnvironment == "" {
+		return nil, nil, ErrRequiredParam("sourceEnvironment")
+	}
+	if targetEnvironment == "" {
+		return nil, nil, ErrRequiredParam("targetEnvironment")
+	}
+	if sourceEnvironment == targetEnvironment {
+		return nil, nil, errors.New("source and target environment must differ")
+	}
+	feature, resp, err := p.GetFeatureByNameWithContext(ctx, projectId, featureName)
+	if err != nil {
+		return nil, resp, err
+	}
+	var source, target *Environment
+	for i := range feature.Environments {
+		switch feature.Environments[i].Name {
+		case sourceEnvironment:
+			source = &feature.Environments[i]
+		case targetEnvironment:
+			target = &feature.Environments[i]
+		}
+	}
+	if source == nil || target == nil {
+		missing := sourceEnvironment
+		if source != nil {
+			missing = targetEnvironment
+		}
+		return nil, resp, fmt.Errorf("feature %s has no environment %s: %w", featureName, missing, ErrNotFound)
+	}
+
+	changes, err := planPromotion(*source, *target, opts)
+	if err != nil {
+		return nil, resp, err
+	}
+	result := &PromoteResult{Changes: changes}
+	if opts.DryRun {
+		return result, resp, nil
+	}
+
+	var undo []func() error
+	for _, change := range changes {
+		var rollback func() error
+		if rollback, resp, err = p.applyPromotionChange(ctx, projectId, featureName, targetEnvironment, change, target.Enabled); err != nil {
+			for i := len(undo) - 1; i >= 0; i-- {
+				if undoErr := undo[i](); undoErr != nil {
+					result.RollbackErrors = append(result.RollbackErrors, undoErr)
+				}
+			}
+			result.RolledBack = true
+			result.Applied = 0
+			if len(result.RollbackErrors) > 0 {
+				return result, resp, fmt.Errorf("%s: %w; %d rollback steps failed", change, err, len(result.RollbackErrors))
+			}
+			return result, resp, fmt.Errorf("%s: %w", change, err)
+		}
+		undo = append(undo, rollback)
+		result.Applied++
+	}
+	return result, resp, nil
+}
+
+// planPromotion pairs the strategies of both environments by position. Paired
+// strategies keep the sort order of the target, which may number them
+// differently from the source.
+func planPromotion(source Environment, target Environment, opts PromoteOptions) ([]PromotionChange, error) {
+	sourceStrategies := SortedStrategies(source.Strategies)
+	targetStrategies := SortedStrategies(target.Strategies)
+
+	changes := []PromotionChange{}
+	for i, strategy := range sourceStrategies {
+		desired := strategy
+		desired.ID = ""
+		if i >= len(targetStrategies) {
+			changes = append(changes, PromotionChange{Kind: PromotionAddStrategy, Strategy: &desired})
+			continue
+		}
+		previous := targetStrategies[i]
+		desired.ID = previous.ID
+		desired.SortOrder = previous.SortOrder
+		diff, err := DiffFeatureStrategy(previous, desired)
+		if err != nil {
+			return nil, err
+		}
+		if len(diff) > 0 {
+			changes = append(changes, PromotionChange{Kind: PromotionUpdateStrategy, Strategy: &desired, Previous: &previous, Diff: diff})
+		}
+	}
+	for i := len(sourceStrategies); i < len(targetStrategies); i++ {
+		previous := targetStrategies[i]
+		changes = append(changes, PromotionChange{Kind: PromotionDeleteStrategy, Previous: &previous})
+	}
+	if opts.CopyEnabledState && source.Enabled != target.Enabled {
+		changes = append(changes, PromotionChange{Kind: PromotionSetEnabled, Enabled: source.Enabled})
+	}
+	return changes, nil
+}
+
+// applyPromotionChange applies a change to the target environment and returns
+// the function undoing it.
+func (p *FeatureTogglesService) applyPromotionChange(ctx context.Context, projectId string, featureName string, environment string, change PromotionChange, wasEnabled bool) (func() error, *Response, error) {
+	switch change.Kind {
+	case PromotionAddStrategy:
+		added, resp, err := p.AddStrategyToFeatureWithContext(ctx, projectId, featureName, environment, *change.Strategy)
+		if err != nil {
+			return nil, resp, err
+		}
+		return func() error {
+			_, _, err := p.DeleteStrategyFromFeatureWithContext(ctx, projectId, featureName, environment, added.ID)
+			return err
+		}, resp, nil
+	case PromotionUpdateStrategy:
+		_, resp, err := p.UpdateFeatureStrategyWithContext(ctx, projectId, featureName, environment, *change.Strategy)
+		if err != nil {
+			return nil, resp, err
+		}
+		return func() error {
+			_, _, err := p.UpdateFeatureStrategyWithContext(ctx, projectId, featureName, environment, *change.Previous)
+			return err
+		}, resp, nil
+	case PromotionDeleteStrategy:
+		_, resp, err := p.DeleteStrategyFromFeatureWithContext(ctx, projectId, featureName, environment, change.Previous.ID)
+		if err != nil {
+			return nil, resp, err
+		}
+		return func() error {
+			// Unleash assigns a new id to the restored strategy
+			restored := *change.Previous
+			restored.ID = ""
+			_, _, err := p.AddStrategyToFeatureWithContext(ctx, projectId, featureName, environment, restored)
+			return err
+		}, resp, nil
+	case PromotionSetEnabled:
+		_, resp, err := p.EnableFeatureOnEnvironmentWithContext(ctx, projectId, featureName, environment, change.Enabled)
+		if err != nil {
+			return nil, resp, err
+		}
+		return func() error {
+			_, _, err := p.EnableFeatureOnEnvironmentWithContext(ctx, projectId, featureName, environment, wasEnabled)
+			return err
+		}, resp, nil
+	}
+	return nil, nil, fmt.Errorf("unknown promotion change %q", change.Kind)
+}
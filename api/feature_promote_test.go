@@ -0,0 +1,80 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestFeatureTogglesService_PromoteFeature_RollsBack(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodGet, "admin/projects/default/features/checkout").Reply(http.StatusOK, `{"name":"checkout","environments":[
+		{"name":"production","enabled":false,"strategies":[
+			{"id":"p1","name":"default","sortOrder":0},
+			{"id":"p2","name":"default","sortOrder":1}
+		]},
+		{"name":"staging","enabled":true,"strategies":[
+			{"id":"s1","name":"userWithId","parameters":{"userIds":"1,2"},"segments":[3],"sortOrder":0}
+		]}
+	]}`)
+	update := mock.On(http.MethodPut, "admin/projects/default/features/checkout/environments/production/strategies/p1").
+		Reply(http.StatusOK, `{"id":"p1","name":"userWithId"}`).
+		Reply(http.StatusOK, `{"id":"p1","name":"default"}`)
+	mock.On(http.MethodDelete, "admin/projects/default/features/checkout/environments/production/strategies/p2").
+		Reply(http.StatusInternalServerError, `{"name":"InternalError","message":"boom"}`)
+
+	result, _, err := client.FeatureToggles.PromoteFeature("default", "checkout", "staging", "production", PromoteOptions{CopyEnabledState: true})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("PromoteFeature() error = %v, want the failed delete", err)
+	}
+	if !result.RolledBack || result.Applied != 0 || len(result.RollbackErrors) != 0 || len(result.Changes) != 3 {
+		t.Errorf("PromoteFeature() result = %+v", result)
+	}
+	if update.Calls() != 2 {
+		t.Fatalf("strategy p1 was updated %d times, want the change and its rollback", update.Calls())
+	}
+
+	var promoted, restored FeatureStrategy
+	updates := mock.RequestsTo(http.MethodPut, "admin/projects/default/features/checkout/environments/production/strategies/p1")
+	if err := updates[0].DecodeBody(&promoted); err != nil || promoted.Name != "userWithId" || len(promoted.Segments) != 1 {
+		t.Errorf("promoted strategy = %+v, %v", promoted, err)
+	}
+	if err := updates[1].DecodeBody(&restored); err != nil || restored.Name != "default" || restored.Segments != nil {
+		t.Errorf("restored strategy = %+v, %v", restored, err)
+	}
+	if n := len(mock.RequestsTo(http.MethodPost, "admin/projects/default/features/checkout/environments/production/on")); n != 0 {
+		t.Errorf("the environment was enabled %d times after the failure", n)
+	}
+}
+
+func TestFeatureTogglesService_PromoteFeature_IgnoresSortOrderOffsets(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodGet, "admin/projects/default/features/checkout").Reply(http.StatusOK, `{"name":"checkout","environments":[
+		{"name":"production","enabled":true,"strategies":[
+			{"id":"p1","name":"default","sortOrder":10},
+			{"id":"p2","name":"userWithId","parameters":{"userIds":"1"},"sortOrder":20}
+		]},
+		{"name":"staging","enabled":true,"strategies":[
+			{"id":"s1","name":"default","sortOrder":0},
+			{"id":"s2","name":"userWithId","parameters":{"userIds":"1,2"},"sortOrder":1}
+		]}
+	]}`)
+
+	result, _, err := client.FeatureToggles.PromoteFeature("default", "checkout", "staging", "production", PromoteOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("PromoteFeature() error = %v", err)
+	}
+	if len(result.Changes) != 1 {
+		t.Fatalf("PromoteFeature() changes = %v, want only the userIds update", result.Changes)
+	}
+	change := result.Changes[0]
+	if change.Kind != PromotionUpdateStrategy || change.Strategy.ID != "p2" || change.Strategy.SortOrder != 20 {
+		t.Errorf("change = %+v, want an update of p2 keeping sort order 20", change.Strategy)
+	}
+	for _, op := range change.Diff {
+		if op.Path == "/sortOrder" {
+			t.Errorf("diff = %v, want no sort order change", change.Diff)
+		}
+	}
+}
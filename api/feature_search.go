@@ -0,0 +1,184 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Feature states understood by SearchFeaturesOptions.State.
+const (
+	FeatureStateActive = "active"
+	FeatureStateStale  = "stale"
+)
+
+// EnvironmentStatus matches features that are enabled or disabled in an
+// environment.
+type EnvironmentStatus struct {
+	Environment string
+	Enabled     bool
+}
+
+// SearchFeaturesOptions filters SearchFeatures. Zero values do not filter;
+// lists match features having any of the given values.
+type SearchFeaturesOptions struct {
+	// Query matches features whose name or description contains it.
+	Query string
+	// NamePrefix matches features whose name starts with it. The endpoint
+	// only searches substrings, so the prefix is sent as the query when Query
+	// is empty and each page is filtered client-side. Pages may then hold
+	// fewer than Limit features, and Total counts the substring matches.
+	NamePrefix string
+	Projects   []string
+	Tags       []FeatureTag
+	Types      []string
+	// State is FeatureStateActive or FeatureStateStale.
+	State  string
+	Status []EnvironmentStatus
+	// CreatedAfter matches features created on or after its date.
+	CreatedAfter time.Time
+	// SortBy is a field such as name, type or createdAt; SortOrder is asc or desc.
+	SortBy    string
+	SortOrder string
+	Offset    int
+	Limit     int
+}
+
+// FeatureSearchResult is a page of features matching a search. Total counts
+// every match, not only the ones on the page.
+type FeatureSearchResult struct {
+	Features []FeatureToggle `json:"features"`
+	Total    int             `json:"total"`
+}
+
+// searchFeaturesQuery is the query string of admin/search/features, where
+// filters take the form OPERATOR:values.
+type searchFeaturesQuery struct {
+	Query     string `url:"query,omitempty"`
+	Project   string `url:"project,omitempty"`
+	Tag       string `url:"tag,omitempty"`
+	Type      string `url:"type,omitempty"`
+	State     string `url:"state,omitempty"`
+	Status    string `url:"status,omitempty"`
+	CreatedAt string `url:"createdAt,omitempty"`
+	SortBy    string `url:"sortBy,omitempty"`
+	SortOrder string `url:"sortOrder,omitempty"`
+	Offset    int    `url:"offset,omitempty"`
+	Limit     int    `url:"limit,omitempty"`
+}
+
+func (o SearchFeaturesOptions) query() searchFeaturesQuery {
+	q := searchFeaturesQuery{
+		Query:     o.Query,
+		Project:   anyOf("IS", o.Projects),
+		Type:      anyOf("IS", o.Types),
+		SortBy:    o.SortBy,
+		SortOrder: o.SortOrder,
+		Offset:    o.Offset,
+		Limit:     o.Limit,
+	}
+	if q.Query == "" {
+		q.Query = o.NamePrefix
+	}
+	if o.State != "" {
+		q.State = "IS:" + o.State
+	}
+
+	tags := make([]string, len(o.Tags))
+	for i, tag := range o.Tags {
+		tags[i] = tag.Type + ":" + tag.Value
+	}
+	q.Tag = anyOf("INCLUDE", tags)
+
+	statuses := make([]string, len(o.Status))
+	for i, status := range o.Status {
+		state := "disabled"
+		if status.Enabled {
+			state = "enabled"
+		}
+		statuses[i] = status.Environment + ":" + state
+	}
+	q.Status = anyOf("IS", statuses)
+
+	if !o.CreatedAfter.IsZero() {
+		q.CreatedAt = "IS_ON_OR_AFTER:" + o.CreatedAfter.Format("2006-01-02")
+	}
+	return q
+}
+
+// filter drops the features of a page that NamePrefix does not match.
+func (o SearchFeaturesOptions) filter(features []FeatureToggle) []FeatureToggle {
+	if o.NamePrefix == "" {
+		return features
+	}
+	matched := []FeatureToggle{}
+	for _, feature := range features {
+		if strings.HasPrefix(feature.Name, o.NamePrefix) {
+			matched = append(matched, feature)
+		}
+	}
+	return matched
+}
+
+// anyOf encodes values as an Unleash search filter, for example IS:a or
+// IS_ANY_OF:a,b.
+func anyOf(operator string, values []string) string {
+	switch len(values) {
+	case 0:
+		return ""
+	case 1:
+		return operator + ":" + values[0]
+	}
+	return operator + "_ANY_OF:" + strings.Join(values, ",")
+}
+
+// SearchFeatures searches features across all projects.
+func (p *FeatureTogglesService) SearchFeatures(opts SearchFeaturesOptions) (*FeatureSearchResult, *Response, error) {
+	return p.SearchFeaturesWithContext(context.Background(), opts)
+}
+
+func (p *FeatureTogglesService) SearchFeaturesWithContext(ctx context.Context, opts SearchFeaturesOptions) (*FeatureSearchResult, *Response, error) {
+	result, resp, err := p.searchFeatures(ctx, opts)
+	if err != nil {
+		return nil, resp, err
+	}
+	result.Features = opts.filter(result.Features)
+	return result, resp, nil
+}
+
+// searchFeatures fetches a page without applying the client-side filters.
+func (p *FeatureTogglesService) searchFeatures(ctx context.Context, opts SearchFeaturesOptions) (*FeatureSearchResult, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/search/features", "GET", opts.query())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var result FeatureSearchResult
+
+	resp, err := p.client.do(req, &result)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &result, resp, err
+}
+
+// IterateSearchFeatures pages through every feature matching opts, starting at
+// opts.Offset and requesting opts.Limit features per page.
+func (p *FeatureTogglesService) IterateSearchFeatures(opts SearchFeaturesOptions) *FeatureIterator {
+	return p.IterateSearchFeaturesWithContext(context.Background(), opts)
+}
+
+func (p *FeatureTogglesService) IterateSearchFeaturesWithContext(ctx context.Context, opts SearchFeaturesOptions) *FeatureIterator {
+	it := &FeatureIterator{}
+	it.pages = newPageIterator(ctx, opts.Offset, opts.Limit, func(ctx context.Context, offset int, limit int) (int, int, error) {
+		opts.Offset, opts.Limit = offset, limit
+		result, _, err := p.searchFeatures(ctx, opts)
+		if err != nil {
+			return 0, 0, err
+		}
+		// page by what the server returned, not by what the filters kept
+		it.page = opts.filter(result.Features)
+		return len(result.Features), result.Total, nil
+	})
+	return it
+}
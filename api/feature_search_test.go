@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/sighphyre/go-unleash-api/mocks"
+)
+
+func TestFeatureTogglesService_SearchFeatures(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodGet, "admin/search/features").
+		Reply(http.StatusOK, `{"features":[{"name":"checkout","project":"payments","tags":[{"type":"simple","value":"team-a"}]}],"total":12}`)
+
+	result, _, err := client.FeatureToggles.SearchFeatures(SearchFeaturesOptions{
+		Query:        "check",
+		Projects:     []string{"payments", "default"},
+		Tags:         []FeatureTag{{Type: "simple", Value: "team-a"}},
+		Types:        []string{"release"},
+		State:        FeatureStateStale,
+		Status:       []EnvironmentStatus{{Environment: "production", Enabled: true}},
+		CreatedAfter: time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC),
+		Offset:       10,
+		Limit:        5,
+	})
+	if err != nil {
+		t.Fatalf("SearchFeatures() error = %v", err)
+	}
+	if result.Total != 12 || len(result.Features) != 1 || result.Features[0].Tags[0].Value != "team-a" {
+		t.Errorf("SearchFeatures() got = %+v", result)
+	}
+
+	want := url.Values{
+		"query":     {"check"},
+		"project":   {"IS_ANY_OF:payments,default"},
+		"tag":       {"INCLUDE:simple:team-a"},
+		"type":      {"IS:release"},
+		"state":     {"IS:stale"},
+		"status":    {"IS:production:enabled"},
+		"createdAt": {"IS_ON_OR_AFTER:2024-03-01"},
+		"offset":    {"10"},
+		"limit":     {"5"},
+	}
+	if got := mock.Requests()[0].Query; got.Encode() != want.Encode() {
+		t.Errorf("query = %s, want %s", got.Encode(), want.Encode())
+	}
+}
+
+func TestFeatureTogglesService_SearchFeatures_NamePrefix(t *testing.T) {
+	pages := func() (*mocks.Client, *ApiClient) {
+		mock, client := newTestClient(t)
+		mock.On(http.MethodGet, "admin/search/features").
+			Reply(http.StatusOK, `{"features":[{"name":"checkout"},{"name":"legacy-checkout-v2"},{"name":"checkout-v2"}],"total":4}`).
+			Reply(http.StatusOK, `{"features":[{"name":"cart","description":"runs before checkout"}],"total":4}`)
+		return mock, client
+	}
+
+	mock, client := pages()
+	result, _, err := client.FeatureToggles.SearchFeatures(SearchFeaturesOptions{NamePrefix: "checkout", Limit: 3})
+	if err != nil {
+		t.Fatalf("SearchFeatures() error = %v", err)
+	}
+	if len(result.Features) != 2 || result.Features[0].Name != "checkout" || result.Features[1].Name != "checkout-v2" {
+		t.Errorf("SearchFeatures() features = %+v, want checkout and checkout-v2", result.Features)
+	}
+	if query := mock.Requests()[0].Query.Get("query"); query != "checkout" {
+		t.Errorf("query = %q, want the prefix", query)
+	}
+
+	mock, client = pages()
+	var names []string
+	err = client.FeatureToggles.IterateSearchFeatures(SearchFeaturesOptions{NamePrefix: "checkout", Limit: 3}).ForEach(func(feature FeatureToggle) error {
+		names = append(names, feature.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEach() error = %v", err)
+	}
+	if len(names) != 2 || names[0] != "checkout" || names[1] != "checkout-v2" {
+		t.Errorf("iterated over %v, want checkout and checkout-v2", names)
+	}
+	if offset := mock.Requests()[1].Query.Get("offset"); offset != "3" {
+		t.Errorf("second page offset = %s, want 3", offset)
+	}
+}
@@ -1,5 +1,7 @@
 package api
 
+import "context"
+
 type UpdateFeatureTagsBody struct {
 	AddedTags   []FeatureTag `json:"addedTags"`
 	RemovedTags []FeatureTag `json:"removedTags"`
@@ -20,7 +22,14 @@ type FeatureTagsService struct {
 }
 
 func (p *FeatureTagsService) GetAllFeatureTags(featureName string) (*FeatureTagsResponse, *Response, error) {
-	req, _ := p.client.newRequest("admin/features/"+featureName+"/tags", "GET", nil)
+	return p.GetAllFeatureTagsWithContext(context.Background(), featureName)
+}
+
+func (p *FeatureTagsService) GetAllFeatureTagsWithContext(ctx context.Context, featureName string) (*FeatureTagsResponse, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/features/"+featureName+"/tags", "GET", nil)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	var featureTags FeatureTagsResponse
 
@@ -32,7 +41,11 @@ func (p *FeatureTagsService) GetAllFeatureTags(featureName string) (*FeatureTags
 }
 
 func (p *FeatureTagsService) CreateFeatureTags(featureName string, tag FeatureTag) (*FeatureTag, *Response, error) {
-	req, err := p.client.newRequest("admin/features/"+featureName+"/tags", "POST", tag)
+	return p.CreateFeatureTagsWithContext(context.Background(), featureName, tag)
+}
+
+func (p *FeatureTagsService) CreateFeatureTagsWithContext(ctx context.Context, featureName string, tag FeatureTag) (*FeatureTag, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/features/"+featureName+"/tags", "POST", tag)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -48,12 +61,16 @@ func (p *FeatureTagsService) CreateFeatureTags(featureName string, tag FeatureTa
 }
 
 func (p *FeatureTagsService) UpdateFeatureTags(featureName string, addedTags []FeatureTag, removedTags []FeatureTag) (*FeatureTagsResponse, *Response, error) {
+	return p.UpdateFeatureTagsWithContext(context.Background(), featureName, addedTags, removedTags)
+}
+
+func (p *FeatureTagsService) UpdateFeatureTagsWithContext(ctx context.Context, featureName string, addedTags []FeatureTag, removedTags []FeatureTag) (*FeatureTagsResponse, *Response, error) {
 	updateFeatureTagsBody := UpdateFeatureTagsBody{
 		AddedTags:   addedTags,
 		RemovedTags: removedTags,
 	}
 
-	req, err := p.client.newRequest("admin/features/"+featureName+"/tags", "PUT", updateFeatureTagsBody)
+	req, err := p.client.newRequest(ctx, "admin/features/"+featureName+"/tags", "PUT", updateFeatureTagsBody)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -69,7 +86,11 @@ func (p *FeatureTagsService) UpdateFeatureTags(featureName string, addedTags []F
 }
 
 func (p *FeatureTagsService) DeleteFeatureTags(featureName string, tag FeatureTag) (*Response, error) {
-	req, err := p.client.newRequest("admin/features/"+featureName+"/tags/"+tag.Type+"/"+tag.Value, "DELETE", nil)
+	return p.DeleteFeatureTagsWithContext(context.Background(), featureName, tag)
+}
+
+func (p *FeatureTagsService) DeleteFeatureTagsWithContext(ctx context.Context, featureName string, tag FeatureTag) (*Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/features/"+featureName+"/tags/"+tag.Type+"/"+tag.Value, "DELETE", nil)
 	if err != nil {
 		return nil, err
 	}
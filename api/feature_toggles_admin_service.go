@@ -2,11 +2,13 @@ package api
 
 import (
 	"bytes"
-	"net/http"
+	"context"
+	"fmt"
 )
 
 type FeatureToggle struct {
 	Archived     bool          `json:"archived"`
+	ArchivedAt   string        `json:"archivedAt,omitempty"`
 	CreatedAt    string        `json:"createdAt,omitempty"`
 	LastSeenAt   string        `json:"lastSeenAt,omitempty"`
 	Description  string        `json:"description"`
@@ -16,14 +18,17 @@ type FeatureToggle struct {
 	Type         string        `json:"type"`
 	Environments []Environment `json:"environments"`
 	Variants     []Variant     `json:"variants"`
+	Tags         []FeatureTag  `json:"tags,omitempty"`
 }
 
 type FeatureStrategy struct {
-	ID          string      `json:"id,omitempty"`
-	Name        string      `json:"name"`
-	Constraints []string    `json:"constraints,omitempty"`
-	Parameters  interface{} `json:"parameters,omitempty"`
-	SortOrder   int         `json:"sortOrder"`
+	ID          string       `json:"id,omitempty"`
+	Name        string       `json:"name"`
+	Constraints []Constraint `json:"constraints,omitempty"`
+	Parameters  interface{}  `json:"parameters,omitempty"`
+	SortOrder   int          `json:"sortOrder"`
+	Segments    []int        `json:"segments,omitempty"`
+	Variants    []Variant    `json:"variants,omitempty"`
 }
 
 type Variant struct {
@@ -50,6 +55,48 @@ type Environment struct {
 	Name       string            `json:"name"`
 	Type       string            `json:"type"`
 	Strategies []FeatureStrategy `json:"strategies"`
+	Variants   []Variant         `json:"variants,omitempty"`
+}
+
+// Validate checks the strategy before it is sent to Unleash.
+func (s FeatureStrategy) Validate() error {
+	if s.Name == "" {
+		return ErrRequiredParam("name")
+	}
+	for i, constraint := range s.Constraints {
+		if err := constraint.Validate(); err != nil {
+			return fmt.Errorf("constraint %d: %w", i, err)
+		}
+	}
+	if params, ok := s.Parameters.(StrategyParameters); ok {
+		if params.StrategyName() != s.Name {
+			return fmt.Errorf("strategy %s cannot take %s parameters", s.Name, params.StrategyName())
+		}
+		if err := params.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prepareStrategy validates the strategy and normalizes its variants.
+func (p *FeatureTogglesService) prepareStrategy(strategy FeatureStrategy) (FeatureStrategy, error) {
+	if err := strategy.Validate(); err != nil {
+		return strategy, err
+	}
+	if len(strategy.Variants) > 0 {
+		variants, err := NormalizeVariants(strategy.Variants, p.client.stickiness...)
+		if err != nil {
+			return strategy, fmt.Errorf("strategy variants: %w", err)
+		}
+		strategy.Variants = variants
+	}
+	return strategy, nil
+}
+
+type projectFeaturesResponse struct {
+	Version  int             `json:"version"`
+	Features []FeatureToggle `json:"features"`
 }
 
 type FeatureTogglesService struct {
@@ -57,7 +104,14 @@ type FeatureTogglesService struct {
 }
 
 func (p *FeatureTogglesService) GetFeatureByName(projectId string, featureName string) (*FeatureToggle, *Response, error) {
-	req, _ := p.client.newRequest("admin/projects/"+projectId+"/features/"+featureName, "GET", nil)
+	return p.GetFeatureByNameWithContext(context.Background(), projectId, featureName)
+}
+
+func (p *FeatureTogglesService) GetFeatureByNameWithContext(ctx context.Context, projectId string, featureName string) (*FeatureToggle, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/features/"+featureName, "GET", nil)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	var feature FeatureToggle
 
@@ -69,7 +123,11 @@ func (p *FeatureTogglesService) GetFeatureByName(projectId string, featureName s
 }
 
 func (p *FeatureTogglesService) CreateFeature(projectId string, feature FeatureToggle) (*FeatureToggle, *Response, error) {
-	req, err := p.client.newRequest("admin/projects/"+projectId+"/features", "POST", feature)
+	return p.CreateFeatureWithContext(context.Background(), projectId, feature)
+}
+
+func (p *FeatureTogglesService) CreateFeatureWithContext(ctx context.Context, projectId string, feature FeatureToggle) (*FeatureToggle, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/features", "POST", feature)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -84,7 +142,11 @@ func (p *FeatureTogglesService) CreateFeature(projectId string, feature FeatureT
 }
 
 func (p *FeatureTogglesService) UpdateFeature(projectId string, feature FeatureToggle) (*FeatureToggle, *Response, error) {
-	req, err := p.client.newRequest("admin/projects/"+projectId+"/features/"+feature.Name, "PUT", feature)
+	return p.UpdateFeatureWithContext(context.Background(), projectId, feature)
+}
+
+func (p *FeatureTogglesService) UpdateFeatureWithContext(ctx context.Context, projectId string, feature FeatureToggle) (*FeatureToggle, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/features/"+feature.Name, "PUT", feature)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -99,44 +161,113 @@ func (p *FeatureTogglesService) UpdateFeature(projectId string, feature FeatureT
 }
 
 func (p *FeatureTogglesService) ArchiveFeature(projectId string, featureName string) (bool, *Response, error) {
-	req, _ := p.client.newRequest("admin/projects/"+projectId+"/features/"+featureName, "DELETE", nil)
+	return p.ArchiveFeatureWithContext(context.Background(), projectId, featureName)
+}
+
+func (p *FeatureTogglesService) ArchiveFeatureWithContext(ctx context.Context, projectId string, featureName string) (bool, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/features/"+featureName, "DELETE", nil)
+	if err != nil {
+		return false, nil, err
+	}
 
 	var deleteResponse bytes.Buffer
 
 	resp, err := p.client.do(req, &deleteResponse)
-	if resp == nil || resp.StatusCode != http.StatusAccepted {
+	if err != nil {
 		return false, resp, err
 	}
 	return true, resp, nil
 }
 
 func (p *FeatureTogglesService) DeleteArchivedFeature(featureName string) (bool, *Response, error) {
-	req, _ := p.client.newRequest("admin/archive/"+featureName, "DELETE", nil)
+	return p.DeleteArchivedFeatureWithContext(context.Background(), featureName)
+}
+
+func (p *FeatureTogglesService) DeleteArchivedFeatureWithContext(ctx context.Context, featureName string) (bool, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/archive/"+featureName, "DELETE", nil)
+	if err != nil {
+		return false, nil, err
+	}
 
 	var deleteResponse bytes.Buffer
 
 	resp, err := p.client.do(req, &deleteResponse)
-	if resp == nil || resp.StatusCode != http.StatusAccepted {
+	if err != nil {
 		return false, resp, err
 	}
 	return true, resp, nil
 }
 
 func (p *FeatureTogglesService) GetFeaturesByProject(projectId string) (*[]FeatureToggle, *Response, error) {
-	req, _ := p.client.newRequest("admin/projects/"+projectId+"/features", "GET", nil)
+	return p.GetFeaturesByProjectWithContext(context.Background(), projectId)
+}
+
+func (p *FeatureTogglesService) GetFeaturesByProjectWithContext(ctx context.Context, projectId string) (*[]FeatureToggle, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/features", "GET", nil)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	var features []FeatureToggle
+	var features projectFeaturesResponse
 
 	resp, err := p.client.do(req, &features)
 	if err != nil {
 		return nil, resp, err
 	}
-	return &features, resp, err
+	return &features.Features, resp, err
+}
+
+// IterateFeaturesByProject iterates over the features of a project. Unleash
+// returns them all at once, so the iterator yields a single page.
+func (p *FeatureTogglesService) IterateFeaturesByProject(projectId string) *FeatureIterator {
+	return p.IterateFeaturesByProjectWithContext(context.Background(), projectId)
+}
+
+func (p *FeatureTogglesService) IterateFeaturesByProjectWithContext(ctx context.Context, projectId string) *FeatureIterator {
+	it := &FeatureIterator{}
+	it.pages = newPageIterator(ctx, 0, 0, func(ctx context.Context, offset int, limit int) (int, int, error) {
+		features, _, err := p.GetFeaturesByProjectWithContext(ctx, projectId)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = *features
+		return len(it.page), len(it.page), nil
+	})
+	return it
+}
+
+// GetFeatureStrategies returns the strategies of a feature in one
+// environment. Unleash serves them for archived features too.
+func (p *FeatureTogglesService) GetFeatureStrategies(projectId string, featureName string, environment string) (*[]FeatureStrategy, *Response, error) {
+	return p.GetFeatureStrategiesWithContext(context.Background(), projectId, featureName, environment)
+}
+
+func (p *FeatureTogglesService) GetFeatureStrategiesWithContext(ctx context.Context, projectId string, featureName string, environment string) (*[]FeatureStrategy, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/features/"+featureName+"/environments/"+environment+"/strategies", "GET", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var strategies []FeatureStrategy
+
+	resp, err := p.client.do(req, &strategies)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &strategies, resp, err
 }
 
 // Adds a strategy to a feature toggle in a given environment
 func (p *FeatureTogglesService) AddStrategyToFeature(projectId string, featureName string, environment string, featureStrategy FeatureStrategy) (*FeatureStrategy, *Response, error) {
-	req, err := p.client.newRequest("admin/projects/"+projectId+"/features/"+featureName+"/environments/"+environment+"/strategies", "POST", featureStrategy)
+	return p.AddStrategyToFeatureWithContext(context.Background(), projectId, featureName, environment, featureStrategy)
+}
+
+func (p *FeatureTogglesService) AddStrategyToFeatureWithContext(ctx context.Context, projectId string, featureName string, environment string, featureStrategy FeatureStrategy) (*FeatureStrategy, *Response, error) {
+	featureStrategy, err := p.prepareStrategy(featureStrategy)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/features/"+featureName+"/environments/"+environment+"/strategies", "POST", featureStrategy)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -151,7 +282,15 @@ func (p *FeatureTogglesService) AddStrategyToFeature(projectId string, featureNa
 }
 
 func (p *FeatureTogglesService) UpdateFeatureStrategy(projectId string, featureName string, environment string, featureStrategy FeatureStrategy) (*FeatureStrategy, *Response, error) {
-	req, err := p.client.newRequest("admin/projects/"+projectId+"/features/"+featureName+"/environments/"+environment+"/strategies/"+featureStrategy.ID, "PUT", featureStrategy)
+	return p.UpdateFeatureStrategyWithContext(context.Background(), projectId, featureName, environment, featureStrategy)
+}
+
+func (p *FeatureTogglesService) UpdateFeatureStrategyWithContext(ctx context.Context, projectId string, featureName string, environment string, featureStrategy FeatureStrategy) (*FeatureStrategy, *Response, error) {
+	featureStrategy, err := p.prepareStrategy(featureStrategy)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/features/"+featureName+"/environments/"+environment+"/strategies/"+featureStrategy.ID, "PUT", featureStrategy)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -167,25 +306,39 @@ func (p *FeatureTogglesService) UpdateFeatureStrategy(projectId string, featureN
 
 // Deletes a strategy from a feature toggle in a given environment
 func (p *FeatureTogglesService) DeleteStrategyFromFeature(projectId string, featureName string, environment string, strategyId string) (bool, *Response, error) {
-	req, _ := p.client.newRequest("admin/projects/"+projectId+"/features/"+featureName+"/environments/"+environment+"/strategies/"+strategyId, "DELETE", nil)
+	return p.DeleteStrategyFromFeatureWithContext(context.Background(), projectId, featureName, environment, strategyId)
+}
+
+func (p *FeatureTogglesService) DeleteStrategyFromFeatureWithContext(ctx context.Context, projectId string, featureName string, environment string, strategyId string) (bool, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/features/"+featureName+"/environments/"+environment+"/strategies/"+strategyId, "DELETE", nil)
+	if err != nil {
+		return false, nil, err
+	}
 
 	var deleteResponse bytes.Buffer
 
 	resp, err := p.client.do(req, &deleteResponse)
-	if resp == nil {
+	if err != nil {
 		return false, resp, err
 	}
 	return true, resp, nil
 }
 
 func (p *FeatureTogglesService) EnableFeatureOnEnvironment(projectId string, featureName string, environment string, enabled bool) (bool, *Response, error) {
+	return p.EnableFeatureOnEnvironmentWithContext(context.Background(), projectId, featureName, environment, enabled)
+}
+
+func (p *FeatureTogglesService) EnableFeatureOnEnvironmentWithContext(ctx context.Context, projectId string, featureName string, environment string, enabled bool) (bool, *Response, error) {
 	var featureState string
 	if enabled {
 		featureState = "on"
 	} else {
 		featureState = "off"
 	}
-	req, _ := p.client.newRequest("admin/projects/"+projectId+"/features/"+featureName+"/environments/"+environment+"/"+featureState, "POST", FeatureToggle{})
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/features/"+featureName+"/environments/"+environment+"/"+featureState, "POST", FeatureToggle{})
+	if err != nil {
+		return false, nil, err
+	}
 
 	var response bytes.Buffer
 
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -91,6 +92,53 @@ func TestFeatureTogglesService_GetFeatureByName(t *testing.T) {
 	}
 }
 
+func TestFeatureTogglesService_GetFeaturesByProject(t *testing.T) {
+	tests := []struct {
+		name         string
+		statusCode   int
+		body         string
+		wantFeatures *[]FeatureToggle
+		wantErr      bool
+	}{
+		{
+			name:       "ReturnsFeaturesFromEnvelope",
+			statusCode: http.StatusOK,
+			body:       `{"version":1,"features":[{"name":"MyToggle","project":"default"},{"name":"OtherToggle","project":"default"}]}`,
+			wantFeatures: &[]FeatureToggle{
+				{Name: "MyToggle", Project: "default"},
+				{Name: "OtherToggle", Project: "default"},
+			},
+		},
+		{
+			name:         "ReturnsEmptyList",
+			statusCode:   http.StatusOK,
+			body:         `{"version":1,"features":[]}`,
+			wantFeatures: &[]FeatureToggle{},
+		},
+		{
+			name:       "ReturnsError",
+			statusCode: http.StatusNotFound,
+			body:       `{"name":"NotFoundError"}`,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			mock, client := newTestClient(t)
+			mock.On(http.MethodGet, "admin/projects/default/features").Reply(tt.statusCode, tt.body)
+
+			got, _, err := client.FeatureToggles.GetFeaturesByProject("default")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetFeaturesByProject() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.wantFeatures) {
+				t.Errorf("GetFeaturesByProject() got = %v, want %v", got, tt.wantFeatures)
+			}
+		})
+	}
+}
+
 func createHttpResponseMock(statusCode int, body string, requestMethod string) *http.Response {
 	return &http.Response{
 		StatusCode: statusCode,
@@ -98,3 +146,66 @@ func createHttpResponseMock(statusCode int, body string, requestMethod string) *
 		Request:    &http.Request{Method: requestMethod, RequestURI: "local"},
 	}
 }
+
+func TestFeatureTogglesService_DeletesReportErrorResponses(t *testing.T) {
+	calls := map[string]struct {
+		path string
+		call func(p *FeatureTogglesService) (bool, *Response, error)
+	}{
+		"ArchiveFeature": {
+			"admin/projects/default/features/checkout",
+			func(p *FeatureTogglesService) (bool, *Response, error) {
+				return p.ArchiveFeature("default", "checkout")
+			},
+		},
+		"DeleteArchivedFeature": {
+			"admin/archive/checkout",
+			func(p *FeatureTogglesService) (bool, *Response, error) {
+				return p.DeleteArchivedFeature("checkout")
+			},
+		},
+		"DeleteStrategyFromFeature": {
+			"admin/projects/default/features/checkout/environments/production/strategies/s1",
+			func(p *FeatureTogglesService) (bool, *Response, error) {
+				return p.DeleteStrategyFromFeature("default", "checkout", "production", "s1")
+			},
+		},
+	}
+	for name, c := range calls {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			for _, status := range []int{http.StatusOK, http.StatusAccepted, http.StatusNoContent} {
+				mock, client := newTestClient(t)
+				mock.On(http.MethodDelete, c.path).Reply(status, "")
+				ok, _, err := c.call(client.FeatureToggles)
+				if !ok || err != nil {
+					t.Errorf("status %d: got %v, %v, want true, nil", status, ok, err)
+				}
+			}
+
+			mock, client := newTestClient(t)
+			mock.On(http.MethodDelete, c.path).Reply(http.StatusNotFound, `{"name":"NotFoundError","message":"not found"}`)
+			ok, resp, err := c.call(client.FeatureToggles)
+			if ok || !errors.Is(err, ErrNotFound) {
+				t.Errorf("status 404: got %v, %v, want false, ErrNotFound", ok, err)
+			}
+			if resp == nil || resp.StatusCode != http.StatusNotFound {
+				t.Errorf("status 404: response = %v, want the 404 response", resp)
+			}
+		})
+	}
+}
+
+func TestFeatureTogglesService_GetFeatureStrategies(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodGet, "admin/projects/default/features/checkout/environments/production/strategies").
+		Reply(http.StatusOK, `[{"id":"s1","name":"default","sortOrder":0},{"id":"s2","name":"userWithId","parameters":{"userIds":"1"},"sortOrder":1}]`)
+
+	strategies, _, err := client.FeatureToggles.GetFeatureStrategies("default", "checkout", "production")
+	if err != nil {
+		t.Fatalf("GetFeatureStrategies() error = %v", err)
+	}
+	if len(*strategies) != 2 || (*strategies)[1].ID != "s2" {
+		t.Errorf("GetFeatureStrategies() = %+v", *strategies)
+	}
+}
@@ -1,5 +1,7 @@
 package api
 
+import "context"
+
 type AllFeatureTypesResponse struct {
 	Version int           `json:"version"`
 	Types   []FeatureType `json:"types"`
@@ -17,7 +19,14 @@ type FeatureTypesService struct {
 }
 
 func (p *FeatureTypesService) GetAllFeatureTypes() (*AllFeatureTypesResponse, *Response, error) {
-	req, _ := p.client.newRequest("admin/feature-types", "GET", nil)
+	return p.GetAllFeatureTypesWithContext(context.Background())
+}
+
+func (p *FeatureTypesService) GetAllFeatureTypesWithContext(ctx context.Context) (*AllFeatureTypesResponse, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/feature-types", "GET", nil)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	var featureTypes AllFeatureTypesResponse
 
@@ -27,3 +36,32 @@ func (p *FeatureTypesService) GetAllFeatureTypes() (*AllFeatureTypesResponse, *R
 	}
 	return &featureTypes, resp, err
 }
+
+type featureTypeLifetimeBody struct {
+	LifetimeDays int `json:"lifetimeDays"`
+}
+
+// UpdateFeatureTypeLifetime changes the expected lifetime of features of a
+// type, after which they are reported as potentially stale. Zero means they
+// never become stale.
+func (p *FeatureTypesService) UpdateFeatureTypeLifetime(typeId string, lifetimeDays int) (*FeatureType, *Response, error) {
+	return p.UpdateFeatureTypeLifetimeWithContext(context.Background(), typeId, lifetimeDays)
+}
+
+func (p *FeatureTypesService) UpdateFeatureTypeLifetimeWithContext(ctx context.Context, typeId string, lifetimeDays int) (*FeatureType, *Response, error) {
+	if typeId == "" {
+		return nil, nil, ErrRequiredParam("typeId")
+	}
+	req, err := p.client.newRequest(ctx, "admin/feature-types/"+typeId+"/lifetime", "PUT", featureTypeLifetimeBody{LifetimeDays: lifetimeDays})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var featureType FeatureType
+
+	resp, err := p.client.do(req, &featureType)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &featureType, resp, err
+}
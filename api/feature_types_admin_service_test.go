@@ -80,3 +80,17 @@ func TestFeatureTypesService_GetAllFeatureTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestFeatureTypesService_UpdateFeatureTypeLifetime(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodPut, "admin/feature-types/release/lifetime").Reply(http.StatusOK, `{"id":"release","name":"Release","lifetimeDays":60}`)
+
+	featureType, _, err := client.FeatureTypes.UpdateFeatureTypeLifetime("release", 60)
+	if err != nil || featureType.LifetimeDays != 60 {
+		t.Fatalf("UpdateFeatureTypeLifetime() = %+v, %v", featureType, err)
+	}
+	var sent featureTypeLifetimeBody
+	if err := mock.Requests()[0].DecodeBody(&sent); err != nil || sent.LifetimeDays != 60 {
+		t.Errorf("UpdateFeatureTypeLifetime() sent %+v, %v", sent, err)
+	}
+}
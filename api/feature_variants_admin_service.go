@@ -1,5 +1,7 @@
 package api
 
+import "context"
+
 type VariantsResponse struct {
 	Version  int       `json:"version"`
 	Variants []Variant `json:"variants"`
@@ -10,7 +12,104 @@ type VariantsService struct {
 }
 
 func (p *VariantsService) AddVariantsForFeatureToggle(projectId string, featureName string, variants []Variant) (*VariantsResponse, *Response, error) {
-	req, err := p.client.newRequest("admin/projects/"+projectId+"/features/"+featureName+"/variants", "PUT", variants)
+	return p.AddVariantsForFeatureToggleWithContext(context.Background(), projectId, featureName, variants)
+}
+
+// AddVariantsForFeatureToggleWithContext replaces the variants of a feature.
+// The variants are validated and normalized with NormalizeVariants first, so
+// invalid variants are reported as an error wrapping ErrInvalidVariants
+// without a request being sent.
+func (p *VariantsService) AddVariantsForFeatureToggleWithContext(ctx context.Context, projectId string, featureName string, variants []Variant) (*VariantsResponse, *Response, error) {
+	variants, err := NormalizeVariants(variants, p.client.stickiness...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/features/"+featureName+"/variants", "PUT", variants)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var variantsResponse VariantsResponse
+
+	resp, err := p.client.do(req, &variantsResponse)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &variantsResponse, resp, err
+}
+
+// GetEnvironmentVariants returns the variants of a feature in one environment.
+func (p *VariantsService) GetEnvironmentVariants(projectId string, featureName string, environment string) (*VariantsResponse, *Response, error) {
+	return p.GetEnvironmentVariantsWithContext(context.Background(), projectId, featureName, environment)
+}
+
+func (p *VariantsService) GetEnvironmentVariantsWithContext(ctx context.Context, projectId string, featureName string, environment string) (*VariantsResponse, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/features/"+featureName+"/environments/"+environment+"/variants", "GET", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var variantsResponse VariantsResponse
+
+	resp, err := p.client.do(req, &variantsResponse)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &variantsResponse, resp, err
+}
+
+// SetEnvironmentVariants replaces the variants of a feature in one
+// environment. The variants are normalized like in AddVariantsForFeatureToggle.
+func (p *VariantsService) SetEnvironmentVariants(projectId string, featureName string, environment string, variants []Variant) (*VariantsResponse, *Response, error) {
+	return p.SetEnvironmentVariantsWithContext(context.Background(), projectId, featureName, environment, variants)
+}
+
+func (p *VariantsService) SetEnvironmentVariantsWithContext(ctx context.Context, projectId string, featureName string, environment string, variants []Variant) (*VariantsResponse, *Response, error) {
+	variants, err := NormalizeVariants(variants, p.client.stickiness...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/features/"+featureName+"/environments/"+environment+"/variants", "PUT", variants)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var variantsResponse VariantsResponse
+
+	resp, err := p.client.do(req, &variantsResponse)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return &variantsResponse, resp, err
+}
+
+type variantsBatchRequest struct {
+	Variants     []Variant `json:"variants"`
+	Environments []string  `json:"environments"`
+}
+
+// SetVariantsForEnvironments replaces the variants of a feature in several
+// environments at once. Unleash applies the change to all of them or none.
+func (p *VariantsService) SetVariantsForEnvironments(projectId string, featureName string, environments []string, variants []Variant) (*VariantsResponse, *Response, error) {
+	return p.SetVariantsForEnvironmentsWithContext(context.Background(), projectId, featureName, environments, variants)
+}
+
+func (p *VariantsService) SetVariantsForEnvironmentsWithContext(ctx context.Context, projectId string, featureName string, environments []string, variants []Variant) (*VariantsResponse, *Response, error) {
+	if len(environments) == 0 {
+		return nil, nil, ErrRequiredParam("environments")
+	}
+	variants, err := NormalizeVariants(variants, p.client.stickiness...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body := variantsBatchRequest{Variants: variants, Environments: environments}
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/features/"+featureName+"/variants-batch", "PUT", body)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -0,0 +1,18 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/sighphyre/go-unleash-api/mocks"
+)
+
+// newTestClient returns a client whose requests are answered by a fresh mock.
+func newTestClient(t *testing.T, opts ...Option) (*mocks.Client, *ApiClient) {
+	t.Helper()
+	mock := mocks.NewClient(t)
+	client, err := NewClient("https://unleash.example.com/api", "myToken", append([]Option{WithHTTPClient(mock)}, opts...)...)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return mock, client
+}
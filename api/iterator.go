@@ -0,0 +1,186 @@
+package api
+
+import "context"
+
+// DefaultPageSize is the number of items iterators request per page from
+// endpoints that support paging.
+const DefaultPageSize = 100
+
+// pageFetcher loads the page starting at offset into the typed iterator and
+// returns how many items it held and how many items exist in total.
+type pageFetcher func(ctx context.Context, offset int, limit int) (n int, total int, err error)
+
+// pageIterator drives the offset based paging shared by the typed iterators.
+// Endpoints without paging return everything as a single page.
+type pageIterator struct {
+	ctx    context.Context
+	fetch  pageFetcher
+	offset int
+	limit  int
+	total  int
+	done   bool
+	err    error
+}
+
+func newPageIterator(ctx context.Context, offset int, limit int, fetch pageFetcher) pageIterator {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	return pageIterator{ctx: ctx, fetch: fetch, offset: offset, limit: limit, total: -1}
+}
+
+func (it *pageIterator) next() bool {
+	if it.done {
+		return false
+	}
+	if it.ctx == nil {
+		it.err = ErrContextCannotBeNil
+		it.done = true
+		return false
+	}
+	n, total, err := it.fetch(it.ctx, it.offset, it.limit)
+	if err != nil {
+		it.err = err
+		it.done = true
+		return false
+	}
+	it.total = total
+	it.offset += n
+	if n == 0 {
+		it.done = true
+		return false
+	}
+	// rely on the total rather than short pages, servers may cap the limit
+	if it.offset >= total {
+		it.done = true
+	}
+	return true
+}
+
+// FeatureIterator pages through features. Call Next until it returns false,
+// reading each page with Page, then check Err:
+//
+//	it := client.FeatureToggles.IterateSearchFeatures(opts)
+//	for it.Next() {
+//		for _, feature := range it.Page() {
+//			...
+//		}
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type FeatureIterator struct {
+	pages pageIterator
+	page  []FeatureToggle
+}
+
+// Next fetches the next page and reports whether there was one.
+func (it *FeatureIterator) Next() bool {
+	return it.pages.next()
+}
+
+// Page returns the page fetched by the last call to Next.
+func (it *FeatureIterator) Page() []FeatureToggle {
+	return it.page
+}
+
+// Total returns the number of items across all pages, or -1 before the first
+// page is fetched.
+func (it *FeatureIterator) Total() int {
+	return it.pages.total
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *FeatureIterator) Err() error {
+	return it.pages.err
+}
+
+// ForEach calls fn for every remaining feature, stopping at the first error.
+func (it *FeatureIterator) ForEach(fn func(FeatureToggle) error) error {
+	for it.Next() {
+		for _, feature := range it.page {
+			if err := fn(feature); err != nil {
+				return err
+			}
+		}
+	}
+	return it.Err()
+}
+
+// UserIterator pages through users. It is used like FeatureIterator.
+type UserIterator struct {
+	pages pageIterator
+	page  []UserDetails
+}
+
+// Next fetches the next page and reports whether there was one.
+func (it *UserIterator) Next() bool {
+	return it.pages.next()
+}
+
+// Page returns the page fetched by the last call to Next.
+func (it *UserIterator) Page() []UserDetails {
+	return it.page
+}
+
+// Total returns the number of items across all pages, or -1 before the first
+// page is fetched.
+func (it *UserIterator) Total() int {
+	return it.pages.total
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *UserIterator) Err() error {
+	return it.pages.err
+}
+
+// ForEach calls fn for every remaining user, stopping at the first error.
+func (it *UserIterator) ForEach(fn func(UserDetails) error) error {
+	for it.Next() {
+		for _, user := range it.page {
+			if err := fn(user); err != nil {
+				return err
+			}
+		}
+	}
+	return it.Err()
+}
+
+// ApiTokenIterator pages through API tokens. It is used like FeatureIterator.
+type ApiTokenIterator struct {
+	pages pageIterator
+	page  []ApiToken
+}
+
+// Next fetches the next page and reports whether there was one.
+func (it *ApiTokenIterator) Next() bool {
+	return it.pages.next()
+}
+
+// Page returns the page fetched by the last call to Next.
+func (it *ApiTokenIterator) Page() []ApiToken {
+	return it.page
+}
+
+// Total returns the number of items across all pages, or -1 before the first
+// page is fetched.
+func (it *ApiTokenIterator) Total() int {
+	return it.pages.total
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ApiTokenIterator) Err() error {
+	return it.pages.err
+}
+
+// ForEach calls fn for every remaining token, stopping at the first error.
+func (it *ApiTokenIterator) ForEach(fn func(ApiToken) error) error {
+	for it.Next() {
+		for _, token := range it.page {
+			if err := fn(token); err != nil {
+				return err
+			}
+		}
+	}
+	return it.Err()
+}
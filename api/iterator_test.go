@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/sighphyre/go-unleash-api/mocks"
+)
+
+func TestFeatureIterator_PagesThroughSearch(t *testing.T) {
+	mock, client := newTestClient(t)
+	const total = 5
+	mock.On(http.MethodGet, "admin/search/features").ReplyFunc(func(req *http.Request) (*http.Response, error) {
+		query := mock.Requests()[len(mock.Requests())-1].Query
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		// the server caps pages at two features whatever the limit
+		var features []FeatureToggle
+		for i := offset; i < total && i < offset+2; i++ {
+			features = append(features, FeatureToggle{Name: fmt.Sprintf("feature-%d", i)})
+		}
+		body, err := json.Marshal(FeatureSearchResult{Features: features, Total: total})
+		if err != nil {
+			return nil, err
+		}
+		return mocks.NewResponse(req, http.StatusOK, string(body)), nil
+	})
+
+	it := client.FeatureToggles.IterateSearchFeatures(SearchFeaturesOptions{Limit: 3})
+	if it.Total() != -1 {
+		t.Errorf("Total() before Next() = %d, want -1", it.Total())
+	}
+	var names []string
+	err := it.ForEach(func(feature FeatureToggle) error {
+		names = append(names, feature.Name)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEach() error = %v", err)
+	}
+	if len(names) != total || names[4] != "feature-4" {
+		t.Errorf("iterated over %v", names)
+	}
+	if it.Total() != total {
+		t.Errorf("Total() = %d, want %d", it.Total(), total)
+	}
+	if calls := len(mock.Requests()); calls != 3 {
+		t.Errorf("made %d requests, want 3", calls)
+	}
+	if limit := mock.Requests()[0].Query.Get("limit"); limit != "3" {
+		t.Errorf("requested limit %s, want 3", limit)
+	}
+}
+
+func TestFeatureIterator_StopsOnError(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodGet, "admin/projects/default/features").Reply(http.StatusNotFound, `{"name":"NotFoundError","message":"no such project"}`)
+
+	it := client.FeatureToggles.IterateFeaturesByProject("default")
+	if it.Next() {
+		t.Fatal("Next() = true after an error")
+	}
+	if !errors.Is(it.Err(), ErrNotFound) {
+		t.Errorf("Err() = %v, want ErrNotFound", it.Err())
+	}
+	if it.Next() {
+		t.Error("Next() = true after the iterator stopped")
+	}
+}
+
+func TestApiTokenIterator_SinglePage(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodGet, "admin/api-tokens").Reply(http.StatusOK, `{"tokens":[{"secret":"a"},{"secret":"b"}]}`)
+
+	it := client.ApiTokens.IterateApiTokens()
+	pages := 0
+	for it.Next() {
+		pages++
+		if len(it.Page()) != 2 {
+			t.Errorf("Page() = %v", it.Page())
+		}
+	}
+	if pages != 1 || it.Err() != nil || it.Total() != 2 {
+		t.Errorf("got %d pages, total %d, error %v", pages, it.Total(), it.Err())
+	}
+}
+
+func TestPageIterator_NilContext(t *testing.T) {
+	it := newPageIterator(nil, 0, 0, func(ctx context.Context, offset int, limit int) (int, int, error) {
+		t.Fatal("fetch called without a context")
+		return 0, 0, nil
+	})
+	if it.next() || !errors.Is(it.err, ErrContextCannotBeNil) {
+		t.Errorf("next() error = %v, want ErrContextCannotBeNil", it.err)
+	}
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Option configures an ApiClient in NewClient.
+type Option func(c *ApiClient) error
+
+// Logger receives diagnostic messages, such as retries. *log.Logger
+// satisfies it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// RateLimiter throttles outgoing requests. Wait is called before every
+// attempt and should block until the request may proceed.
+// *rate.Limiter from golang.org/x/time/rate satisfies it.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// WithHTTPClient sets the HTTPClient used to send requests. It cannot be
+// combined with WithTransport.
+func WithHTTPClient(httpClient HTTPClient) Option {
+	return func(c *ApiClient) error {
+		if httpClient == nil {
+			return errors.New("http client cannot be nil")
+		}
+		c.client = httpClient
+		return nil
+	}
+}
+
+// WithTransport sets the transport of the default *http.Client.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *ApiClient) error {
+		if transport == nil {
+			return errors.New("transport cannot be nil")
+		}
+		c.transport = transport
+		return nil
+	}
+}
+
+// WithUserAgent overrides the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *ApiClient) error {
+		if userAgent == "" {
+			return errors.New("user agent cannot be empty")
+		}
+		c.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithTimeout bounds every service call, including any retries.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *ApiClient) error {
+		if timeout < 0 {
+			return errors.New("timeout cannot be negative")
+		}
+		c.timeout = timeout
+		return nil
+	}
+}
+
+// WithRetryPolicy enables retries of transient failures. The policy is copied,
+// so later changes to it do not affect the client.
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(c *ApiClient) error {
+		if policy == nil {
+			c.retryPolicy = nil
+			return nil
+		}
+		p := *policy
+		p.RetryableStatusCodes = append([]int(nil), policy.RetryableStatusCodes...)
+		c.retryPolicy = &p
+		return nil
+	}
+}
+
+// WithLogger sets the logger used for diagnostic messages.
+func WithLogger(logger Logger) Option {
+	return func(c *ApiClient) error {
+		c.logger = logger
+		return nil
+	}
+}
+
+// WithRateLimiter throttles requests through limiter.
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(c *ApiClient) error {
+		c.rateLimiter = limiter
+		return nil
+	}
+}
+
+// WithHeader adds a header sent with every request. The Authorization,
+// Accept, Content-Type and User-Agent headers are managed by the client and
+// cannot be overridden this way.
+func WithHeader(key string, value string) Option {
+	return func(c *ApiClient) error {
+		if key == "" {
+			return errors.New("header key cannot be empty")
+		}
+		c.headers.Add(key, value)
+		return nil
+	}
+}
+
+// WithCustomStickiness declares custom context fields that variants may use
+// as stickiness, in addition to the built-in values.
+func WithCustomStickiness(contextFields ...string) Option {
+	return func(c *ApiClient) error {
+		c.stickiness = append(c.stickiness, contextFields...)
+		return nil
+	}
+}
+
+// CustomStickiness returns the context fields declared with
+// WithCustomStickiness.
+func (c *ApiClient) CustomStickiness() []string {
+	return append([]string(nil), c.stickiness...)
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type recordingClient struct {
+	requests []*http.Request
+}
+
+func (r *recordingClient) Do(req *http.Request) (*http.Response, error) {
+	r.requests = append(r.requests, req)
+	return createHttpResponseMock(http.StatusOK, `{"version":1,"types":[]}`, req.Method), nil
+}
+
+type countingLimiter struct {
+	calls int
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	l.calls++
+	return nil
+}
+
+func TestNewClient_Options(t *testing.T) {
+	recorder := &recordingClient{}
+	limiter := &countingLimiter{}
+
+	c, err := NewClient("https://unleash.example.com/api", "myToken",
+		WithHTTPClient(recorder),
+		WithUserAgent("my-tool/1.0"),
+		WithTimeout(time.Minute),
+		WithRateLimiter(limiter),
+		WithHeader("X-Team", "platform"),
+		WithHeader("Authorization", "ignored"),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	if _, _, err := c.FeatureTypes.GetAllFeatureTypes(); err != nil {
+		t.Fatalf("FeatureTypesService.GetAllFeatureTypes() error = %v", err)
+	}
+	if len(recorder.requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(recorder.requests))
+	}
+	req := recorder.requests[0]
+
+	if got := req.Header.Get("User-Agent"); got != "my-tool/1.0" {
+		t.Errorf("User-Agent = %q, want %q", got, "my-tool/1.0")
+	}
+	if got := req.Header.Get("X-Team"); got != "platform" {
+		t.Errorf("X-Team = %q, want %q", got, "platform")
+	}
+	if got := req.Header.Get("Authorization"); got != "myToken" {
+		t.Errorf("Authorization = %q, want %q", got, "myToken")
+	}
+	if _, ok := req.Context().Deadline(); !ok {
+		t.Error("expected the request context to carry a deadline")
+	}
+	if limiter.calls != 1 {
+		t.Errorf("rate limiter called %d times, want 1", limiter.calls)
+	}
+}
+
+func TestNewClient_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		apiUrl    string
+		authToken string
+		opts      []Option
+		wantErr   error
+	}{
+		{"EmptyUrl", "", "myToken", nil, ErrApiUrlCannotBeEmpty},
+		{"EmptyToken", "https://unleash.example.com/api", "", nil, ErrTokenAuthCannotBeEmpty},
+		{"TransportWithHTTPClient", "https://unleash.example.com/api", "myToken", []Option{WithHTTPClient(&recordingClient{}), WithTransport(http.DefaultTransport)}, ErrTransportWithHTTPClient},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClient(tt.apiUrl, tt.authToken, tt.opts...)
+			if err != tt.wantErr {
+				t.Errorf("NewClient() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithRetryPolicy_CopiesPolicy(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	c, err := NewClient("https://unleash.example.com/api", "myToken", WithRetryPolicy(policy))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	policy.MaxAttempts = 100
+	if c.retryPolicy.MaxAttempts != DefaultRetryPolicy().MaxAttempts {
+		t.Errorf("client retry policy changed after construction: %v", c.retryPolicy.MaxAttempts)
+	}
+}
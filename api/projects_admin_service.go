@@ -1,10 +1,9 @@
 package api
 
 import (
-	"encoding/json"
-	"errors"
+	"bytes"
+	"context"
 	"fmt"
-	"net/http"
 )
 
 type ProjectDetails struct {
@@ -25,6 +24,21 @@ type Project struct {
 	Description string `json:"description"`
 }
 
+// ProjectSummary is a project as listed by GetAllProjects.
+type ProjectSummary struct {
+	Id           string `json:"id"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	FeatureCount int    `json:"featureCount"`
+	CreatedAt    string `json:"createdAt,omitempty"`
+	UpdatedAt    string `json:"updatedAt,omitempty"`
+}
+
+type allProjectsResponse struct {
+	Version  int              `json:"version"`
+	Projects []ProjectSummary `json:"projects"`
+}
+
 type CreateProjectResponse struct {
 	Id          string `json:"id"`
 	Name        string `json:"name"`
@@ -38,6 +52,8 @@ type AddUserRoleResponse struct {
 	RoleId    int    `json:"roleId"`
 }
 
+// ErrorResponse is the legacy error envelope used by older Unleash versions.
+// CheckResponse decodes it into an *APIError.
 type ErrorResponse struct {
 	Error struct {
 		Name    string `json:"name"`
@@ -49,8 +65,34 @@ type ProjectsService struct {
 	client *ApiClient
 }
 
+func (p *ProjectsService) GetAllProjects() (*[]ProjectSummary, *Response, error) {
+	return p.GetAllProjectsWithContext(context.Background())
+}
+
+func (p *ProjectsService) GetAllProjectsWithContext(ctx context.Context) (*[]ProjectSummary, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/projects", "GET", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var projects allProjectsResponse
+
+	resp, err := p.client.do(req, &projects)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &projects.Projects, resp, err
+}
+
 func (p *ProjectsService) GetProjectById(projectId string) (*ProjectDetails, *Response, error) {
-	req, _ := p.client.newRequest("admin/projects/"+projectId, "GET", nil)
+	return p.GetProjectByIdWithContext(context.Background(), projectId)
+}
+
+func (p *ProjectsService) GetProjectByIdWithContext(ctx context.Context, projectId string) (*ProjectDetails, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId, "GET", nil)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	var project ProjectDetails
 
@@ -59,15 +101,18 @@ func (p *ProjectsService) GetProjectById(projectId string) (*ProjectDetails, *Re
 		return nil, resp, err
 	}
 
-	if resp == nil {
-		return nil, nil, errors.New("response is nil")
-	}
-
 	return &project, resp, nil
 }
 
 func (p *ProjectsService) CreateProject(project Project) (*CreateProjectResponse, *Response, error) {
-	req, _ := p.client.newRequest("admin/projects", "POST", project)
+	return p.CreateProjectWithContext(context.Background(), project)
+}
+
+func (p *ProjectsService) CreateProjectWithContext(ctx context.Context, project Project) (*CreateProjectResponse, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/projects", "POST", project)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	var projectCreate CreateProjectResponse
 
@@ -80,10 +125,14 @@ func (p *ProjectsService) CreateProject(project Project) (*CreateProjectResponse
 }
 
 func (p *ProjectsService) UpdateProject(projectId string, project Project) (*CreateProjectResponse, *Response, error) {
+	return p.UpdateProjectWithContext(context.Background(), projectId, project)
+}
+
+func (p *ProjectsService) UpdateProjectWithContext(ctx context.Context, projectId string, project Project) (*CreateProjectResponse, *Response, error) {
 	if projectId == "" {
 		return nil, nil, ErrRequiredParam("projectId")
 	}
-	req, err := p.client.newRequest("admin/projects/"+projectId, "PUT", project)
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId, "PUT", project)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -99,10 +148,14 @@ func (p *ProjectsService) UpdateProject(projectId string, project Project) (*Cre
 }
 
 func (p *ProjectsService) DeleteProject(projectId string) (*Response, error) {
+	return p.DeleteProjectWithContext(context.Background(), projectId)
+}
+
+func (p *ProjectsService) DeleteProjectWithContext(ctx context.Context, projectId string) (*Response, error) {
 	if projectId == "" {
 		return nil, ErrRequiredParam("projectId")
 	}
-	req, err := p.client.newRequest("admin/projects/"+projectId, "DELETE", nil)
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId, "DELETE", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -110,23 +163,16 @@ func (p *ProjectsService) DeleteProject(projectId string) (*Response, error) {
 	var deleteResponse Response
 	resp, err := p.client.do(req, &deleteResponse)
 	if err != nil {
-		return nil, err
-	}
-	if resp == nil {
-		return nil, errors.New("response is nil")
-	}
-	if resp.StatusCode != http.StatusOK {
-		var errorResponse ErrorResponse
-		err := json.NewDecoder(resp.Body).Decode(&errorResponse)
-		if err != nil {
-			return nil, err
-		}
-		return nil, fmt.Errorf("%s: %s", errorResponse.Error.Name, errorResponse.Error.Message)
+		return resp, err
 	}
 	return &deleteResponse, nil
 }
 
 func (p *ProjectsService) AddUserProject(userId int, projectId string, roleId int) (*AddUserRoleResponse, *Response, error) {
+	return p.AddUserProjectWithContext(context.Background(), userId, projectId, roleId)
+}
+
+func (p *ProjectsService) AddUserProjectWithContext(ctx context.Context, userId int, projectId string, roleId int) (*AddUserRoleResponse, *Response, error) {
 	if projectId == "" {
 		return nil, nil, ErrRequiredParam("projectId")
 	}
@@ -138,7 +184,7 @@ func (p *ProjectsService) AddUserProject(userId int, projectId string, roleId in
 	}
 
 	path := fmt.Sprintf("admin/projects/%s/users/%d/roles/%d", projectId, userId, roleId)
-	req, err := p.client.newRequest("POST", path, nil)
+	req, err := p.client.newRequest(ctx, path, "POST", nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -146,23 +192,16 @@ func (p *ProjectsService) AddUserProject(userId int, projectId string, roleId in
 	var addRoleResponse AddUserRoleResponse
 	resp, err := p.client.do(req, &addRoleResponse)
 	if err != nil {
-		return nil, nil, err
-	}
-	if resp == nil {
-		return nil, nil, errors.New("response is nil")
-	}
-	if resp.StatusCode != http.StatusOK {
-		var errorResponse ErrorResponse
-		err := json.NewDecoder(resp.Body).Decode(&errorResponse)
-		if err != nil {
-			return nil, nil, err
-		}
-		return nil, nil, fmt.Errorf("%s: %s", errorResponse.Error.Name, errorResponse.Error.Message)
+		return nil, resp, err
 	}
 	return &addRoleResponse, resp, err
 }
 
 func (p *ProjectsService) UpdateUserProject(projectId string, userId int, roleId int) (*AddUserRoleResponse, *Response, error) {
+	return p.UpdateUserProjectWithContext(context.Background(), projectId, userId, roleId)
+}
+
+func (p *ProjectsService) UpdateUserProjectWithContext(ctx context.Context, projectId string, userId int, roleId int) (*AddUserRoleResponse, *Response, error) {
 	if projectId == "" {
 		return nil, nil, ErrRequiredParam("projectId")
 	}
@@ -174,7 +213,7 @@ func (p *ProjectsService) UpdateUserProject(projectId string, userId int, roleId
 	}
 
 	path := fmt.Sprintf("admin/projects/%s/users/%d/roles/%d", projectId, userId, roleId)
-	req, err := p.client.newRequest("PUT", path, nil)
+	req, err := p.client.newRequest(ctx, path, "PUT", nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -182,23 +221,16 @@ func (p *ProjectsService) UpdateUserProject(projectId string, userId int, roleId
 	var updateRoleResponse AddUserRoleResponse
 	resp, err := p.client.do(req, &updateRoleResponse)
 	if err != nil {
-		return nil, nil, err
-	}
-	if resp == nil {
-		return nil, nil, errors.New("response is nil")
-	}
-	if resp.StatusCode != http.StatusOK {
-		var errorResponse ErrorResponse
-		err := json.NewDecoder(resp.Body).Decode(&errorResponse)
-		if err != nil {
-			return nil, nil, err
-		}
-		return nil, nil, fmt.Errorf("%s: %s", errorResponse.Error.Name, errorResponse.Error.Message)
+		return nil, resp, err
 	}
 	return &updateRoleResponse, resp, nil
 }
 
 func (p *ProjectsService) DeleteUserProject(projectId string, userId int, roleId int) (*AddUserRoleResponse, *Response, error) {
+	return p.DeleteUserProjectWithContext(context.Background(), projectId, userId, roleId)
+}
+
+func (p *ProjectsService) DeleteUserProjectWithContext(ctx context.Context, projectId string, userId int, roleId int) (*AddUserRoleResponse, *Response, error) {
 	if projectId == "" {
 		return nil, nil, ErrRequiredParam("projectId")
 	}
@@ -210,7 +242,7 @@ func (p *ProjectsService) DeleteUserProject(projectId string, userId int, roleId
 	}
 
 	path := fmt.Sprintf("admin/projects/%s/users/%d/roles/%d", projectId, userId, roleId)
-	req, err := p.client.newRequest("DELETE", path, nil)
+	req, err := p.client.newRequest(ctx, path, "DELETE", nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -218,20 +250,62 @@ func (p *ProjectsService) DeleteUserProject(projectId string, userId int, roleId
 	var deleteResponse AddUserRoleResponse
 	resp, err := p.client.do(req, &deleteResponse)
 	if err != nil {
-		return nil, nil, err
+		return nil, resp, err
+	}
+	return &deleteResponse, resp, nil
+}
+
+type projectEnvironmentBody struct {
+	Environment string `json:"environment"`
+}
+
+// AddEnvironmentToProject enables an environment in a project, so features of
+// the project can be configured in it.
+func (p *ProjectsService) AddEnvironmentToProject(projectId string, environment string) (bool, *Response, error) {
+	return p.AddEnvironmentToProjectWithContext(context.Background(), projectId, environment)
+}
+
+func (p *ProjectsService) AddEnvironmentToProjectWithContext(ctx context.Context, projectId string, environment string) (bool, *Response, error) {
+	if projectId == "" {
+		return false, nil, ErrRequiredParam("projectId")
+	}
+	if environment == "" {
+		return false, nil, ErrRequiredParam("environment")
 	}
-	if resp == nil {
-		return nil, nil, errors.New("response is nil")
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/environments", "POST", projectEnvironmentBody{Environment: environment})
+	if err != nil {
+		return false, nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		var errorResponse ErrorResponse
-		err := json.NewDecoder(resp.Body).Decode(&errorResponse)
-		if err != nil {
-			return nil, nil, err
-		}
-		return nil, nil, fmt.Errorf("%s: %s", errorResponse.Error.Name, errorResponse.Error.Message)
+	var addResponse bytes.Buffer
+	resp, err := p.client.do(req, &addResponse)
+	if err != nil {
+		return false, resp, err
 	}
+	return true, resp, nil
+}
 
-	return &deleteResponse, resp, nil
+// RemoveEnvironmentFromProject disables an environment in a project.
+func (p *ProjectsService) RemoveEnvironmentFromProject(projectId string, environment string) (bool, *Response, error) {
+	return p.RemoveEnvironmentFromProjectWithContext(context.Background(), projectId, environment)
+}
+
+func (p *ProjectsService) RemoveEnvironmentFromProjectWithContext(ctx context.Context, projectId string, environment string) (bool, *Response, error) {
+	if projectId == "" {
+		return false, nil, ErrRequiredParam("projectId")
+	}
+	if environment == "" {
+		return false, nil, ErrRequiredParam("environment")
+	}
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/environments/"+environment, "DELETE", nil)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var removeResponse bytes.Buffer
+	resp, err := p.client.do(req, &removeResponse)
+	if err != nil {
+		return false, resp, err
+	}
+	return true, resp, nil
 }
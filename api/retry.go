@@ -0,0 +1,194 @@
+package api
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how the ApiClient retries requests that failed with a
+// transient error. It is set with WithRetryPolicy; by default nothing is retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// MinBackoff is the wait before the first retry. It doubles on every
+	// subsequent attempt, with jitter applied.
+	MinBackoff time.Duration
+	// MaxBackoff caps both the computed backoff and any Retry-After value
+	// sent by the server. Zero means no cap.
+	MaxBackoff time.Duration
+	// RetryableStatusCodes lists the response status codes that trigger a
+	// retry. Defaults to 429, 502, 503 and 504 when empty.
+	RetryableStatusCodes []int
+	// RetryNonIdempotent allows POST and PATCH requests to be replayed after
+	// failures the server may already have acted on. Rate limited (429)
+	// requests are always safe to replay and are retried regardless.
+	RetryNonIdempotent bool
+}
+
+var defaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// DefaultRetryPolicy returns a policy suited to bulk automation: up to four
+// attempts with exponential backoff between 500ms and 30s.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 4,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+	}
+}
+
+func (p *RetryPolicy) isRetryableStatus(statusCode int) bool {
+	codes := p.RetryableStatusCodes
+	if len(codes) == 0 {
+		codes = defaultRetryableStatusCodes
+	}
+	for _, code := range codes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	replayable := p.RetryNonIdempotent || isIdempotent(req.Method)
+	if err != nil {
+		return replayable
+	}
+	if !p.isRetryableStatus(resp.StatusCode) {
+		return false
+	}
+	return replayable || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// backoff returns how long to wait before the given retry attempt, honouring
+// the Retry-After header of the previous response when present.
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return p.capBackoff(wait)
+		}
+	}
+
+	wait := p.MinBackoff
+	for i := 1; i < attempt && (p.MaxBackoff == 0 || wait < p.MaxBackoff); i++ {
+		wait *= 2
+	}
+	wait = p.capBackoff(wait)
+	if wait <= 0 {
+		return 0
+	}
+	// equal jitter: wait somewhere between half and the full backoff
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(wait-half)+1))
+}
+
+func (p *RetryPolicy) capBackoff(wait time.Duration) time.Duration {
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return wait
+}
+
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := date.Sub(now)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// send issues req, retrying transient failures according to the client's
+// RetryPolicy.
+func (c *ApiClient) send(req *http.Request) (*http.Response, error) {
+	policy := c.retryPolicy
+	for attempt := 1; ; attempt++ {
+		if c.rateLimiter != nil {
+			if err := c.rateLimiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := c.client.Do(req)
+		if policy == nil || attempt >= policy.MaxAttempts || !policy.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			// the body has been consumed and cannot be replayed
+			return resp, err
+		}
+
+		wait := policy.backoff(attempt, resp)
+		if c.logger != nil {
+			var reason string
+			if err != nil {
+				reason = err.Error()
+			} else {
+				reason = resp.Status
+				if reason == "" {
+					reason = strconv.Itoa(resp.StatusCode)
+				}
+			}
+			c.logger.Printf("unleash: retrying %s %s after %s (attempt %d of %d) in %s", req.Method, displayURL(req.URL), reason, attempt+1, policy.MaxAttempts, wait)
+		}
+		if resp != nil && resp.Body != nil {
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// sequenceClient replies with the given responses in order and records the
+// body of every request it receives.
+type sequenceClient struct {
+	responses []*http.Response
+	bodies    []string
+}
+
+func (s *sequenceClient) Do(req *http.Request) (*http.Response, error) {
+	body := ""
+	if req.Body != nil {
+		data, _ := ioutil.ReadAll(req.Body)
+		body = string(data)
+	}
+	s.bodies = append(s.bodies, body)
+	if len(s.responses) == 0 {
+		return nil, errors.New("no more responses")
+	}
+	resp := s.responses[0]
+	s.responses = s.responses[1:]
+	return resp, nil
+}
+
+func newRetryingClient(httpClient HTTPClient, policy *RetryPolicy) *ApiClient {
+	c := &ApiClient{
+		client:      httpClient,
+		apiUrl:      &url.URL{Path: "local"},
+		authToken:   "myToken",
+		retryPolicy: policy,
+	}
+	c.Projects = &ProjectsService{client: c}
+	return c
+}
+
+func TestApiClient_RetriesTransientFailures(t *testing.T) {
+	seq := &sequenceClient{responses: []*http.Response{
+		createHttpResponseMock(http.StatusTooManyRequests, "", "POST"),
+		createHttpResponseMock(http.StatusOK, `{"id":"default","name":"Default"}`, "POST"),
+	}}
+	c := newRetryingClient(seq, &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond})
+
+	got, _, err := c.Projects.CreateProject(Project{Id: "default", Name: "Default"})
+	if err != nil {
+		t.Fatalf("ProjectsService.CreateProject() error = %v", err)
+	}
+	if got.Id != "default" {
+		t.Errorf("ProjectsService.CreateProject() got = %v, want id default", got)
+	}
+	if len(seq.bodies) != 2 {
+		t.Fatalf("expected 2 attempts, got %d", len(seq.bodies))
+	}
+	if seq.bodies[0] == "" || seq.bodies[0] != seq.bodies[1] {
+		t.Errorf("request body was not replayed: %q then %q", seq.bodies[0], seq.bodies[1])
+	}
+}
+
+func TestApiClient_DoesNotReplayNonIdempotentRequests(t *testing.T) {
+	seq := &sequenceClient{responses: []*http.Response{
+		createHttpResponseMock(http.StatusServiceUnavailable, "", "POST"),
+		createHttpResponseMock(http.StatusOK, `{"id":"default"}`, "POST"),
+	}}
+	c := newRetryingClient(seq, &RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond})
+
+	_, _, err := c.Projects.CreateProject(Project{Id: "default", Name: "Default"})
+	if err == nil {
+		t.Fatal("ProjectsService.CreateProject() expected an error")
+	}
+	if len(seq.bodies) != 1 {
+		t.Errorf("expected 1 attempt, got %d", len(seq.bodies))
+	}
+}
+
+func TestApiClient_StopsAfterMaxAttempts(t *testing.T) {
+	seq := &sequenceClient{responses: []*http.Response{
+		createHttpResponseMock(http.StatusBadGateway, "", "GET"),
+		createHttpResponseMock(http.StatusBadGateway, "", "GET"),
+		createHttpResponseMock(http.StatusOK, `{"name":"Default"}`, "GET"),
+	}}
+	c := newRetryingClient(seq, &RetryPolicy{MaxAttempts: 2, MinBackoff: time.Millisecond})
+
+	_, resp, err := c.Projects.GetProjectById("default")
+	if err == nil {
+		t.Fatal("ProjectsService.GetProjectById() expected an error")
+	}
+	if resp == nil || resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("ProjectsService.GetProjectById() resp = %v, want status 502", resp)
+	}
+	if len(seq.bodies) != 2 {
+		t.Errorf("expected 2 attempts, got %d", len(seq.bodies))
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := &RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for attempt, max := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 10: time.Second} {
+		got := policy.backoff(attempt, nil)
+		if got < max/2 || got > max {
+			t.Errorf("backoff(%d) = %v, want between %v and %v", attempt, got, max/2, max)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"5"}}}
+	if got := policy.backoff(1, resp); got != time.Second {
+		t.Errorf("backoff() with Retry-After above MaxBackoff = %v, want %v", got, time.Second)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOk bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, false},
+		{now.Add(10 * time.Second).Format(http.TimeFormat), 10 * time.Second, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+)
+
+// Segment is a named, reusable set of constraints that strategies refer to by
+// id.
+type Segment struct {
+	ID          int    `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Project limits the segment to one project; it is global when empty.
+	Project     string       `json:"project,omitempty"`
+	Constraints []Constraint `json:"constraints"`
+	CreatedAt   string       `json:"createdAt,omitempty"`
+}
+
+type allSegmentsResponse struct {
+	Segments []Segment `json:"segments"`
+}
+
+type SegmentsService struct {
+	client *ApiClient
+}
+
+// GetAllSegments returns every segment of the instance.
+func (p *SegmentsService) GetAllSegments() (*[]Segment, *Response, error) {
+	return p.GetAllSegmentsWithContext(context.Background())
+}
+
+func (p *SegmentsService) GetAllSegmentsWithContext(ctx context.Context) (*[]Segment, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/segments", "GET", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var segments allSegmentsResponse
+
+	resp, err := p.client.do(req, &segments)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &segments.Segments, resp, err
+}
+
+func (p *SegmentsService) GetSegment(id int) (*Segment, *Response, error) {
+	return p.GetSegmentWithContext(context.Background(), id)
+}
+
+func (p *SegmentsService) GetSegmentWithContext(ctx context.Context, id int) (*Segment, *Response, error) {
+	if id == 0 {
+		return nil, nil, ErrRequiredParam("id")
+	}
+	req, err := p.client.newRequest(ctx, "admin/segments/"+strconv.Itoa(id), "GET", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var segment Segment
+
+	resp, err := p.client.do(req, &segment)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &segment, resp, err
+}
+
+// CreateSegment creates a segment. Unleash assigns its id, which the returned
+// segment carries.
+func (p *SegmentsService) CreateSegment(segment Segment) (*Segment, *Response, error) {
+	return p.CreateSegmentWithContext(context.Background(), segment)
+}
+
+func (p *SegmentsService) CreateSegmentWithContext(ctx context.Context, segment Segment) (*Segment, *Response, error) {
+	if segment.Name == "" {
+		return nil, nil, ErrRequiredParam("name")
+	}
+	segment.ID = 0
+	if segment.Constraints == nil {
+		segment.Constraints = []Constraint{}
+	}
+	req, err := p.client.newRequest(ctx, "admin/segments", "POST", segment)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var created Segment
+
+	resp, err := p.client.do(req, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &created, resp, err
+}
+
+// UpdateSegment replaces the segment with the id of segment.
+func (p *SegmentsService) UpdateSegment(segment Segment) (bool, *Response, error) {
+	return p.UpdateSegmentWithContext(context.Background(), segment)
+}
+
+func (p *SegmentsService) UpdateSegmentWithContext(ctx context.Context, segment Segment) (bool, *Response, error) {
+	if segment.ID == 0 {
+		return false, nil, ErrRequiredParam("id")
+	}
+	if segment.Constraints == nil {
+		segment.Constraints = []Constraint{}
+	}
+	req, err := p.client.newRequest(ctx, "admin/segments/"+strconv.Itoa(segment.ID), "PUT", segment)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var updateResponse bytes.Buffer
+
+	resp, err := p.client.do(req, &updateResponse)
+	if err != nil {
+		return false, resp, err
+	}
+	return true, resp, nil
+}
+
+func (p *SegmentsService) DeleteSegment(id int) (bool, *Response, error) {
+	return p.DeleteSegmentWithContext(context.Background(), id)
+}
+
+func (p *SegmentsService) DeleteSegmentWithContext(ctx context.Context, id int) (bool, *Response, error) {
+	if id == 0 {
+		return false, nil, ErrRequiredParam("id")
+	}
+	req, err := p.client.newRequest(ctx, "admin/segments/"+strconv.Itoa(id), "DELETE", nil)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var deleteResponse bytes.Buffer
+
+	resp, err := p.client.do(req, &deleteResponse)
+	if err != nil {
+		return false, resp, err
+	}
+	return true, resp, nil
+}
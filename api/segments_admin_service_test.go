@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSegmentsService(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodGet, "admin/segments").Reply(http.StatusOK, `{"segments":[
+		{"id":3,"name":"beta-testers","constraints":[{"contextName":"userId","operator":"IN","values":["1","2"]}]}
+	]}`)
+	mock.On(http.MethodGet, "admin/segments/3").Reply(http.StatusOK, `{"id":3,"name":"beta-testers","constraints":[]}`)
+	mock.On(http.MethodPost, "admin/segments").Reply(http.StatusCreated, `{"id":4,"name":"internal","constraints":[]}`)
+	mock.On(http.MethodPut, "admin/segments/4").Reply(http.StatusNoContent, "")
+	mock.On(http.MethodDelete, "admin/segments/4").Reply(http.StatusNoContent, "")
+
+	all, _, err := client.Segments.GetAllSegments()
+	if err != nil {
+		t.Fatalf("GetAllSegments() error = %v", err)
+	}
+	want := []Segment{{ID: 3, Name: "beta-testers", Constraints: []Constraint{{ContextName: "userId", Operator: OperatorIn, Values: []string{"1", "2"}}}}}
+	if !reflect.DeepEqual(*all, want) {
+		t.Errorf("GetAllSegments() = %+v, want %+v", *all, want)
+	}
+	if segment, _, err := client.Segments.GetSegment(3); err != nil || segment.Name != "beta-testers" {
+		t.Errorf("GetSegment() = %+v, %v", segment, err)
+	}
+
+	created, _, err := client.Segments.CreateSegment(Segment{ID: 3, Name: "internal"})
+	if err != nil || created.ID != 4 {
+		t.Fatalf("CreateSegment() = %+v, %v", created, err)
+	}
+	if ok, _, err := client.Segments.UpdateSegment(*created); !ok || err != nil {
+		t.Errorf("UpdateSegment() = %v, %v", ok, err)
+	}
+	if ok, _, err := client.Segments.DeleteSegment(4); !ok || err != nil {
+		t.Errorf("DeleteSegment() = %v, %v", ok, err)
+	}
+	if _, _, err := client.Segments.UpdateSegment(Segment{Name: "internal"}); err == nil {
+		t.Error("UpdateSegment() without an id error = nil")
+	}
+
+	// ids are assigned by Unleash and constraints are always sent
+	var sent map[string]interface{}
+	if err := mock.RequestsTo(http.MethodPost, "admin/segments")[0].DecodeBody(&sent); err != nil {
+		t.Fatalf("DecodeBody() error = %v", err)
+	}
+	if _, hasID := sent["id"]; hasID || sent["constraints"] == nil {
+		t.Errorf("CreateSegment() sent %v", sent)
+	}
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 )
 
 type AllStrategiesResponse struct {
@@ -32,7 +33,11 @@ type StrategiesService struct {
 }
 
 func (p *StrategiesService) CreateStrategy(strategy Strategy) (*Strategy, *Response, error) {
-	req, err := p.client.newRequest("admin/strategies", "POST", strategy)
+	return p.CreateStrategyWithContext(context.Background(), strategy)
+}
+
+func (p *StrategiesService) CreateStrategyWithContext(ctx context.Context, strategy Strategy) (*Strategy, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/strategies", "POST", strategy)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -47,7 +52,14 @@ func (p *StrategiesService) CreateStrategy(strategy Strategy) (*Strategy, *Respo
 }
 
 func (p *StrategiesService) UpdateStrategy(strategy Strategy) (*Strategy, *Response, error) {
-	req, _ := p.client.newRequest("admin/strategies/"+strategy.Name, "PUT", strategy)
+	return p.UpdateStrategyWithContext(context.Background(), strategy)
+}
+
+func (p *StrategiesService) UpdateStrategyWithContext(ctx context.Context, strategy Strategy) (*Strategy, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/strategies/"+strategy.Name, "PUT", strategy)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	var updatedStrategy Strategy
 
@@ -59,7 +71,14 @@ func (p *StrategiesService) UpdateStrategy(strategy Strategy) (*Strategy, *Respo
 }
 
 func (p *FeatureTogglesService) DeprecateStrategy(strategyName string) (bool, *Response, error) {
-	req, _ := p.client.newRequest("admin/strategies/"+strategyName+"/deprecate", "POST", FeatureToggle{})
+	return p.DeprecateStrategyWithContext(context.Background(), strategyName)
+}
+
+func (p *FeatureTogglesService) DeprecateStrategyWithContext(ctx context.Context, strategyName string) (bool, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/strategies/"+strategyName+"/deprecate", "POST", FeatureToggle{})
+	if err != nil {
+		return false, nil, err
+	}
 
 	var deprecateResponse bytes.Buffer
 
@@ -71,7 +90,14 @@ func (p *FeatureTogglesService) DeprecateStrategy(strategyName string) (bool, *R
 }
 
 func (p *FeatureTogglesService) ReactivateStrategy(strategyName string) (bool, *Response, error) {
-	req, _ := p.client.newRequest("admin/strategies/"+strategyName+"/reactivate", "POST", FeatureToggle{})
+	return p.ReactivateStrategyWithContext(context.Background(), strategyName)
+}
+
+func (p *FeatureTogglesService) ReactivateStrategyWithContext(ctx context.Context, strategyName string) (bool, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/strategies/"+strategyName+"/reactivate", "POST", FeatureToggle{})
+	if err != nil {
+		return false, nil, err
+	}
 
 	var reactivateResponse bytes.Buffer
 
@@ -83,7 +109,14 @@ func (p *FeatureTogglesService) ReactivateStrategy(strategyName string) (bool, *
 }
 
 func (p *StrategiesService) GetAllStrategies() (*AllStrategiesResponse, *Response, error) {
-	req, _ := p.client.newRequest("admin/strategies", "GET", nil)
+	return p.GetAllStrategiesWithContext(context.Background())
+}
+
+func (p *StrategiesService) GetAllStrategiesWithContext(ctx context.Context) (*AllStrategiesResponse, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/strategies", "GET", nil)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	var strategies AllStrategiesResponse
 
@@ -95,7 +128,14 @@ func (p *StrategiesService) GetAllStrategies() (*AllStrategiesResponse, *Respons
 }
 
 func (p *StrategiesService) GetStrategyByName(strategyName string) (*Strategy, *Response, error) {
-	req, _ := p.client.newRequest("admin/strategies/"+strategyName, "GET", nil)
+	return p.GetStrategyByNameWithContext(context.Background(), strategyName)
+}
+
+func (p *StrategiesService) GetStrategyByNameWithContext(ctx context.Context, strategyName string) (*Strategy, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/strategies/"+strategyName, "GET", nil)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	var strategy Strategy
 
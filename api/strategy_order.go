@@ -0,0 +1,107 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+)
+
+type strategySortOrder struct {
+	ID        string `json:"id"`
+	SortOrder int    `json:"sortOrder"`
+}
+
+// SetStrategySortOrder sets the evaluation order of strategies of a feature in
+// an environment in one request. The strategy at index i gets sort order i.
+func (p *FeatureTogglesService) SetStrategySortOrder(projectId string, featureName string, environment string, strategyIds []string) (bool, *Response, error) {
+	return p.SetStrategySortOrderWithContext(context.Background(), projectId, featureName, environment, strategyIds)
+}
+
+func (p *FeatureTogglesService) SetStrategySortOrderWithContext(ctx context.Context, projectId string, featureName string, environment string, strategyIds []string) (bool, *Response, error) {
+	if len(strategyIds) == 0 {
+		return false, nil, ErrRequiredParam("strategyIds")
+	}
+	order := make([]strategySortOrder, len(strategyIds))
+	for i, id := range strategyIds {
+		order[i] = strategySortOrder{ID: id, SortOrder: i}
+	}
+	req, err := p.client.newRequest(ctx, "admin/projects/"+projectId+"/features/"+featureName+"/environments/"+environment+"/strategies/set-sort-order", "POST", order)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var sortResponse bytes.Buffer
+
+	resp, err := p.client.do(req, &sortResponse)
+	if err != nil {
+		return false, resp, err
+	}
+	return true, resp, nil
+}
+
+// MoveStrategy moves a strategy of a feature in an environment by offset
+// places in the evaluation order: up when negative, down when positive. Moves
+// past either end stop there. It returns the new order of strategy ids.
+func (p *FeatureTogglesService) MoveStrategy(projectId string, featureName string, environment string, strategyId string, offset int) ([]string, *Response, error) {
+	return p.MoveStrategyWithContext(context.Background(), projectId, featureName, environment, strategyId, offset)
+}
+
+func (p *FeatureTogglesService) MoveStrategyWithContext(ctx context.Context, projectId string, featureName string, environment string, strategyId string, offset int) ([]string, *Response, error) {
+	if strategyId == "" {
+		return nil, nil, ErrRequiredParam("strategyId")
+	}
+	feature, resp, err := p.GetFeatureByNameWithContext(ctx, projectId, featureName)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var strategies []FeatureStrategy
+	found := false
+	for _, env := range feature.Environments {
+		if env.Name == environment {
+			strategies, found = SortedStrategies(env.Strategies), true
+			break
+		}
+	}
+	if !found {
+		return nil, resp, fmt.Errorf("feature %s has no environment %s: %w", featureName, environment, ErrNotFound)
+	}
+
+	ids := make([]string, 0, len(strategies))
+	from := -1
+	for i, strategy := range strategies {
+		ids = append(ids, strategy.ID)
+		if strategy.ID == strategyId {
+			from = i
+		}
+	}
+	if from < 0 {
+		return nil, resp, fmt.Errorf("feature %s has no strategy %s in %s: %w", featureName, strategyId, environment, ErrNotFound)
+	}
+
+	to := from + offset
+	if to < 0 {
+		to = 0
+	}
+	if to > len(ids)-1 {
+		to = len(ids) - 1
+	}
+	ids = append(ids[:from], ids[from+1:]...)
+	ids = append(ids[:to], append([]string{strategyId}, ids[to:]...)...)
+
+	if _, resp, err = p.SetStrategySortOrderWithContext(ctx, projectId, featureName, environment, ids); err != nil {
+		return nil, resp, err
+	}
+	return ids, resp, nil
+}
+
+// SortedStrategies returns a copy of strategies in evaluation order, ordered
+// by SortOrder with ties kept in their original order.
+func SortedStrategies(strategies []FeatureStrategy) []FeatureStrategy {
+	sorted := append([]FeatureStrategy(nil), strategies...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].SortOrder < sorted[j].SortOrder
+	})
+	return sorted
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestFeatureTogglesService_MoveStrategy(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodGet, "admin/projects/default/features/checkout").Reply(http.StatusOK, `{"name":"checkout","environments":[
+		{"name":"production","strategies":[{"id":"c","sortOrder":7},{"id":"a","sortOrder":1},{"id":"b","sortOrder":3}]}
+	]}`)
+	mock.On(http.MethodPost, "admin/projects/default/features/checkout/environments/production/strategies/set-sort-order").Reply(http.StatusOK, "")
+
+	ids, _, err := client.FeatureToggles.MoveStrategy("default", "checkout", "production", "c", -1)
+	if err != nil {
+		t.Fatalf("MoveStrategy() error = %v", err)
+	}
+	if want := []string{"a", "c", "b"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("MoveStrategy() = %v, want %v", ids, want)
+	}
+
+	var sent []strategySortOrder
+	if err := mock.Requests()[1].DecodeBody(&sent); err != nil {
+		t.Fatalf("DecodeBody() error = %v", err)
+	}
+	want := []strategySortOrder{{ID: "a", SortOrder: 0}, {ID: "c", SortOrder: 1}, {ID: "b", SortOrder: 2}}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("MoveStrategy() sent %+v, want %+v", sent, want)
+	}
+}
@@ -0,0 +1,242 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Names of the activation strategies built into Unleash.
+const (
+	StrategyDefault             = "default"
+	StrategyFlexibleRollout     = "flexibleRollout"
+	StrategyUserWithId          = "userWithId"
+	StrategyRemoteAddress       = "remoteAddress"
+	StrategyApplicationHostname = "applicationHostname"
+)
+
+// StrategyParameters is implemented by the typed parameters of the built-in
+// strategies. Values marshal into the exact string-encoded shape Unleash
+// expects and can be assigned to FeatureStrategy.Parameters directly.
+type StrategyParameters interface {
+	StrategyName() string
+	Validate() error
+}
+
+// NewFeatureStrategy returns a strategy for the built-in strategy described
+// by params.
+func NewFeatureStrategy(params StrategyParameters) FeatureStrategy {
+	return FeatureStrategy{
+		Name:       params.StrategyName(),
+		Parameters: params,
+	}
+}
+
+// DecodeParameters decodes the strategy parameters into v, typically a pointer
+// to one of the typed parameter structs such as *FlexibleRolloutParams.
+func (s FeatureStrategy) DecodeParameters(v interface{}) error {
+	if params, ok := v.(StrategyParameters); ok && params.StrategyName() != s.Name {
+		return fmt.Errorf("cannot decode parameters of strategy %s into %s parameters", s.Name, params.StrategyName())
+	}
+	data, err := json.Marshal(s.Parameters)
+	if err != nil {
+		return err
+	}
+	if string(data) == "null" {
+		data = []byte("{}")
+	}
+	return json.Unmarshal(data, v)
+}
+
+// FlexibleRolloutParams are the parameters of the flexibleRollout strategy.
+type FlexibleRolloutParams struct {
+	// Rollout is the percentage of contexts, between 0 and 100, that get the feature.
+	Rollout int
+	// Stickiness is the context field used to bucket contexts: default,
+	// userId, sessionId, random or a custom field.
+	Stickiness string
+	// GroupId buckets contexts consistently across features sharing it. When
+	// it is empty the groupId parameter is left out and the SDKs bucket by
+	// the feature name.
+	GroupId string
+}
+
+func (p FlexibleRolloutParams) StrategyName() string {
+	return StrategyFlexibleRollout
+}
+
+func (p FlexibleRolloutParams) Validate() error {
+	if p.Rollout < 0 || p.Rollout > 100 {
+		return fmt.Errorf("flexibleRollout rollout must be between 0 and 100, got %d", p.Rollout)
+	}
+	return nil
+}
+
+func (p FlexibleRolloutParams) MarshalJSON() ([]byte, error) {
+	stickiness := p.Stickiness
+	if stickiness == "" {
+		stickiness = "default"
+	}
+	params := map[string]string{
+		"rollout":    strconv.Itoa(p.Rollout),
+		"stickiness": stickiness,
+	}
+	// Unleash stores an empty groupId as is
+	if p.GroupId != "" {
+		params["groupId"] = p.GroupId
+	}
+	return json.Marshal(params)
+}
+
+func (p *FlexibleRolloutParams) UnmarshalJSON(data []byte) error {
+	var raw map[string]paramValue
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	rollout, err := raw["rollout"].int("rollout")
+	if err != nil {
+		return err
+	}
+	*p = FlexibleRolloutParams{
+		Rollout:    rollout,
+		Stickiness: string(raw["stickiness"]),
+		GroupId:    string(raw["groupId"]),
+	}
+	return nil
+}
+
+// UserWithIdParams are the parameters of the userWithId strategy.
+type UserWithIdParams struct {
+	UserIds []string
+}
+
+func (p UserWithIdParams) StrategyName() string {
+	return StrategyUserWithId
+}
+
+func (p UserWithIdParams) Validate() error {
+	return validateList(StrategyUserWithId, "userIds", p.UserIds)
+}
+
+func (p UserWithIdParams) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"userIds": strings.Join(p.UserIds, ",")})
+}
+
+func (p *UserWithIdParams) UnmarshalJSON(data []byte) error {
+	var raw map[string]paramValue
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*p = UserWithIdParams{UserIds: raw["userIds"].list()}
+	return nil
+}
+
+// RemoteAddressParams are the parameters of the remoteAddress strategy. IPs
+// may hold single addresses or CIDR ranges.
+type RemoteAddressParams struct {
+	IPs []string
+}
+
+func (p RemoteAddressParams) StrategyName() string {
+	return StrategyRemoteAddress
+}
+
+func (p RemoteAddressParams) Validate() error {
+	return validateList(StrategyRemoteAddress, "IPs", p.IPs)
+}
+
+func (p RemoteAddressParams) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"IPs": strings.Join(p.IPs, ",")})
+}
+
+func (p *RemoteAddressParams) UnmarshalJSON(data []byte) error {
+	var raw map[string]paramValue
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*p = RemoteAddressParams{IPs: raw["IPs"].list()}
+	return nil
+}
+
+// ApplicationHostnameParams are the parameters of the applicationHostname
+// strategy.
+type ApplicationHostnameParams struct {
+	HostNames []string
+}
+
+func (p ApplicationHostnameParams) StrategyName() string {
+	return StrategyApplicationHostname
+}
+
+func (p ApplicationHostnameParams) Validate() error {
+	return validateList(StrategyApplicationHostname, "hostNames", p.HostNames)
+}
+
+func (p ApplicationHostnameParams) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"hostNames": strings.Join(p.HostNames, ",")})
+}
+
+func (p *ApplicationHostnameParams) UnmarshalJSON(data []byte) error {
+	var raw map[string]paramValue
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*p = ApplicationHostnameParams{HostNames: raw["hostNames"].list()}
+	return nil
+}
+
+func validateList(strategy string, param string, values []string) error {
+	if len(values) == 0 {
+		return fmt.Errorf("%s needs at least one entry in %s", strategy, param)
+	}
+	for _, value := range values {
+		if strings.Contains(value, ",") {
+			return fmt.Errorf("%s %s entries cannot contain commas: %q", strategy, param, value)
+		}
+	}
+	return nil
+}
+
+// paramValue decodes a strategy parameter that Unleash may send as a string,
+// a number or a boolean.
+type paramValue string
+
+func (v *paramValue) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*v = paramValue(s)
+		return nil
+	}
+	var other interface{}
+	if err := json.Unmarshal(data, &other); err != nil {
+		return err
+	}
+	if other == nil {
+		*v = ""
+		return nil
+	}
+	*v = paramValue(fmt.Sprint(other))
+	return nil
+}
+
+func (v paramValue) int(name string) (int, error) {
+	if v == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(string(v), 64)
+	if err != nil {
+		return 0, fmt.Errorf("parameter %s must be a number, got %q", name, string(v))
+	}
+	return int(f), nil
+}
+
+func (v paramValue) list() []string {
+	var values []string
+	for _, value := range strings.Split(string(v), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
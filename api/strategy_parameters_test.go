@@ -0,0 +1,112 @@
+package api
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestStrategyParameters_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		params StrategyParameters
+		want   string
+	}{
+		{
+			"FlexibleRollout",
+			FlexibleRolloutParams{Rollout: 25, GroupId: "checkout"},
+			`{"groupId":"checkout","rollout":"25","stickiness":"default"}`,
+		},
+		{
+			"FlexibleRolloutWithoutGroupId",
+			FlexibleRolloutParams{Rollout: 25, Stickiness: "userId"},
+			`{"rollout":"25","stickiness":"userId"}`,
+		},
+		{
+			"UserWithId",
+			UserWithIdParams{UserIds: []string{"alice", "bob"}},
+			`{"userIds":"alice,bob"}`,
+		},
+		{
+			"RemoteAddress",
+			RemoteAddressParams{IPs: []string{"10.0.0.1", "192.168.0.0/16"}},
+			`{"IPs":"10.0.0.1,192.168.0.0/16"}`,
+		},
+		{
+			"ApplicationHostname",
+			ApplicationHostnameParams{HostNames: []string{"web-1"}},
+			`{"hostNames":"web-1"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy := NewFeatureStrategy(tt.params)
+			if err := strategy.Validate(); err != nil {
+				t.Fatalf("FeatureStrategy.Validate() error = %v", err)
+			}
+			got, err := json.Marshal(strategy.Parameters)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("json.Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeatureStrategy_DecodeParameters(t *testing.T) {
+	var feature FeatureToggle
+	err := json.Unmarshal([]byte(`{
+		"name": "checkout",
+		"environments": [{
+			"name": "production",
+			"strategies": [
+				{"name": "flexibleRollout", "parameters": {"rollout": 40, "stickiness": "userId", "groupId": "checkout"}},
+				{"name": "userWithId", "parameters": {"userIds": "alice, bob,,carol"}}
+			]
+		}]
+	}`), &feature)
+	if err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	strategies := feature.Environments[0].Strategies
+
+	var rollout FlexibleRolloutParams
+	if err := strategies[0].DecodeParameters(&rollout); err != nil {
+		t.Fatalf("DecodeParameters() error = %v", err)
+	}
+	if want := (FlexibleRolloutParams{Rollout: 40, Stickiness: "userId", GroupId: "checkout"}); rollout != want {
+		t.Errorf("DecodeParameters() got = %+v, want %+v", rollout, want)
+	}
+
+	var users UserWithIdParams
+	if err := strategies[1].DecodeParameters(&users); err != nil {
+		t.Fatalf("DecodeParameters() error = %v", err)
+	}
+	if want := []string{"alice", "bob", "carol"}; !reflect.DeepEqual(users.UserIds, want) {
+		t.Errorf("DecodeParameters() got = %v, want %v", users.UserIds, want)
+	}
+
+	if err := strategies[1].DecodeParameters(&rollout); err == nil {
+		t.Error("DecodeParameters() into the parameters of another strategy expected an error")
+	}
+}
+
+func TestFeatureStrategy_ValidateParameters(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy FeatureStrategy
+	}{
+		{"RolloutAboveHundred", NewFeatureStrategy(FlexibleRolloutParams{Rollout: 150})},
+		{"EmptyUserIds", NewFeatureStrategy(UserWithIdParams{})},
+		{"MismatchedName", FeatureStrategy{Name: StrategyDefault, Parameters: RemoteAddressParams{IPs: []string{"10.0.0.1"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.strategy.Validate(); err == nil {
+				t.Error("FeatureStrategy.Validate() expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"bytes"
+	"context"
+)
+
+// TagType is a kind of feature tag, such as simple or slack.
+type TagType struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Icon        string `json:"icon,omitempty"`
+}
+
+type allTagTypesResponse struct {
+	Version  int       `json:"version"`
+	TagTypes []TagType `json:"tagTypes"`
+}
+
+type tagTypeResponse struct {
+	Version int     `json:"version"`
+	TagType TagType `json:"tagType"`
+}
+
+type TagTypesService struct {
+	client *ApiClient
+}
+
+// GetAllTagTypes returns every tag type of the instance.
+func (p *TagTypesService) GetAllTagTypes() (*[]TagType, *Response, error) {
+	return p.GetAllTagTypesWithContext(context.Background())
+}
+
+func (p *TagTypesService) GetAllTagTypesWithContext(ctx context.Context) (*[]TagType, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/tag-types", "GET", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tagTypes allTagTypesResponse
+
+	resp, err := p.client.do(req, &tagTypes)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &tagTypes.TagTypes, resp, err
+}
+
+func (p *TagTypesService) GetTagType(name string) (*TagType, *Response, error) {
+	return p.GetTagTypeWithContext(context.Background(), name)
+}
+
+func (p *TagTypesService) GetTagTypeWithContext(ctx context.Context, name string) (*TagType, *Response, error) {
+	if name == "" {
+		return nil, nil, ErrRequiredParam("name")
+	}
+	req, err := p.client.newRequest(ctx, "admin/tag-types/"+name, "GET", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tagType tagTypeResponse
+
+	resp, err := p.client.do(req, &tagType)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &tagType.TagType, resp, err
+}
+
+func (p *TagTypesService) CreateTagType(tagType TagType) (*TagType, *Response, error) {
+	return p.CreateTagTypeWithContext(context.Background(), tagType)
+}
+
+func (p *TagTypesService) CreateTagTypeWithContext(ctx context.Context, tagType TagType) (*TagType, *Response, error) {
+	if tagType.Name == "" {
+		return nil, nil, ErrRequiredParam("name")
+	}
+	req, err := p.client.newRequest(ctx, "admin/tag-types", "POST", tagType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var created TagType
+
+	resp, err := p.client.do(req, &created)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &created, resp, err
+}
+
+// UpdateTagType changes the description and icon of a tag type.
+func (p *TagTypesService) UpdateTagType(tagType TagType) (*TagType, *Response, error) {
+	return p.UpdateTagTypeWithContext(context.Background(), tagType)
+}
+
+func (p *TagTypesService) UpdateTagTypeWithContext(ctx context.Context, tagType TagType) (*TagType, *Response, error) {
+	if tagType.Name == "" {
+		return nil, nil, ErrRequiredParam("name")
+	}
+	req, err := p.client.newRequest(ctx, "admin/tag-types/"+tagType.Name, "PUT", tagType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var updated TagType
+
+	resp, err := p.client.do(req, &updated)
+	if err != nil {
+		return nil, resp, err
+	}
+	return &updated, resp, err
+}
+
+func (p *TagTypesService) DeleteTagType(name string) (bool, *Response, error) {
+	return p.DeleteTagTypeWithContext(context.Background(), name)
+}
+
+func (p *TagTypesService) DeleteTagTypeWithContext(ctx context.Context, name string) (bool, *Response, error) {
+	if name == "" {
+		return false, nil, ErrRequiredParam("name")
+	}
+	req, err := p.client.newRequest(ctx, "admin/tag-types/"+name, "DELETE", nil)
+	if err != nil {
+		return false, nil, err
+	}
+
+	var deleteResponse bytes.Buffer
+
+	resp, err := p.client.do(req, &deleteResponse)
+	if err != nil {
+		return false, resp, err
+	}
+	return true, resp, nil
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestTagTypesService(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodGet, "admin/tag-types").Reply(http.StatusOK, `{"version":1,"tagTypes":[
+		{"name":"simple","description":"Used to simplify filtering of features","icon":"#"},
+		{"name":"team","description":"Owning team"}
+	]}`)
+	mock.On(http.MethodGet, "admin/tag-types/team").Reply(http.StatusOK, `{"version":1,"tagType":{"name":"team","description":"Owning team"}}`)
+	mock.On(http.MethodGet, "admin/tag-types/missing").Reply(http.StatusNotFound, `{"name":"NotFoundError","message":"Could not find tag-type with name: missing"}`)
+	mock.On(http.MethodPost, "admin/tag-types").Reply(http.StatusCreated, `{"name":"team","description":"Owning team"}`)
+	mock.On(http.MethodPut, "admin/tag-types/team").Reply(http.StatusOK, `{"name":"team","description":"Owning squad"}`)
+	mock.On(http.MethodDelete, "admin/tag-types/team").Reply(http.StatusOK, "")
+
+	all, _, err := client.TagTypes.GetAllTagTypes()
+	if err != nil {
+		t.Fatalf("GetAllTagTypes() error = %v", err)
+	}
+	want := []TagType{{Name: "simple", Description: "Used to simplify filtering of features", Icon: "#"}, {Name: "team", Description: "Owning team"}}
+	if !reflect.DeepEqual(*all, want) {
+		t.Errorf("GetAllTagTypes() = %+v, want %+v", *all, want)
+	}
+	if tagType, _, err := client.TagTypes.GetTagType("team"); err != nil || tagType.Description != "Owning team" {
+		t.Errorf("GetTagType() = %+v, %v", tagType, err)
+	}
+	if _, _, err := client.TagTypes.GetTagType("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetTagType(missing) error = %v, want ErrNotFound", err)
+	}
+	if _, _, err := client.TagTypes.CreateTagType(TagType{Name: "team", Description: "Owning team"}); err != nil {
+		t.Errorf("CreateTagType() error = %v", err)
+	}
+	if updated, _, err := client.TagTypes.UpdateTagType(TagType{Name: "team", Description: "Owning squad"}); err != nil || updated.Description != "Owning squad" {
+		t.Errorf("UpdateTagType() = %+v, %v", updated, err)
+	}
+	if ok, _, err := client.TagTypes.DeleteTagType("team"); !ok || err != nil {
+		t.Errorf("DeleteTagType() = %v, %v", ok, err)
+	}
+	if _, _, err := client.TagTypes.CreateTagType(TagType{}); err == nil {
+		t.Error("CreateTagType() without a name error = nil")
+	}
+
+	var sent TagType
+	if err := mock.RequestsTo(http.MethodPut, "admin/tag-types/team")[0].DecodeBody(&sent); err != nil || sent.Description != "Owning squad" {
+		t.Errorf("UpdateTagType() sent %+v, %v", sent, err)
+	}
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 )
 
 type UserDetails struct {
@@ -29,10 +30,17 @@ type UsersService struct {
 }
 
 func (p *UsersService) GetUserById(userId string) (*UserDetails, *Response, error) {
+	return p.GetUserByIdWithContext(context.Background(), userId)
+}
+
+func (p *UsersService) GetUserByIdWithContext(ctx context.Context, userId string) (*UserDetails, *Response, error) {
 	if userId == "" {
 		return nil, nil, ErrRequiredParam("userId")
 	}
-	req, _ := p.client.newRequest("admin/user-admin/"+userId, "GET", nil)
+	req, err := p.client.newRequest(ctx, "admin/user-admin/"+userId, "GET", nil)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	var user UserDetails
 
@@ -44,7 +52,14 @@ func (p *UsersService) GetUserById(userId string) (*UserDetails, *Response, erro
 }
 
 func (p *UsersService) CreateUser(user User) (*UserDetails, *Response, error) {
-	req, _ := p.client.newRequest("admin/user-admin", "POST", user)
+	return p.CreateUserWithContext(context.Background(), user)
+}
+
+func (p *UsersService) CreateUserWithContext(ctx context.Context, user User) (*UserDetails, *Response, error) {
+	req, err := p.client.newRequest(ctx, "admin/user-admin", "POST", user)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	var userDetails UserDetails
 
@@ -56,10 +71,14 @@ func (p *UsersService) CreateUser(user User) (*UserDetails, *Response, error) {
 }
 
 func (p *UsersService) UpdateUser(userId string, user User) (*UserDetails, *Response, error) {
+	return p.UpdateUserWithContext(context.Background(), userId, user)
+}
+
+func (p *UsersService) UpdateUserWithContext(ctx context.Context, userId string, user User) (*UserDetails, *Response, error) {
 	if userId == "" {
 		return nil, nil, ErrRequiredParam("userId")
 	}
-	req, err := p.client.newRequest("admin/user-admin/"+userId, "PUT", user)
+	req, err := p.client.newRequest(ctx, "admin/user-admin/"+userId, "PUT", user)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -74,25 +93,39 @@ func (p *UsersService) UpdateUser(userId string, user User) (*UserDetails, *Resp
 }
 
 func (p *UsersService) DeleteUser(userId string) (bool, *Response, error) {
+	return p.DeleteUserWithContext(context.Background(), userId)
+}
+
+func (p *UsersService) DeleteUserWithContext(ctx context.Context, userId string) (bool, *Response, error) {
 	if userId == "" {
 		return false, nil, ErrRequiredParam("userId")
 	}
-	req, _ := p.client.newRequest("admin/user-admin/"+userId, "DELETE", nil)
+	req, err := p.client.newRequest(ctx, "admin/user-admin/"+userId, "DELETE", nil)
+	if err != nil {
+		return false, nil, err
+	}
 
 	var deleteResponse bytes.Buffer
 
 	resp, err := p.client.do(req, &deleteResponse)
-	if resp == nil {
+	if err != nil {
 		return false, resp, err
 	}
 	return true, resp, nil
 }
 
 func (p *UsersService) SearchUser(query string) (*[]UserDetails, *Response, error) {
+	return p.SearchUserWithContext(context.Background(), query)
+}
+
+func (p *UsersService) SearchUserWithContext(ctx context.Context, query string) (*[]UserDetails, *Response, error) {
 	if query == "" {
 		return nil, nil, ErrRequiredParam("query")
 	}
-	req, _ := p.client.newRequest("admin/user-admin/search?q="+query, "GET", nil)
+	req, err := p.client.newRequest(ctx, "admin/user-admin/search?q="+query, "GET", nil)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	var users []UserDetails
 
@@ -102,3 +135,22 @@ func (p *UsersService) SearchUser(query string) (*[]UserDetails, *Response, erro
 	}
 	return &users, resp, err
 }
+
+// IterateSearchUser iterates over the users matching query. Unleash returns
+// them all at once, so the iterator yields a single page.
+func (p *UsersService) IterateSearchUser(query string) *UserIterator {
+	return p.IterateSearchUserWithContext(context.Background(), query)
+}
+
+func (p *UsersService) IterateSearchUserWithContext(ctx context.Context, query string) *UserIterator {
+	it := &UserIterator{}
+	it.pages = newPageIterator(ctx, 0, 0, func(ctx context.Context, offset int, limit int) (int, int, error) {
+		users, _, err := p.SearchUserWithContext(ctx, query)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = *users
+		return len(it.page), len(it.page), nil
+	})
+	return it
+}
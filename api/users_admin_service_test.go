@@ -0,0 +1,21 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestUsersService_DeleteUser(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodDelete, "admin/user-admin/1").Reply(http.StatusOK, "")
+	mock.On(http.MethodDelete, "admin/user-admin/2").Reply(http.StatusNotFound, `{"name":"NotFoundError","message":"no such user"}`)
+
+	if ok, _, err := client.Users.DeleteUser("1"); !ok || err != nil {
+		t.Errorf("DeleteUser(1) = %v, %v, want true, nil", ok, err)
+	}
+	ok, resp, err := client.Users.DeleteUser("2")
+	if ok || !errors.Is(err, ErrNotFound) || resp == nil {
+		t.Errorf("DeleteUser(2) = %v, %v, %v, want false with ErrNotFound and the response", ok, resp, err)
+	}
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// NewJSONPayload returns a json payload holding v encoded as JSON.
+func NewJSONPayload(v interface{}) (*VariantPayload, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return &VariantPayload{Type: PayloadTypeJSON, Value: string(data)}, nil
+}
+
+// NewCSVPayload returns a csv payload holding values as a single record,
+// quoting values that contain commas or quotes.
+func NewCSVPayload(values []string) (*VariantPayload, error) {
+	if len(values) == 0 {
+		return nil, ErrRequiredParam("values")
+	}
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(values); err != nil {
+		return nil, err
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return &VariantPayload{Type: PayloadTypeCSV, Value: strings.TrimSuffix(buf.String(), "\n")}, nil
+}
+
+// NewNumberPayload returns a number payload holding f.
+func NewNumberPayload(f float64) (*VariantPayload, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("payload number must be finite, got %v", f)
+	}
+	return &VariantPayload{Type: PayloadTypeNumber, Value: strconv.FormatFloat(f, 'f', -1, 64)}, nil
+}
+
+// NewStringPayload returns a string payload holding s, which must not be
+// empty.
+func NewStringPayload(s string) (*VariantPayload, error) {
+	if s == "" {
+		return nil, ErrRequiredParam("s")
+	}
+	return &VariantPayload{Type: PayloadTypeString, Value: s}, nil
+}
+
+// DecodeJSON decodes a json payload into v.
+func (p VariantPayload) DecodeJSON(v interface{}) error {
+	if err := p.expectType(PayloadTypeJSON); err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(p.Value), v); err != nil {
+		return fmt.Errorf("decoding json payload: %w", err)
+	}
+	return nil
+}
+
+// CSV returns the values of a csv payload. Records on separate lines are
+// flattened into one list and surrounding spaces are trimmed.
+func (p VariantPayload) CSV() ([]string, error) {
+	if err := p.expectType(PayloadTypeCSV); err != nil {
+		return nil, err
+	}
+	values, err := readCSVPayload(p.Value)
+	if err != nil {
+		return nil, fmt.Errorf("decoding csv payload: %w", err)
+	}
+	return values, nil
+}
+
+// Number returns the value of a number payload.
+func (p VariantPayload) Number() (float64, error) {
+	if err := p.expectType(PayloadTypeNumber); err != nil {
+		return 0, err
+	}
+	f, err := parseNumberPayload(p.Value)
+	if err != nil {
+		return 0, fmt.Errorf("decoding number payload: %w", err)
+	}
+	return f, nil
+}
+
+func (p VariantPayload) expectType(want string) error {
+	if p.Type != want {
+		return fmt.Errorf("%w: want %s, got %q", ErrPayloadTypeMismatch, want, p.Type)
+	}
+	return nil
+}
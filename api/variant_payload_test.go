@@ -0,0 +1,123 @@
+package api
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestVariantPayload_JSON(t *testing.T) {
+	type banner struct {
+		Title string `json:"title"`
+		Color string `json:"color"`
+	}
+	payload, err := NewJSONPayload(banner{Title: "Sale", Color: "red"})
+	if err != nil {
+		t.Fatalf("NewJSONPayload() error = %v", err)
+	}
+	if payload.Type != PayloadTypeJSON || payload.Validate() != nil {
+		t.Errorf("NewJSONPayload() = %+v", payload)
+	}
+
+	var got banner
+	if err := payload.DecodeJSON(&got); err != nil {
+		t.Fatalf("DecodeJSON() error = %v", err)
+	}
+	if got != (banner{Title: "Sale", Color: "red"}) {
+		t.Errorf("DecodeJSON() got = %+v", got)
+	}
+
+	broken := VariantPayload{Type: PayloadTypeJSON, Value: `{"title":`}
+	if err := broken.DecodeJSON(&got); err == nil || errors.Is(err, ErrPayloadTypeMismatch) {
+		t.Errorf("DecodeJSON() on invalid JSON error = %v, want a decoding error", err)
+	}
+}
+
+func TestVariantPayload_CSV(t *testing.T) {
+	payload, err := NewCSVPayload([]string{"eu-west", "us-east", "a,b"})
+	if err != nil {
+		t.Fatalf("NewCSVPayload() error = %v", err)
+	}
+	if payload.Value != `eu-west,us-east,"a,b"` {
+		t.Errorf("NewCSVPayload() value = %q", payload.Value)
+	}
+	got, err := payload.CSV()
+	if err != nil {
+		t.Fatalf("CSV() error = %v", err)
+	}
+	if want := []string{"eu-west", "us-east", "a,b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("CSV() = %q, want %q", got, want)
+	}
+
+	multiline := VariantPayload{Type: PayloadTypeCSV, Value: "a, b\nc"}
+	if got, err := multiline.CSV(); err != nil || !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("CSV() = %q, %v", got, err)
+	}
+	if _, err := NewCSVPayload(nil); err == nil {
+		t.Error("NewCSVPayload() without values succeeded")
+	}
+}
+
+func TestVariantPayload_Number(t *testing.T) {
+	payload, err := NewNumberPayload(12.5)
+	if err != nil {
+		t.Fatalf("NewNumberPayload() error = %v", err)
+	}
+	if payload.Value != "12.5" {
+		t.Errorf("NewNumberPayload() value = %q", payload.Value)
+	}
+	if got, err := payload.Number(); err != nil || got != 12.5 {
+		t.Errorf("Number() = %v, %v", got, err)
+	}
+	invalid := VariantPayload{Type: PayloadTypeNumber, Value: "twelve"}
+	if _, err := invalid.Number(); err == nil {
+		t.Error("Number() on a non-numeric value succeeded")
+	}
+}
+
+func TestVariantPayload_TypeMismatch(t *testing.T) {
+	payload, err := NewStringPayload("hello")
+	if err != nil {
+		t.Fatalf("NewStringPayload() error = %v", err)
+	}
+	if _, err := payload.Number(); !errors.Is(err, ErrPayloadTypeMismatch) {
+		t.Errorf("Number() error = %v, want ErrPayloadTypeMismatch", err)
+	}
+	if _, err := payload.CSV(); !errors.Is(err, ErrPayloadTypeMismatch) {
+		t.Errorf("CSV() error = %v, want ErrPayloadTypeMismatch", err)
+	}
+	var v interface{}
+	if err := payload.DecodeJSON(&v); !errors.Is(err, ErrPayloadTypeMismatch) {
+		t.Errorf("DecodeJSON() error = %v, want ErrPayloadTypeMismatch", err)
+	}
+}
+
+func TestVariantPayload_ValidateMatchesDecoders(t *testing.T) {
+	payloads := []VariantPayload{
+		{Type: PayloadTypeCSV, Value: "a, b\nc"},
+		{Type: PayloadTypeCSV, Value: " eu-west, \"a,b\""},
+		{Type: PayloadTypeNumber, Value: " 12.5 "},
+	}
+	for _, payload := range payloads {
+		if err := payload.Validate(); err != nil {
+			t.Errorf("Validate(%q) error = %v", payload.Value, err)
+		}
+	}
+	if _, err := payloads[0].CSV(); err != nil {
+		t.Errorf("CSV() error = %v", err)
+	}
+	if _, err := payloads[2].Number(); err != nil {
+		t.Errorf("Number() error = %v", err)
+	}
+
+	invalid := VariantPayload{Type: PayloadTypeCSV, Value: `a,"b`}
+	if invalid.Validate() == nil {
+		t.Error("Validate() accepted an unterminated quote")
+	}
+	if _, err := invalid.CSV(); err == nil {
+		t.Error("CSV() decoded an unterminated quote")
+	}
+	if _, err := NewStringPayload(""); err == nil {
+		t.Error("NewStringPayload() with an empty value succeeded")
+	}
+}
@@ -0,0 +1,273 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TotalVariantWeight is the weight all variants of a feature add up to.
+const TotalVariantWeight = 1000
+
+// Variant weight types.
+const (
+	WeightTypeVariable = "variable"
+	WeightTypeFix      = "fix"
+)
+
+// Variant payload types.
+const (
+	PayloadTypeJSON   = "json"
+	PayloadTypeCSV    = "csv"
+	PayloadTypeString = "string"
+	PayloadTypeNumber = "number"
+)
+
+// Stickiness values every Unleash instance understands. Custom context fields
+// can be used as well once they are passed to ValidateVariants.
+const (
+	StickinessDefault   = "default"
+	StickinessUserId    = "userId"
+	StickinessSessionId = "sessionId"
+	StickinessRandom    = "random"
+)
+
+// VariantError is a problem with a single variant.
+type VariantError struct {
+	// Index is the position of the variant, or -1 for problems with the set.
+	Index   int
+	Name    string
+	Message string
+}
+
+func (e VariantError) Error() string {
+	if e.Index < 0 {
+		return e.Message
+	}
+	if e.Name == "" {
+		return fmt.Sprintf("variant %d: %s", e.Index, e.Message)
+	}
+	return fmt.Sprintf("variant %d (%s): %s", e.Index, e.Name, e.Message)
+}
+
+// VariantsError reports every problem found by ValidateVariants. It matches
+// ErrInvalidVariants with errors.Is.
+type VariantsError struct {
+	Errors []VariantError
+}
+
+func (e *VariantsError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%v: %s", ErrInvalidVariants, strings.Join(messages, "; "))
+}
+
+func (e *VariantsError) Is(target error) bool {
+	return target == ErrInvalidVariants
+}
+
+func (e *VariantsError) add(index int, name string, format string, args ...interface{}) {
+	e.Errors = append(e.Errors, VariantError{Index: index, Name: name, Message: fmt.Sprintf(format, args...)})
+}
+
+// ValidateVariants checks variants the way Unleash does before storing them:
+// names must be present and unique, weight types, stickiness and payloads must
+// be well formed and fixed weights must leave room for the variable variants.
+// Stickiness must be one of the built-in values or one of customStickiness.
+// All problems are reported at once in a *VariantsError.
+func ValidateVariants(variants []Variant, customStickiness ...string) error {
+	verr := &VariantsError{}
+	names := make(map[string]bool)
+	fixedWeight := 0
+	variableCount := 0
+	stickiness := ""
+
+	for i, variant := range variants {
+		if variant.Name == "" {
+			verr.add(i, "", "name is required")
+		} else if names[variant.Name] {
+			verr.add(i, variant.Name, "name is used by another variant")
+		}
+		names[variant.Name] = true
+
+		switch variant.WeightType {
+		case WeightTypeFix:
+			if variant.Weight < 0 || variant.Weight > TotalVariantWeight {
+				verr.add(i, variant.Name, "fixed weight must be between 0 and %d, got %d", TotalVariantWeight, variant.Weight)
+			}
+			fixedWeight += variant.Weight
+		case "", WeightTypeVariable:
+			variableCount++
+		default:
+			verr.add(i, variant.Name, "unknown weight type %q", variant.WeightType)
+			variableCount++
+		}
+
+		variantStickiness := variant.Stickiness
+		if variantStickiness == "" {
+			variantStickiness = StickinessDefault
+		}
+		if !knownStickiness(variantStickiness, customStickiness) {
+			verr.add(i, variant.Name, "unknown stickiness %q", variant.Stickiness)
+		}
+		if i == 0 {
+			stickiness = variantStickiness
+		} else if variantStickiness != stickiness {
+			verr.add(i, variant.Name, "stickiness %q differs from %q used by the first variant", variantStickiness, stickiness)
+		}
+
+		for _, override := range variant.Overrides {
+			if override.ContextName == "" {
+				verr.add(i, variant.Name, "override contextName is required")
+			} else if len(override.Values) == 0 {
+				verr.add(i, variant.Name, "override on %s needs at least one value", override.ContextName)
+			}
+		}
+
+		if variant.Payload != nil {
+			if err := variant.Payload.Validate(); err != nil {
+				verr.add(i, variant.Name, "%v", err)
+			}
+		}
+	}
+
+	if fixedWeight > TotalVariantWeight {
+		verr.add(-1, "", "fixed weights add up to %d, more than %d", fixedWeight, TotalVariantWeight)
+	} else if variableCount == 0 && len(variants) > 0 && fixedWeight != TotalVariantWeight {
+		verr.add(-1, "", "fixed weights add up to %d but must add up to %d when there are no variable variants", fixedWeight, TotalVariantWeight)
+	}
+
+	if len(verr.Errors) > 0 {
+		return verr
+	}
+	return nil
+}
+
+func knownStickiness(stickiness string, custom []string) bool {
+	switch stickiness {
+	case StickinessDefault, StickinessUserId, StickinessSessionId, StickinessRandom:
+		return true
+	}
+	for _, field := range custom {
+		if field == stickiness {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that the payload type is known and that the value parses as
+// that type.
+func (p VariantPayload) Validate() error {
+	if p.Value == "" {
+		return fmt.Errorf("payload of type %q needs a value", p.Type)
+	}
+	switch p.Type {
+	case PayloadTypeString:
+	case PayloadTypeJSON:
+		if !json.Valid([]byte(p.Value)) {
+			return fmt.Errorf("payload of type json is not valid JSON: %q", p.Value)
+		}
+	case PayloadTypeCSV:
+		if _, err := readCSVPayload(p.Value); err != nil {
+			return fmt.Errorf("payload of type csv is not valid CSV: %v", err)
+		}
+	case PayloadTypeNumber:
+		if _, err := parseNumberPayload(p.Value); err != nil {
+			return fmt.Errorf("payload of type number is not a number: %q", p.Value)
+		}
+	default:
+		return fmt.Errorf("unknown payload type %q", p.Type)
+	}
+	return nil
+}
+
+// readCSVPayload reads the values of a csv payload. Records may have any
+// number of fields, which are flattened into one list with surrounding spaces
+// trimmed.
+func readCSVPayload(value string) ([]string, error) {
+	reader := csv.NewReader(strings.NewReader(value))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	for _, record := range records {
+		for _, field := range record {
+			values = append(values, strings.TrimSpace(field))
+		}
+	}
+	return values, nil
+}
+
+// parseNumberPayload parses the value of a number payload, ignoring
+// surrounding spaces.
+func parseNumberPayload(value string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSpace(value), 64)
+}
+
+// NormalizeVariants validates variants and returns a copy with default weight
+// types and stickiness filled in and the weight left by fixed variants spread
+// over the variable ones, so that the weights add up to exactly 1000.
+func NormalizeVariants(variants []Variant, customStickiness ...string) ([]Variant, error) {
+	if err := ValidateVariants(variants, customStickiness...); err != nil {
+		return nil, err
+	}
+	normalized := DistributeVariantWeights(variants)
+	if normalized == nil {
+		// clearing the variants must send an empty list rather than null
+		normalized = []Variant{}
+	}
+	for i := range normalized {
+		if normalized[i].WeightType == "" {
+			normalized[i].WeightType = WeightTypeVariable
+		}
+		if normalized[i].Stickiness == "" {
+			normalized[i].Stickiness = StickinessDefault
+		}
+	}
+	return normalized, nil
+}
+
+// DistributeVariantWeights returns a copy of variants where the weight fixed
+// variants leave of 1000 is spread evenly over the variable ones, the first
+// variable variants taking the remainder, exactly like Unleash. It does not
+// validate the variants.
+func DistributeVariantWeights(variants []Variant) []Variant {
+	if variants == nil {
+		return nil
+	}
+	distributed := append([]Variant(nil), variants...)
+	fixedWeight := 0
+	variableCount := 0
+	for _, variant := range distributed {
+		if variant.WeightType == WeightTypeFix {
+			fixedWeight += variant.Weight
+		} else {
+			variableCount++
+		}
+	}
+	if variableCount == 0 || fixedWeight > TotalVariantWeight {
+		return distributed
+	}
+
+	share := (TotalVariantWeight - fixedWeight) / variableCount
+	remainder := (TotalVariantWeight - fixedWeight) % variableCount
+	for i := range distributed {
+		if distributed[i].WeightType == WeightTypeFix {
+			continue
+		}
+		distributed[i].Weight = share
+		if remainder > 0 {
+			distributed[i].Weight++
+			remainder--
+		}
+	}
+	return distributed
+}
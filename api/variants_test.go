@@ -0,0 +1,163 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestValidateVariants(t *testing.T) {
+	tests := []struct {
+		name       string
+		variants   []Variant
+		wantErrors int
+	}{
+		{"Empty", nil, 0},
+		{"Valid", []Variant{
+			{Name: "blue", WeightType: WeightTypeFix, Weight: 200, Stickiness: StickinessUserId, Payload: &VariantPayload{Type: PayloadTypeJSON, Value: `{"color":"blue"}`}},
+			{Name: "green", Stickiness: StickinessUserId},
+		}, 0},
+		{"DuplicateName", []Variant{{Name: "blue"}, {Name: "blue"}}, 1},
+		{"MissingName", []Variant{{Name: ""}}, 1},
+		{"FixedWeightTooHigh", []Variant{
+			{Name: "blue", WeightType: WeightTypeFix, Weight: 700},
+			{Name: "green", WeightType: WeightTypeFix, Weight: 400},
+			{Name: "red"},
+		}, 1},
+		{"OnlyFixedBelowTotal", []Variant{{Name: "blue", WeightType: WeightTypeFix, Weight: 500}}, 1},
+		{"UnknownWeightType", []Variant{{Name: "blue", WeightType: "percentage"}}, 1},
+		{"UnknownStickiness", []Variant{{Name: "blue", Stickiness: "tenant"}}, 1},
+		{"MixedStickiness", []Variant{{Name: "blue", Stickiness: StickinessUserId}, {Name: "green", Stickiness: StickinessSessionId}}, 1},
+		{"BadPayloads", []Variant{
+			{Name: "json", Payload: &VariantPayload{Type: PayloadTypeJSON, Value: `{"broken"`}},
+			{Name: "number", Payload: &VariantPayload{Type: PayloadTypeNumber, Value: "ten"}},
+			{Name: "xml", Payload: &VariantPayload{Type: "xml", Value: "<a/>"}},
+			{Name: "empty", Payload: &VariantPayload{Type: PayloadTypeString}},
+		}, 4},
+		{"EmptyOverride", []Variant{{Name: "blue", Overrides: []VariantOverride{{ContextName: "userId"}}}}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateVariants(tt.variants)
+			if tt.wantErrors == 0 {
+				if err != nil {
+					t.Errorf("ValidateVariants() error = %v", err)
+				}
+				return
+			}
+			var verr *VariantsError
+			if !errors.As(err, &verr) || !errors.Is(err, ErrInvalidVariants) {
+				t.Fatalf("ValidateVariants() error = %v, want a *VariantsError", err)
+			}
+			if len(verr.Errors) != tt.wantErrors {
+				t.Errorf("ValidateVariants() reported %d problems, want %d: %v", len(verr.Errors), tt.wantErrors, err)
+			}
+		})
+	}
+}
+
+func TestValidateVariants_CustomStickiness(t *testing.T) {
+	variants := []Variant{{Name: "blue", Stickiness: "tenant"}}
+	if err := ValidateVariants(variants, "tenant"); err != nil {
+		t.Errorf("ValidateVariants() with a declared custom stickiness error = %v", err)
+	}
+}
+
+func TestNormalizeVariants(t *testing.T) {
+	variants := []Variant{
+		{Name: "fixed", WeightType: WeightTypeFix, Weight: 100},
+		{Name: "a"},
+		{Name: "b"},
+		{Name: "c"},
+		{Name: "d"},
+	}
+	normalized, err := NormalizeVariants(variants)
+	if err != nil {
+		t.Fatalf("NormalizeVariants() error = %v", err)
+	}
+	want := []int{100, 225, 225, 225, 225}
+	total := 0
+	for i, variant := range normalized {
+		if variant.Weight != want[i] {
+			t.Errorf("variant %s weight = %d, want %d", variant.Name, variant.Weight, want[i])
+		}
+		if variant.Stickiness != StickinessDefault {
+			t.Errorf("variant %s stickiness = %q, want default", variant.Name, variant.Stickiness)
+		}
+		total += variant.Weight
+	}
+	if total != TotalVariantWeight {
+		t.Errorf("weights add up to %d, want %d", total, TotalVariantWeight)
+	}
+	if normalized[1].WeightType != WeightTypeVariable {
+		t.Errorf("weight type = %q, want variable", normalized[1].WeightType)
+	}
+	if variants[1].Weight != 0 {
+		t.Error("NormalizeVariants() modified its input")
+	}
+}
+
+func TestDistributeVariantWeights_Remainder(t *testing.T) {
+	got := DistributeVariantWeights([]Variant{{Name: "a"}, {Name: "b"}, {Name: "c"}})
+	want := []int{334, 333, 333}
+	for i, variant := range got {
+		if variant.Weight != want[i] {
+			t.Errorf("variant %s weight = %d, want %d", variant.Name, variant.Weight, want[i])
+		}
+	}
+}
+
+func TestVariantsService_AddVariantsForFeatureToggle_Normalizes(t *testing.T) {
+	mock, client := newTestClient(t, WithCustomStickiness("tenant"))
+	route := mock.On(http.MethodPut, "admin/projects/default/features/checkout/variants").
+		Reply(http.StatusOK, `{"version":1,"variants":[]}`)
+
+	invalid := []Variant{{Name: "blue"}, {Name: "blue"}}
+	if _, _, err := client.Variants.AddVariantsForFeatureToggle("default", "checkout", invalid); !errors.Is(err, ErrInvalidVariants) {
+		t.Errorf("AddVariantsForFeatureToggle() error = %v, want ErrInvalidVariants", err)
+	}
+	if route.Calls() != 0 {
+		t.Fatal("invalid variants were sent to the server")
+	}
+
+	valid := []Variant{{Name: "blue", Stickiness: "tenant"}, {Name: "green", Stickiness: "tenant"}}
+	if _, _, err := client.Variants.AddVariantsForFeatureToggle("default", "checkout", valid); err != nil {
+		t.Fatalf("AddVariantsForFeatureToggle() error = %v", err)
+	}
+	var sent []Variant
+	if err := mock.Requests()[0].DecodeBody(&sent); err != nil {
+		t.Fatalf("DecodeBody() error = %v", err)
+	}
+	if sent[0].Weight != 500 || sent[1].Weight != 500 || sent[0].WeightType != WeightTypeVariable {
+		t.Errorf("sent variants = %+v, want normalized weights", sent)
+	}
+}
+
+func TestVariantsService_SetVariantsForEnvironments(t *testing.T) {
+	mock, client := newTestClient(t)
+	mock.On(http.MethodPut, "admin/projects/default/features/checkout/variants-batch").
+		Reply(http.StatusOK, `{"version":1,"variants":[{"name":"blue","weight":1000,"weightType":"variable","stickiness":"default"}]}`)
+
+	if _, _, err := client.Variants.SetVariantsForEnvironments("default", "checkout", nil, []Variant{{Name: "blue"}}); err == nil {
+		t.Error("SetVariantsForEnvironments() without environments succeeded")
+	}
+
+	got, _, err := client.Variants.SetVariantsForEnvironments("default", "checkout", []string{"development", "production"}, []Variant{{Name: "blue"}})
+	if err != nil {
+		t.Fatalf("SetVariantsForEnvironments() error = %v", err)
+	}
+	if len(got.Variants) != 1 || got.Variants[0].Weight != 1000 {
+		t.Errorf("SetVariantsForEnvironments() got = %+v", got.Variants)
+	}
+
+	var sent struct {
+		Variants     []Variant `json:"variants"`
+		Environments []string  `json:"environments"`
+	}
+	if err := mock.Requests()[0].DecodeBody(&sent); err != nil {
+		t.Fatalf("DecodeBody() error = %v", err)
+	}
+	if len(sent.Environments) != 2 || len(sent.Variants) != 1 || sent.Variants[0].Weight != 1000 {
+		t.Errorf("sent body = %+v", sent)
+	}
+}
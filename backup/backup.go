@@ -0,0 +1,251 @@
+// Package backup snapshots an Unleash instance into a single portable archive
+// and restores archives into empty or existing instances.
+//
+// An archive holds the feature types, tag types, context fields, custom
+// strategies, segments, projects and features, archived ones included, with
+// their strategies, variants and tags. Ids assigned by Unleash are not
+// portable: segments are matched by name on restore and the strategies
+// referring to them are updated accordingly.
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/sighphyre/go-unleash-api/api"
+)
+
+// ArchiveVersion is the version of the archive format written by this
+// package.
+const ArchiveVersion = 1
+
+// Archive is a snapshot of an Unleash instance.
+type Archive struct {
+	Version   int    `json:"version"`
+	CreatedAt string `json:"createdAt"`
+
+	FeatureTypes  []api.FeatureType  `json:"featureTypes"`
+	TagTypes      []api.TagType      `json:"tagTypes"`
+	ContextFields []api.ContextField `json:"contextFields"`
+	// Strategies are the activation strategies, the built-in ones included.
+	Strategies []api.Strategy `json:"strategies"`
+	Segments   []api.Segment  `json:"segments"`
+	Projects   []Project      `json:"projects"`
+	// Features are the live and archived features of every project, with
+	// their tags. Their Project field names the project they belong to.
+	Features []api.FeatureToggle `json:"features"`
+}
+
+// Project is a project and the environments enabled in it.
+type Project struct {
+	api.Project
+	Environments []string `json:"environments"`
+}
+
+// Backup reads the whole instance behind client into an archive.
+func Backup(ctx context.Context, client *api.ApiClient) (*Archive, error) {
+	archive := &Archive{
+		Version:   ArchiveVersion,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	featureTypes, _, err := client.FeatureTypes.GetAllFeatureTypesWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read feature types: %w", err)
+	}
+	archive.FeatureTypes = featureTypes.Types
+
+	tagTypes, _, err := client.TagTypes.GetAllTagTypesWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read tag types: %w", err)
+	}
+	archive.TagTypes = *tagTypes
+
+	contextFields, _, err := client.ContextFields.GetAllContextFieldsWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read context fields: %w", err)
+	}
+	archive.ContextFields = *contextFields
+
+	strategies, _, err := client.Strategies.GetAllStrategiesWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read strategies: %w", err)
+	}
+	archive.Strategies = strategies.Strategies
+
+	segments, _, err := client.Segments.GetAllSegmentsWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read segments: %w", err)
+	}
+	archive.Segments = *segments
+
+	projects, _, err := client.Projects.GetAllProjectsWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read projects: %w", err)
+	}
+	for _, summary := range *projects {
+		if err := archive.addProject(ctx, client, summary); err != nil {
+			return nil, err
+		}
+	}
+
+	archived, _, err := client.FeatureToggles.GetArchivedFeaturesWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read archived features: %w", err)
+	}
+	sort.Slice(*archived, func(i, j int) bool { return (*archived)[i].Name < (*archived)[j].Name })
+	for _, listed := range *archived {
+		feature, err := readArchivedFeature(ctx, client, listed, archive.environmentsOf(listed.Project))
+		if err != nil {
+			return nil, err
+		}
+		if err := archive.addFeature(ctx, client, feature); err != nil {
+			return nil, err
+		}
+	}
+	return archive, nil
+}
+
+// environmentsOf returns the environments of an archived project, or none
+// when the project is gone.
+func (a *Archive) environmentsOf(projectId string) []string {
+	for _, project := range a.Projects {
+		if project.Id == projectId {
+			return project.Environments
+		}
+	}
+	return nil
+}
+
+// readArchivedFeature completes an archived feature as listed by the archive,
+// which leaves out strategies and variants, by reading them per environment.
+// The environments listed with the feature are read, or else the given ones;
+// those the feature does not have are left out.
+func readArchivedFeature(ctx context.Context, client *api.ApiClient, listed api.FeatureToggle, environments []string) (api.FeatureToggle, error) {
+	feature := listed
+	feature.Archived = true
+	feature.Environments = nil
+	known := make(map[string]api.Environment)
+	for _, env := range listed.Environments {
+		known[env.Name] = env
+	}
+	if len(listed.Environments) > 0 {
+		environments = make([]string, len(listed.Environments))
+		for i, env := range listed.Environments {
+			environments[i] = env.Name
+		}
+	}
+	for _, name := range environments {
+		strategies, _, err := client.FeatureToggles.GetFeatureStrategiesWithContext(ctx, listed.Project, listed.Name, name)
+		if errors.Is(err, api.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return feature, fmt.Errorf("read the strategies of archived feature %s in %s: %w", listed.Name, name, err)
+		}
+		variants, _, err := client.Variants.GetEnvironmentVariantsWithContext(ctx, listed.Project, listed.Name, name)
+		if err != nil {
+			return feature, fmt.Errorf("read the variants of archived feature %s in %s: %w", listed.Name, name, err)
+		}
+		env := known[name]
+		env.Name = name
+		env.Strategies = *strategies
+		env.Variants = variants.Variants
+		feature.Environments = append(feature.Environments, env)
+	}
+	return feature, nil
+}
+
+func (a *Archive) addProject(ctx context.Context, client *api.ApiClient, summary api.ProjectSummary) error {
+	details, _, err := client.Projects.GetProjectByIdWithContext(ctx, summary.Id)
+	if err != nil {
+		return fmt.Errorf("read project %s: %w", summary.Id, err)
+	}
+	project := Project{
+		Project:      api.Project{Id: summary.Id, Name: summary.Name, Description: summary.Description},
+		Environments: []string{},
+	}
+	for _, env := range details.Environments {
+		project.Environments = append(project.Environments, env.Environment)
+	}
+	a.Projects = append(a.Projects, project)
+
+	features, _, err := client.FeatureToggles.GetFeaturesByProjectWithContext(ctx, summary.Id)
+	if err != nil {
+		return fmt.Errorf("read the features of project %s: %w", summary.Id, err)
+	}
+	names := make([]string, 0, len(*features))
+	for _, feature := range *features {
+		names = append(names, feature.Name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// listings may leave out strategies and variants
+		feature, _, err := client.FeatureToggles.GetFeatureByNameWithContext(ctx, summary.Id, name)
+		if err != nil {
+			return fmt.Errorf("read feature %s: %w", name, err)
+		}
+		feature.Project = summary.Id
+		if err := a.addFeature(ctx, client, *feature); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *Archive) addFeature(ctx context.Context, client *api.ApiClient, feature api.FeatureToggle) error {
+	tags, _, err := client.FeatureTags.GetAllFeatureTagsWithContext(ctx, feature.Name)
+	if err != nil {
+		return fmt.Errorf("read the tags of %s: %w", feature.Name, err)
+	}
+	feature.Tags = tags.Tags
+	a.Features = append(a.Features, feature)
+	return nil
+}
+
+// Write encodes the archive as indented JSON.
+func Write(w io.Writer, archive *Archive) error {
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// WriteFile writes the archive to path.
+func WriteFile(path string, archive *Archive) error {
+	var buf bytes.Buffer
+	if err := Write(&buf, archive); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// Read decodes an archive, rejecting versions this package does not know.
+func Read(r io.Reader) (*Archive, error) {
+	var archive Archive
+	if err := json.NewDecoder(r).Decode(&archive); err != nil {
+		return nil, fmt.Errorf("read archive: %w", err)
+	}
+	if archive.Version < 1 || archive.Version > ArchiveVersion {
+		return nil, fmt.Errorf("unsupported archive version %d, this library reads version %d", archive.Version, ArchiveVersion)
+	}
+	return &archive, nil
+}
+
+// ReadFile reads the archive at path.
+func ReadFile(path string) (*Archive, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Read(bytes.NewReader(data))
+}
@@ -0,0 +1,399 @@
+package backup_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sighphyre/go-unleash-api/api"
+	"github.com/sighphyre/go-unleash-api/backup"
+	"github.com/sighphyre/go-unleash-api/reconcile"
+	"github.com/sighphyre/go-unleash-api/unleashtest"
+)
+
+func newTestClient(t *testing.T) (*unleashtest.Server, *api.ApiClient) {
+	t.Helper()
+	srv := unleashtest.NewServer()
+	t.Cleanup(srv.Close)
+	client, err := srv.NewClient()
+	if err != nil {
+		t.Fatalf("Server.NewClient() error = %v", err)
+	}
+	return srv, client
+}
+
+// seed fills an instance with one of everything an archive holds.
+func seed(t *testing.T, client *api.ApiClient) {
+	t.Helper()
+	ctx := context.Background()
+	if _, _, err := client.TagTypes.CreateTagType(api.TagType{Name: "team", Description: "Owning team"}); err != nil {
+		t.Fatalf("CreateTagType() error = %v", err)
+	}
+	region := api.ContextField{Name: "region", Stickiness: true, SortOrder: 5, LegalValues: []api.LegalValue{{Value: "eu"}, {Value: "us"}}}
+	if _, _, err := client.ContextFields.CreateContextField(region); err != nil {
+		t.Fatalf("CreateContextField() error = %v", err)
+	}
+	if _, _, err := client.Strategies.CreateStrategy(api.Strategy{Name: "byTenant", Description: "Tenants", Parameters: []api.StrategyParameter{{Name: "tenants", Type: "list"}}}); err != nil {
+		t.Fatalf("CreateStrategy() error = %v", err)
+	}
+	if _, _, err := client.FeatureTypes.UpdateFeatureTypeLifetime("release", 60); err != nil {
+		t.Fatalf("UpdateFeatureTypeLifetime() error = %v", err)
+	}
+	beta, _, err := client.Segments.CreateSegment(api.Segment{Name: "beta", Constraints: []api.Constraint{{ContextName: "region", Operator: api.OperatorIn, Values: []string{"eu"}}}})
+	if err != nil {
+		t.Fatalf("CreateSegment() error = %v", err)
+	}
+
+	rollout := api.NewFeatureStrategy(api.FlexibleRolloutParams{Rollout: 25, Stickiness: "default", GroupId: "checkout"})
+	rollout.Segments = []int{beta.ID}
+	desired := reconcile.DesiredState{Projects: []reconcile.ProjectState{
+		{
+			Project:      api.Project{Id: "payments", Name: "Payments"},
+			Environments: []string{"production"},
+			Features: []api.FeatureToggle{{
+				Name: "checkout",
+				Type: "experiment",
+				Environments: []api.Environment{{
+					Name:       "production",
+					Enabled:    true,
+					Strategies: []api.FeatureStrategy{rollout, {Name: "byTenant", Parameters: map[string]string{"tenants": "acme"}}},
+				}},
+				Variants: []api.Variant{{Name: "control"}, {Name: "treatment"}},
+				Tags:     []api.FeatureTag{{Type: "team", Value: "payments"}},
+			}},
+		},
+		{
+			Project: api.Project{Id: "default", Name: "Default", Description: "Default project"},
+			Features: []api.FeatureToggle{{
+				Name:        "legacy",
+				Description: "Old search",
+				Environments: []api.Environment{{
+					Name:       "production",
+					Strategies: []api.FeatureStrategy{{Name: "userWithId", Parameters: map[string]string{"userIds": "1,2"}}},
+					Variants:   []api.Variant{{Name: "old"}},
+				}},
+			}},
+		},
+	}}
+	p, err := reconcile.NewPlan(ctx, client, desired, reconcile.Options{})
+	if err != nil {
+		t.Fatalf("NewPlan() error = %v", err)
+	}
+	if _, err := p.Apply(ctx); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if _, _, err := client.FeatureToggles.ArchiveFeature("default", "legacy"); err != nil {
+		t.Fatalf("ArchiveFeature() error = %v", err)
+	}
+}
+
+func takeBackup(t *testing.T, client *api.ApiClient) *backup.Archive {
+	t.Helper()
+	archive, err := backup.Backup(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	return archive
+}
+
+func restore(t *testing.T, client *api.ApiClient, archive *backup.Archive, mode backup.ConflictMode) *backup.RestoreResult {
+	t.Helper()
+	result, err := backup.Restore(context.Background(), client, archive, backup.RestoreOptions{OnConflict: mode})
+	if err != nil {
+		t.Fatalf("Restore(%s) error = %v", mode, err)
+	}
+	return result
+}
+
+func strategyOf(t *testing.T, srv *unleashtest.Server, feature string) api.FeatureStrategy {
+	t.Helper()
+	toggle, ok := srv.Feature(feature)
+	if !ok {
+		t.Fatalf("feature %s does not exist", feature)
+	}
+	for _, env := range toggle.Environments {
+		if env.Name == "production" && len(env.Strategies) > 0 {
+			sort.SliceStable(env.Strategies, func(i, j int) bool { return env.Strategies[i].SortOrder < env.Strategies[j].SortOrder })
+			return env.Strategies[0]
+		}
+	}
+	t.Fatalf("feature %s has no strategies in production", feature)
+	return api.FeatureStrategy{}
+}
+
+func hasProductionStrategyAndVariant(feature api.FeatureToggle) bool {
+	for _, env := range feature.Environments {
+		if env.Name == "production" {
+			return len(env.Strategies) == 1 && len(env.Variants) == 1
+		}
+	}
+	return false
+}
+
+func TestBackup(t *testing.T) {
+	_, client := newTestClient(t)
+	seed(t, client)
+
+	archive := takeBackup(t, client)
+	if archive.Version != backup.ArchiveVersion {
+		t.Errorf("Version = %d, want %d", archive.Version, backup.ArchiveVersion)
+	}
+	if len(archive.TagTypes) != 2 || len(archive.Segments) != 1 || len(archive.FeatureTypes) != 5 {
+		t.Errorf("archive has %d tag types, %d segments and %d feature types", len(archive.TagTypes), len(archive.Segments), len(archive.FeatureTypes))
+	}
+	var names []string
+	for _, feature := range archive.Features {
+		names = append(names, feature.Project+"/"+feature.Name)
+	}
+	if want := []string{"payments/checkout", "default/legacy"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("features = %v, want %v", names, want)
+	}
+	if checkout := archive.Features[0]; len(checkout.Tags) != 1 || checkout.Archived {
+		t.Errorf("checkout = %+v, want one tag and live", checkout)
+	}
+	if legacy := archive.Features[1]; !legacy.Archived || !hasProductionStrategyAndVariant(legacy) {
+		t.Errorf("legacy = %+v, want it archived with its strategy and variant", legacy)
+	}
+	for _, project := range archive.Projects {
+		if project.Id == "payments" && !reflect.DeepEqual(project.Environments, []string{"development", "production"}) {
+			t.Errorf("payments environments = %v, want [development production]", project.Environments)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "unleash.json")
+	if err := backup.WriteFile(path, archive); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	read, err := backup.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	want, _ := json.Marshal(archive)
+	got, _ := json.Marshal(read)
+	if !bytes.Equal(got, want) {
+		t.Errorf("archive changed in a write and read:\n%s\n%s", got, want)
+	}
+
+	if _, err := backup.Read(bytes.NewReader([]byte(`{"version": 2}`))); err == nil {
+		t.Error("Read() of version 2 error = nil")
+	}
+}
+
+func TestRestore_IntoEmptyInstance(t *testing.T) {
+	_, source := newTestClient(t)
+	seed(t, source)
+	archive := takeBackup(t, source)
+
+	srv, client := newTestClient(t)
+	// shift the segment ids of the target
+	if _, _, err := client.Segments.CreateSegment(api.Segment{Name: "internal"}); err != nil {
+		t.Fatalf("CreateSegment() error = %v", err)
+	}
+
+	result := restore(t, client, archive, backup.ConflictFail)
+	if len(result.Conflicts) != 0 {
+		t.Errorf("Conflicts = %v", result.Conflicts)
+	}
+	want := []string{"tag type team", "context field region", "strategy byTenant", "segment beta", "project payments", "feature legacy", "feature checkout"}
+	if !reflect.DeepEqual(result.Created, want) {
+		t.Errorf("Created = %v, want %v", result.Created, want)
+	}
+	if want := []string{"feature type release"}; !reflect.DeepEqual(result.Updated, want) {
+		t.Errorf("Updated = %v, want %v", result.Updated, want)
+	}
+
+	restored := takeBackup(t, client)
+	if len(restored.Features) != 2 || !restored.Features[1].Archived || !hasProductionStrategyAndVariant(restored.Features[1]) {
+		t.Fatalf("restored features = %+v", restored.Features)
+	}
+	segments, _, _ := client.Segments.GetAllSegments()
+	var betaID int
+	for _, segment := range *segments {
+		if segment.Name == "beta" {
+			betaID = segment.ID
+		}
+	}
+	if got := strategyOf(t, srv, "checkout").Segments; !reflect.DeepEqual(got, []int{betaID}) {
+		t.Errorf("checkout segments = %v, want [%d]", got, betaID)
+	}
+
+	again := restore(t, client, archive, backup.ConflictFail)
+	if len(again.Created)+len(again.Updated)+len(again.Skipped)+len(again.Conflicts) != 0 {
+		t.Errorf("second restore = %+v, want no changes", again)
+	}
+}
+
+func TestRestore_ConflictModes(t *testing.T) {
+	srv, client := newTestClient(t)
+	seed(t, client)
+	archive := takeBackup(t, client)
+
+	// drift away from the archive
+	region := api.ContextField{Name: "region", Stickiness: true, SortOrder: 5, LegalValues: []api.LegalValue{{Value: "eu"}}}
+	if _, _, err := client.ContextFields.UpdateContextField(region); err != nil {
+		t.Fatalf("UpdateContextField() error = %v", err)
+	}
+	strategy := strategyOf(t, srv, "checkout")
+	strategy.Parameters = api.FlexibleRolloutParams{Rollout: 100, Stickiness: "default", GroupId: "checkout"}
+	if _, _, err := client.FeatureToggles.UpdateFeatureStrategy("payments", "checkout", "production", strategy); err != nil {
+		t.Fatalf("UpdateFeatureStrategy() error = %v", err)
+	}
+	if _, _, err := client.FeatureToggles.ReviveFeature("legacy"); err != nil {
+		t.Fatalf("ReviveFeature() error = %v", err)
+	}
+	rollout := func() int {
+		var params api.FlexibleRolloutParams
+		if err := strategyOf(t, srv, "checkout").DecodeParameters(&params); err != nil {
+			t.Fatalf("DecodeParameters() error = %v", err)
+		}
+		return params.Rollout
+	}
+	wantConflicts := []string{"context field region", "feature legacy", "feature checkout"}
+	conflicts := func(result *backup.RestoreResult) []string {
+		var names []string
+		for _, conflict := range result.Conflicts {
+			names = append(names, conflict.Resource+" "+conflict.Name)
+		}
+		return names
+	}
+
+	result, err := backup.Restore(context.Background(), client, archive, backup.RestoreOptions{})
+	if !errors.Is(err, backup.ErrConflict) {
+		t.Fatalf("Restore() error = %v, want ErrConflict", err)
+	}
+	if got := conflicts(result); !reflect.DeepEqual(got, wantConflicts) {
+		t.Errorf("Conflicts = %v, want %v", got, wantConflicts)
+	}
+	if got := rollout(); got != 100 {
+		t.Errorf("checkout rollout after a failed restore = %d, want 100", got)
+	}
+
+	result = restore(t, client, archive, backup.ConflictSkip)
+	if !reflect.DeepEqual(result.Skipped, wantConflicts) || len(result.Created)+len(result.Updated) != 0 {
+		t.Errorf("skip result = %+v", result)
+	}
+	if _, ok := srv.Feature("legacy"); !ok || rollout() != 100 {
+		t.Error("skip changed the conflicting resources")
+	}
+
+	result = restore(t, client, archive, backup.ConflictOverwrite)
+	if got := conflicts(result); !reflect.DeepEqual(got, wantConflicts) {
+		t.Errorf("Conflicts = %v, want %v", got, wantConflicts)
+	}
+	if want := []string{"context field region", "feature checkout"}; !reflect.DeepEqual(result.Updated, want) {
+		t.Errorf("Updated = %v, want %v", result.Updated, want)
+	}
+	if want := []string{"feature legacy"}; !reflect.DeepEqual(result.Created, want) {
+		t.Errorf("Created = %v, want %v", result.Created, want)
+	}
+	if _, ok := srv.Feature("legacy"); ok {
+		t.Error("legacy is live after overwrite, want archived")
+	}
+	if got := rollout(); got != 25 {
+		t.Errorf("checkout rollout after overwrite = %d, want 25", got)
+	}
+
+	if result := restore(t, client, archive, backup.ConflictFail); len(result.Conflicts) != 0 {
+		t.Errorf("Conflicts after overwrite = %v", conflicts(result))
+	}
+}
+
+func TestRestore_SkipKeepsDriftedProjects(t *testing.T) {
+	_, client := newTestClient(t)
+	seed(t, client)
+	archive := takeBackup(t, client)
+	if _, _, err := client.Projects.UpdateProject("payments", api.Project{Id: "payments", Name: "Paid", Description: "changed"}); err != nil {
+		t.Fatalf("UpdateProject() error = %v", err)
+	}
+
+	result := restore(t, client, archive, backup.ConflictSkip)
+	if want := []string{"project payments"}; !reflect.DeepEqual(result.Skipped, want) {
+		t.Errorf("Skipped = %v, want %v", result.Skipped, want)
+	}
+	if len(result.Updated) != 0 {
+		t.Errorf("Updated = %v, want none", result.Updated)
+	}
+	payments, _, err := client.Projects.GetProjectById("payments")
+	if err != nil {
+		t.Fatalf("GetProjectById() error = %v", err)
+	}
+	if payments.Name != "Paid" || payments.Description != "changed" {
+		t.Errorf("payments after skip = %q, %q, want Paid, changed", payments.Name, payments.Description)
+	}
+}
+
+// failingClient answers the requests to one path with a validation error.
+type failingClient struct {
+	next   api.HTTPClient
+	method string
+	path   string
+}
+
+func (c failingClient) Do(req *http.Request) (*http.Response, error) {
+	// the api package keeps the path in URL.Opaque
+	if req.Method != c.method || !strings.HasSuffix(req.URL.Opaque, c.path) {
+		return c.next.Do(req)
+	}
+	return &http.Response{
+		StatusCode: http.StatusBadRequest,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(`{"name":"ValidationError","message":"rejected"}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestRestore_PutsBackReplacedFeaturesOnFailure(t *testing.T) {
+	srv, client := newTestClient(t)
+	seed(t, client)
+	archive := takeBackup(t, client)
+	if _, _, err := client.FeatureToggles.ChangeFeatureProject("payments", "checkout", "default"); err != nil {
+		t.Fatalf("ChangeFeatureProject() error = %v", err)
+	}
+
+	failing, err := srv.NewClient(api.WithHTTPClient(failingClient{
+		next:   srv.Client(),
+		method: http.MethodPost,
+		path:   "/projects/payments/features/checkout/environments/production/strategies",
+	}))
+	if err != nil {
+		t.Fatalf("Server.NewClient() error = %v", err)
+	}
+	_, err = backup.Restore(context.Background(), failing, archive, backup.RestoreOptions{OnConflict: backup.ConflictOverwrite})
+	if !errors.Is(err, api.ErrValidation) {
+		t.Fatalf("Restore() error = %v, want %v", err, api.ErrValidation)
+	}
+
+	checkout, ok := srv.Feature("checkout")
+	if !ok {
+		t.Fatal("checkout was not put back")
+	}
+	if project, _, err := client.Projects.GetProjectById("default"); err != nil || project.Name != "Default" {
+		t.Errorf("default after put back = %+v, %v, want it named Default", project, err)
+	}
+	if checkout.Project != "default" {
+		t.Errorf("checkout project = %q, want default", checkout.Project)
+	}
+	if len(checkout.Variants) != 2 {
+		t.Errorf("checkout variants = %v, want two", checkout.Variants)
+	}
+	tags, _, err := client.FeatureTags.GetAllFeatureTags("checkout")
+	if err != nil {
+		t.Fatalf("GetAllFeatureTags() error = %v", err)
+	}
+	if len(tags.Tags) != 1 {
+		t.Errorf("checkout tags = %v, want one", tags.Tags)
+	}
+	for _, env := range checkout.Environments {
+		if env.Name == "production" && (!env.Enabled || len(env.Strategies) != 2) {
+			t.Errorf("checkout in production = %+v, want enabled with two strategies", env)
+		}
+	}
+}
@@ -0,0 +1,742 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sighphyre/go-unleash-api/api"
+	"github.com/sighphyre/go-unleash-api/reconcile"
+)
+
+// ErrConflict is matched by the error of Restore when the instance conflicts
+// with the archive and the ConflictFail mode is used.
+var ErrConflict = errors.New("the instance conflicts with the archive")
+
+// ConflictMode decides what Restore does with resources that already exist
+// in the instance and differ from the archive.
+type ConflictMode string
+
+const (
+	// ConflictFail restores nothing when there is any conflict.
+	ConflictFail ConflictMode = "fail"
+	// ConflictSkip keeps the existing resources.
+	ConflictSkip ConflictMode = "skip"
+	// ConflictOverwrite replaces the existing resources with the archived
+	// ones. Features in the way are removed last, and put back when their
+	// replacements cannot be restored.
+	ConflictOverwrite ConflictMode = "overwrite"
+)
+
+// Resources named by Conflict and RestoreResult.
+const (
+	ResourceFeatureType  = "feature type"
+	ResourceTagType      = "tag type"
+	ResourceContextField = "context field"
+	ResourceStrategy     = "strategy"
+	ResourceSegment      = "segment"
+	ResourceProject      = "project"
+	ResourceFeature      = "feature"
+)
+
+// RestoreOptions configures Restore.
+type RestoreOptions struct {
+	// OnConflict defaults to ConflictFail.
+	OnConflict ConflictMode
+}
+
+// Conflict is an existing resource that differs from the archive.
+type Conflict struct {
+	Resource string
+	Name     string
+	Reason   string
+}
+
+func (c Conflict) String() string {
+	return c.Resource + " " + c.Name + ": " + c.Reason
+}
+
+// RestoreResult describes what Restore did. Resources that already match the
+// archive appear in none of the lists.
+type RestoreResult struct {
+	// Created, Updated and Skipped name resources like "segment beta".
+	Created []string
+	Updated []string
+	Skipped []string
+	// Conflicts lists the existing resources that differ from the archive,
+	// whatever the mode.
+	Conflicts []Conflict
+}
+
+// Restore replays an archive into the instance behind client. Resources are
+// restored in dependency order: feature types, tag types, context fields,
+// strategies and segments, then projects and features, which are reconciled
+// with the reconcile package. Built-in strategies and features missing from
+// the archive are left alone, and archived features already archived in the
+// instance are kept as they are.
+//
+// The instance is compared with the archive before anything is written, so
+// with ConflictFail a conflicting instance is left untouched; the error then
+// matches ErrConflict and the result lists the conflicts. Restoring the same
+// archive twice changes nothing the second time.
+func Restore(ctx context.Context, client *api.ApiClient, archive *Archive, opts RestoreOptions) (*RestoreResult, error) {
+	mode := opts.OnConflict
+	switch mode {
+	case "":
+		mode = ConflictFail
+	case ConflictFail, ConflictSkip, ConflictOverwrite:
+	default:
+		return nil, fmt.Errorf("unknown conflict mode %q", mode)
+	}
+	r := &restorer{
+		client:          client,
+		archive:         archive,
+		mode:            mode,
+		result:          &RestoreResult{},
+		segmentIDs:      make(map[int]int),
+		replaced:        make(map[string]string),
+		skippedProjects: make(map[string]api.Project),
+	}
+
+	for _, plan := range []func(context.Context) error{
+		r.planFeatureTypes,
+		r.planTagTypes,
+		r.planContextFields,
+		r.planStrategies,
+		r.planSegments,
+		r.planFeatures,
+	} {
+		if err := plan(ctx); err != nil {
+			return r.result, err
+		}
+	}
+	if mode == ConflictFail && len(r.result.Conflicts) > 0 {
+		conflicts := make([]string, len(r.result.Conflicts))
+		for i, conflict := range r.result.Conflicts {
+			conflicts[i] = conflict.String()
+		}
+		return r.result, fmt.Errorf("%w: %s", ErrConflict, strings.Join(conflicts, "; "))
+	}
+
+	for _, step := range r.steps {
+		if err := step.apply(ctx); err != nil {
+			return r.result, fmt.Errorf("restore %s %s: %w", step.resource, step.name, err)
+		}
+		r.record(step.kind, step.resource, step.name)
+	}
+	return r.result, r.restoreFeatures(ctx)
+}
+
+type restorer struct {
+	client  *api.ApiClient
+	archive *Archive
+	mode    ConflictMode
+	result  *RestoreResult
+	steps   []step
+
+	// segmentIDs maps archived segment ids to the ids in the instance.
+	segmentIDs map[int]int
+	// features are the archived features to restore, in archive order.
+	features []api.FeatureToggle
+	// replaced are the features to remove before they are recreated, by the
+	// project they live in, or "" when they are archived.
+	replaced map[string]string
+	// skippedProjects keep their current settings, by project id.
+	skippedProjects map[string]api.Project
+}
+
+type step struct {
+	kind     string
+	resource string
+	name     string
+	apply    func(ctx context.Context) error
+}
+
+func (r *restorer) record(kind string, resource string, name string) {
+	item := resource + " " + name
+	switch kind {
+	case reconcile.Create:
+		r.result.Created = append(r.result.Created, item)
+	case reconcile.Update:
+		r.result.Updated = append(r.result.Updated, item)
+	default:
+		r.result.Skipped = append(r.result.Skipped, item)
+	}
+}
+
+// resolve handles an existing resource that differs from the archive,
+// reporting whether it should be overwritten.
+func (r *restorer) resolve(resource string, name string, reason string) bool {
+	r.result.Conflicts = append(r.result.Conflicts, Conflict{Resource: resource, Name: name, Reason: reason})
+	switch r.mode {
+	case ConflictOverwrite:
+		return true
+	case ConflictSkip:
+		r.record("", resource, name)
+	}
+	return false
+}
+
+func (r *restorer) add(kind string, resource string, name string, apply func(ctx context.Context) error) {
+	r.steps = append(r.steps, step{kind: kind, resource: resource, name: name, apply: apply})
+}
+
+// planFeatureTypes restores lifetimes. Every instance has the feature types,
+// so a differing lifetime is a setting to restore rather than a conflict.
+// Feature types cannot be created, so types the instance lacks are skipped.
+func (r *restorer) planFeatureTypes(ctx context.Context) error {
+	live, _, err := r.client.FeatureTypes.GetAllFeatureTypesWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("read feature types: %w", err)
+	}
+	existing := make(map[string]api.FeatureType)
+	for _, featureType := range live.Types {
+		existing[featureType.ID] = featureType
+	}
+	for _, featureType := range r.archive.FeatureTypes {
+		featureType := featureType
+		current, ok := existing[featureType.ID]
+		switch {
+		case !ok:
+			r.record("", ResourceFeatureType, featureType.ID)
+		case current.LifetimeDays != featureType.LifetimeDays:
+			r.add(reconcile.Update, ResourceFeatureType, featureType.ID, func(ctx context.Context) error {
+				_, _, err := r.client.FeatureTypes.UpdateFeatureTypeLifetimeWithContext(ctx, featureType.ID, featureType.LifetimeDays)
+				return err
+			})
+		}
+	}
+	return nil
+}
+
+func (r *restorer) planTagTypes(ctx context.Context) error {
+	live, _, err := r.client.TagTypes.GetAllTagTypesWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("read tag types: %w", err)
+	}
+	existing := make(map[string]api.TagType)
+	for _, tagType := range *live {
+		existing[tagType.Name] = tagType
+	}
+	tagTypes := r.client.TagTypes
+	for _, tagType := range r.archive.TagTypes {
+		tagType := tagType
+		current, ok := existing[tagType.Name]
+		switch {
+		case !ok:
+			r.add(reconcile.Create, ResourceTagType, tagType.Name, func(ctx context.Context) error {
+				_, _, err := tagTypes.CreateTagTypeWithContext(ctx, tagType)
+				return err
+			})
+		case current != tagType:
+			if r.resolve(ResourceTagType, tagType.Name, "description or icon differ") {
+				r.add(reconcile.Update, ResourceTagType, tagType.Name, func(ctx context.Context) error {
+					_, _, err := tagTypes.UpdateTagTypeWithContext(ctx, tagType)
+					return err
+				})
+			}
+		}
+	}
+	return nil
+}
+
+func (r *restorer) planContextFields(ctx context.Context) error {
+	live, _, err := r.client.ContextFields.GetAllContextFieldsWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("read context fields: %w", err)
+	}
+	existing := make(map[string]api.ContextField)
+	for _, field := range *live {
+		existing[field.Name] = field
+	}
+	fields := r.client.ContextFields
+	for _, field := range r.archive.ContextFields {
+		field := field
+		field.CreatedAt = ""
+		current, ok := existing[field.Name]
+		current.CreatedAt = ""
+		switch {
+		case !ok:
+			r.add(reconcile.Create, ResourceContextField, field.Name, func(ctx context.Context) error {
+				_, _, err := fields.CreateContextFieldWithContext(ctx, field)
+				return err
+			})
+		case !sameJSON(current, field):
+			if r.resolve(ResourceContextField, field.Name, "description, stickiness, sort order or legal values differ") {
+				r.add(reconcile.Update, ResourceContextField, field.Name, func(ctx context.Context) error {
+					_, _, err := fields.UpdateContextFieldWithContext(ctx, field)
+					return err
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// planStrategies restores custom strategies; built-in ones cannot change.
+func (r *restorer) planStrategies(ctx context.Context) error {
+	live, _, err := r.client.Strategies.GetAllStrategiesWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("read strategies: %w", err)
+	}
+	existing := make(map[string]api.Strategy)
+	for _, strategy := range live.Strategies {
+		existing[strategy.Name] = strategy
+	}
+	strategies := r.client.Strategies
+	for _, strategy := range r.archive.Strategies {
+		strategy := strategy
+		if !strategy.Editable {
+			continue
+		}
+		setDeprecated := func(ctx context.Context) error {
+			if strategy.Deprecated {
+				_, _, err := r.client.FeatureToggles.DeprecateStrategyWithContext(ctx, strategy.Name)
+				return err
+			}
+			_, _, err := r.client.FeatureToggles.ReactivateStrategyWithContext(ctx, strategy.Name)
+			return err
+		}
+		current, ok := existing[strategy.Name]
+		switch {
+		case !ok:
+			r.add(reconcile.Create, ResourceStrategy, strategy.Name, func(ctx context.Context) error {
+				if _, _, err := strategies.CreateStrategyWithContext(ctx, strategy); err != nil {
+					return err
+				}
+				if strategy.Deprecated {
+					return setDeprecated(ctx)
+				}
+				return nil
+			})
+		case !sameJSON(current, strategy):
+			if r.resolve(ResourceStrategy, strategy.Name, "definition differs") {
+				r.add(reconcile.Update, ResourceStrategy, strategy.Name, func(ctx context.Context) error {
+					if _, _, err := strategies.UpdateStrategyWithContext(ctx, strategy); err != nil {
+						return err
+					}
+					if current.Deprecated != strategy.Deprecated {
+						return setDeprecated(ctx)
+					}
+					return nil
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// planSegments matches segments by name and records the ids strategies must
+// use in the instance.
+func (r *restorer) planSegments(ctx context.Context) error {
+	live, _, err := r.client.Segments.GetAllSegmentsWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("read segments: %w", err)
+	}
+	existing := make(map[string]api.Segment)
+	for _, segment := range *live {
+		existing[segment.Name] = segment
+	}
+	segments := r.client.Segments
+	for _, segment := range r.archive.Segments {
+		segment := segment
+		archivedID := segment.ID
+		current, ok := existing[segment.Name]
+		if !ok {
+			r.add(reconcile.Create, ResourceSegment, segment.Name, func(ctx context.Context) error {
+				created, _, err := segments.CreateSegmentWithContext(ctx, segment)
+				if err != nil {
+					return err
+				}
+				r.segmentIDs[archivedID] = created.ID
+				return nil
+			})
+			continue
+		}
+		r.segmentIDs[archivedID] = current.ID
+		if current.Description != segment.Description || current.Project != segment.Project || !sameJSON(current.Constraints, segment.Constraints) {
+			if r.resolve(ResourceSegment, segment.Name, "description, project or constraints differ") {
+				segment.ID = current.ID
+				r.add(reconcile.Update, ResourceSegment, segment.Name, func(ctx context.Context) error {
+					_, _, err := segments.UpdateSegmentWithContext(ctx, segment)
+					return err
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// planFeatures finds the projects and features that conflict with the
+// archive. Features whose name is taken elsewhere in the instance, by a live
+// feature of another project or an archived feature, conflict outright;
+// features in place conflict when reconciling them would change them.
+func (r *restorer) planFeatures(ctx context.Context) error {
+	liveProjects, _, err := r.client.Projects.GetAllProjectsWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("read projects: %w", err)
+	}
+	liveIn := make(map[string]string)
+	settings := make(map[string]api.Project)
+	for _, project := range *liveProjects {
+		settings[project.Id] = api.Project{Id: project.Id, Name: project.Name, Description: project.Description}
+		features, _, err := r.client.FeatureToggles.GetFeaturesByProjectWithContext(ctx, project.Id)
+		if err != nil {
+			return fmt.Errorf("read the features of project %s: %w", project.Id, err)
+		}
+		for _, feature := range *features {
+			liveIn[feature.Name] = project.Id
+		}
+	}
+	archivedIn := make(map[string]string)
+	archived, _, err := r.client.FeatureToggles.GetArchivedFeaturesWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("read archived features: %w", err)
+	}
+	for _, feature := range *archived {
+		archivedIn[feature.Name] = feature.Project
+	}
+
+	for _, feature := range r.archive.Features {
+		project, isLive := liveIn[feature.Name]
+		_, isArchived := archivedIn[feature.Name]
+		var reason string
+		switch {
+		case feature.Archived && isArchived:
+			continue
+		case isArchived:
+			reason = "is archived in the instance"
+		case feature.Archived && isLive:
+			reason = "is live in the instance"
+		case isLive && project != feature.Project:
+			reason = "lives in project " + project
+		}
+		if reason != "" {
+			if !r.resolve(ResourceFeature, feature.Name, reason) {
+				continue
+			}
+			r.replaced[feature.Name] = project
+			if !isLive {
+				r.replaced[feature.Name] = ""
+			}
+		}
+		r.features = append(r.features, feature)
+	}
+
+	// compare the features in place, and the projects, by planning them
+	desired := r.desiredState(r.features)
+	plan, err := reconcile.NewPlan(ctx, r.client, desired, reconcile.Options{})
+	if err != nil {
+		return err
+	}
+	created := createdByPlan(plan)
+	changedFeatures := make(map[string]bool)
+	changedProjects := make(map[string]bool)
+	for _, action := range plan.Actions {
+		if action.Feature == "" {
+			changedProjects[action.Project] = !created[ResourceProject+" "+action.Project]
+			continue
+		}
+		if _, replaced := r.replaced[action.Feature]; !replaced && !created[ResourceFeature+" "+action.Feature] {
+			changedFeatures[action.Feature] = true
+		}
+	}
+
+	for _, project := range desired.Projects {
+		if changedProjects[project.Id] && !r.resolve(ResourceProject, project.Id, "name, description or environments differ") {
+			r.skippedProjects[project.Id] = settings[project.Id]
+		}
+	}
+	kept := r.features[:0]
+	for _, feature := range r.features {
+		if changedFeatures[feature.Name] && !r.resolve(ResourceFeature, feature.Name, "strategies, variants, tags or settings differ") {
+			continue
+		}
+		kept = append(kept, feature)
+	}
+	r.features = kept
+	return nil
+}
+
+// createdByPlan returns the projects and features a plan creates, keyed like
+// "feature checkout".
+func createdByPlan(plan *reconcile.Plan) map[string]bool {
+	created := make(map[string]bool)
+	for _, action := range plan.Actions {
+		switch {
+		case action.Kind != reconcile.Create:
+		case action.Resource == reconcile.ResourceProject:
+			created[ResourceProject+" "+action.Project] = true
+		case action.Resource == reconcile.ResourceFeature:
+			created[ResourceFeature+" "+action.Feature] = true
+		}
+	}
+	return created
+}
+
+// desiredState groups features by project. Skipped projects keep their
+// current name, description and environments.
+func (r *restorer) desiredState(features []api.FeatureToggle) reconcile.DesiredState {
+	var state reconcile.DesiredState
+	index := make(map[string]int)
+	addProject := func(project Project) {
+		index[project.Id] = len(state.Projects)
+		projectState := reconcile.ProjectState{Project: project.Project, Environments: project.Environments}
+		if live, ok := r.skippedProjects[project.Id]; ok {
+			// reconcile would rename a project declared without a name
+			projectState = reconcile.ProjectState{Project: live}
+		}
+		state.Projects = append(state.Projects, projectState)
+	}
+	for _, project := range r.archive.Projects {
+		addProject(project)
+	}
+	for _, feature := range features {
+		if _, ok := index[feature.Project]; !ok {
+			// archived features may belong to deleted projects
+			addProject(Project{Project: api.Project{Id: feature.Project, Name: feature.Project}})
+		}
+		i := index[feature.Project]
+		state.Projects[i].Features = append(state.Projects[i].Features, r.portable(feature))
+	}
+	return state
+}
+
+// portable rewrites the segment ids of the strategies of feature for the
+// instance. Segments that do not exist yet get a placeholder, so an existing
+// feature using them is still seen to differ.
+func (r *restorer) portable(feature api.FeatureToggle) api.FeatureToggle {
+	var copied api.FeatureToggle
+	data, _ := json.Marshal(feature)
+	_ = json.Unmarshal(data, &copied)
+	for i := range copied.Environments {
+		for j := range copied.Environments[i].Strategies {
+			segments := copied.Environments[i].Strategies[j].Segments
+			for k, id := range segments {
+				if mapped, ok := r.segmentIDs[id]; ok {
+					segments[k] = mapped
+				} else {
+					segments[k] = -id
+				}
+			}
+		}
+	}
+	return copied
+}
+
+// restoreFeatures reconciles the projects and features and archives those
+// archived in the archive. The features being replaced are removed only once
+// everything else is restored, and are put back when their replacements
+// cannot be restored.
+func (r *restorer) restoreFeatures(ctx context.Context) error {
+	var features, replacing []api.FeatureToggle
+	for _, feature := range r.features {
+		if _, ok := r.replaced[feature.Name]; ok {
+			replacing = append(replacing, feature)
+		} else {
+			features = append(features, feature)
+		}
+	}
+	recorded := make(map[string]bool)
+	// segment ids are known now that the segments exist
+	if err := r.applyFeatures(ctx, features, recorded); err != nil {
+		return err
+	}
+	if len(replacing) > 0 {
+		if err := r.replaceFeatures(ctx, replacing, recorded); err != nil {
+			return err
+		}
+	}
+
+	for _, feature := range r.features {
+		if !feature.Archived {
+			continue
+		}
+		if _, _, err := r.client.FeatureToggles.ArchiveFeatureWithContext(ctx, feature.Project, feature.Name); err != nil {
+			return fmt.Errorf("archive feature %s: %w", feature.Name, err)
+		}
+	}
+	return nil
+}
+
+// applyFeatures reconciles the projects and features and records what
+// changed.
+func (r *restorer) applyFeatures(ctx context.Context, features []api.FeatureToggle, recorded map[string]bool) error {
+	plan, err := reconcile.NewPlan(ctx, r.client, r.desiredState(features), reconcile.Options{})
+	if err != nil {
+		return err
+	}
+	if n, err := plan.Apply(ctx); err != nil {
+		return fmt.Errorf("restore projects and features after %d changes: %w", n, err)
+	}
+	created := createdByPlan(plan)
+	for _, action := range plan.Actions {
+		item := ResourceFeature + " " + action.Feature
+		if action.Feature == "" {
+			item = ResourceProject + " " + action.Project
+		}
+		if recorded[item] {
+			continue
+		}
+		recorded[item] = true
+		if created[item] {
+			r.result.Created = append(r.result.Created, item)
+		} else {
+			r.result.Updated = append(r.result.Updated, item)
+		}
+	}
+	return nil
+}
+
+// replaceFeatures removes the features in the way of features and restores
+// them. The removed features are read first, so they can be put back if the
+// restore fails.
+func (r *restorer) replaceFeatures(ctx context.Context, features []api.FeatureToggle, recorded map[string]bool) error {
+	names := make([]string, 0, len(r.replaced))
+	for name := range r.replaced {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	previous, err := r.readReplaced(ctx, names)
+	if err != nil {
+		return err
+	}
+
+	var removed []api.FeatureToggle
+	for _, feature := range previous {
+		err = r.removeFeature(ctx, feature.Project, feature.Name, feature.Archived)
+		if err != nil {
+			err = fmt.Errorf("replace feature %s: %w", feature.Name, err)
+			break
+		}
+		removed = append(removed, feature)
+	}
+	if err == nil {
+		err = r.applyFeatures(ctx, features, recorded)
+	}
+	if err == nil {
+		return nil
+	}
+
+	// leave the instance as it was for the features that were replaced
+	projects := make(map[string]string)
+	for _, feature := range features {
+		projects[feature.Name] = feature.Project
+	}
+	for _, feature := range removed {
+		if putErr := r.putBack(ctx, feature, projects[feature.Name]); putErr != nil {
+			return fmt.Errorf("%w; put back feature %s: %v", err, feature.Name, putErr)
+		}
+	}
+	return fmt.Errorf("%w; the replaced features were put back", err)
+}
+
+// readReplaced reads the features in the way of the restore, with their
+// strategies, variants and tags.
+func (r *restorer) readReplaced(ctx context.Context, names []string) ([]api.FeatureToggle, error) {
+	toggles := r.client.FeatureToggles
+	archived, _, err := toggles.GetArchivedFeaturesWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read archived features: %w", err)
+	}
+	archivedIn := make(map[string]api.FeatureToggle)
+	for _, feature := range *archived {
+		archivedIn[feature.Name] = feature
+	}
+
+	features := make([]api.FeatureToggle, 0, len(names))
+	for _, name := range names {
+		var feature api.FeatureToggle
+		if project := r.replaced[name]; project != "" {
+			live, _, err := toggles.GetFeatureByNameWithContext(ctx, project, name)
+			if err != nil {
+				return nil, fmt.Errorf("read feature %s: %w", name, err)
+			}
+			feature = *live
+			feature.Project = project
+		} else {
+			listed := archivedIn[name]
+			var environments []string
+			if details, _, err := r.client.Projects.GetProjectByIdWithContext(ctx, listed.Project); err == nil {
+				for _, env := range details.Environments {
+					environments = append(environments, env.Environment)
+				}
+			}
+			var err error
+			if feature, err = readArchivedFeature(ctx, r.client, listed, environments); err != nil {
+				return nil, err
+			}
+		}
+		tags, _, err := r.client.FeatureTags.GetAllFeatureTagsWithContext(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("read the tags of %s: %w", name, err)
+		}
+		feature.Tags = tags.Tags
+		features = append(features, feature)
+	}
+	return features, nil
+}
+
+// removeFeature permanently deletes a feature, archiving it first when it is
+// live.
+func (r *restorer) removeFeature(ctx context.Context, project string, name string, archived bool) error {
+	toggles := r.client.FeatureToggles
+	if !archived {
+		if _, _, err := toggles.ArchiveFeatureWithContext(ctx, project, name); err != nil {
+			return err
+		}
+	}
+	_, _, err := toggles.DeleteArchivedFeatureWithContext(ctx, name)
+	return err
+}
+
+// putBack removes whatever the failed restore left of feature in project and
+// recreates feature as it was read.
+func (r *restorer) putBack(ctx context.Context, feature api.FeatureToggle, project string) error {
+	toggles := r.client.FeatureToggles
+	if _, _, err := toggles.ArchiveFeatureWithContext(ctx, project, feature.Name); err != nil && !errors.Is(err, api.ErrNotFound) {
+		return err
+	}
+	if _, _, err := toggles.DeleteArchivedFeatureWithContext(ctx, feature.Name); err != nil && !errors.Is(err, api.ErrNotFound) {
+		return err
+	}
+
+	// keep the project as it is, reconcile renames projects declared without
+	// a name
+	live, _, err := r.client.Projects.GetProjectByIdWithContext(ctx, feature.Project)
+	if err != nil {
+		return err
+	}
+	// the strategies already use the segment ids of the instance
+	state := reconcile.DesiredState{Projects: []reconcile.ProjectState{{
+		Project:  api.Project{Id: feature.Project, Name: live.Name, Description: live.Description},
+		Features: []api.FeatureToggle{feature},
+	}}}
+	plan, err := reconcile.NewPlan(ctx, r.client, state, reconcile.Options{})
+	if err != nil {
+		return err
+	}
+	if _, err := plan.Apply(ctx); err != nil {
+		return err
+	}
+	if feature.Archived {
+		if _, _, err := toggles.ArchiveFeatureWithContext(ctx, feature.Project, feature.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sameJSON compares values by their encoding, treating nil and empty slices
+// alike.
+func sameJSON(a interface{}, b interface{}) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return strings.Replace(string(ja), "null", "[]", -1) == strings.Replace(string(jb), "null", "[]", -1)
+}
@@ -0,0 +1,150 @@
+package evaluator
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sighphyre/go-unleash-api/api"
+	"github.com/sighphyre/go-unleash-api/internal/semver"
+)
+
+// ConstraintsMatch reports whether ctx satisfies every constraint.
+func ConstraintsMatch(constraints []api.Constraint, ctx Context) bool {
+	for _, c := range constraints {
+		if !ConstraintMatches(c, ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+// ConstraintMatches reports whether ctx satisfies c. Context values that cannot
+// be parsed for the operator never match, before Inverted is applied.
+func ConstraintMatches(c api.Constraint, ctx Context) bool {
+	matched := matchOperator(c, ctx)
+	if c.Inverted {
+		return !matched
+	}
+	return matched
+}
+
+func matchOperator(c api.Constraint, ctx Context) bool {
+	value := ctx.Field(c.ContextName)
+
+	switch c.Operator {
+	case api.OperatorIn:
+		return contains(c.Values, value)
+	case api.OperatorNotIn:
+		return !contains(c.Values, value)
+	case api.OperatorStrContains, api.OperatorStrStartsWith, api.OperatorStrEndsWith:
+		return matchString(c, value)
+	case api.OperatorNumEq, api.OperatorNumGt, api.OperatorNumGte, api.OperatorNumLt, api.OperatorNumLte:
+		return matchNumber(c, value)
+	case api.OperatorDateAfter, api.OperatorDateBefore:
+		return matchDate(c, ctx)
+	case api.OperatorSemverEq, api.OperatorSemverGt, api.OperatorSemverLt:
+		return matchSemver(c, value)
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func matchString(c api.Constraint, value string) bool {
+	if value == "" {
+		return false
+	}
+	if c.CaseInsensitive {
+		value = strings.ToLower(value)
+	}
+	for _, v := range c.Values {
+		if c.CaseInsensitive {
+			v = strings.ToLower(v)
+		}
+		switch c.Operator {
+		case api.OperatorStrContains:
+			if strings.Contains(value, v) {
+				return true
+			}
+		case api.OperatorStrStartsWith:
+			if strings.HasPrefix(value, v) {
+				return true
+			}
+		case api.OperatorStrEndsWith:
+			if strings.HasSuffix(value, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchNumber(c api.Constraint, value string) bool {
+	got, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false
+	}
+	want, err := strconv.ParseFloat(c.Value, 64)
+	if err != nil {
+		return false
+	}
+	switch c.Operator {
+	case api.OperatorNumEq:
+		return got == want
+	case api.OperatorNumGt:
+		return got > want
+	case api.OperatorNumGte:
+		return got >= want
+	case api.OperatorNumLt:
+		return got < want
+	case api.OperatorNumLte:
+		return got <= want
+	}
+	return false
+}
+
+func matchDate(c api.Constraint, ctx Context) bool {
+	want, err := time.Parse(time.RFC3339, c.Value)
+	if err != nil {
+		return false
+	}
+	got := ctx.now()
+	if c.ContextName != "currentTime" {
+		if got, err = time.Parse(time.RFC3339, ctx.Field(c.ContextName)); err != nil {
+			return false
+		}
+	}
+	if c.Operator == api.OperatorDateAfter {
+		return got.After(want)
+	}
+	return got.Before(want)
+}
+
+func matchSemver(c api.Constraint, value string) bool {
+	got, err := semver.Parse(value)
+	if err != nil {
+		return false
+	}
+	want, err := semver.Parse(c.Value)
+	if err != nil {
+		return false
+	}
+	cmp := semver.Compare(got, want)
+	switch c.Operator {
+	case api.OperatorSemverEq:
+		return cmp == 0
+	case api.OperatorSemverGt:
+		return cmp > 0
+	case api.OperatorSemverLt:
+		return cmp < 0
+	}
+	return false
+}
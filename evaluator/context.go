@@ -0,0 +1,47 @@
+package evaluator
+
+import "time"
+
+// Context describes who a feature is evaluated for. It mirrors the Unleash
+// context used by the official SDKs.
+type Context struct {
+	UserId        string
+	SessionId     string
+	RemoteAddress string
+	// Environment is the context environment field, which constraints may
+	// reference. It is unrelated to the Unleash environment being evaluated.
+	Environment string
+	AppName     string
+	// Hostname is matched by the applicationHostname strategy.
+	Hostname string
+	// CurrentTime is used by the DATE_ operators. The zero value means now.
+	CurrentTime time.Time
+	Properties  map[string]string
+}
+
+// Field returns the value of the named context field, falling back to
+// Properties for custom fields.
+func (c Context) Field(name string) string {
+	switch name {
+	case "userId":
+		return c.UserId
+	case "sessionId":
+		return c.SessionId
+	case "remoteAddress":
+		return c.RemoteAddress
+	case "environment":
+		return c.Environment
+	case "appName":
+		return c.AppName
+	case "currentTime":
+		return c.now().Format(time.RFC3339Nano)
+	}
+	return c.Properties[name]
+}
+
+func (c Context) now() time.Time {
+	if c.CurrentTime.IsZero() {
+		return time.Now()
+	}
+	return c.CurrentTime
+}
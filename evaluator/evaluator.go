@@ -0,0 +1,60 @@
+// Package evaluator evaluates feature toggles fetched through the Admin API
+// offline, using the same strategies, constraint operators and stickiness
+// hashing as the official Unleash SDKs.
+package evaluator
+
+import (
+	"github.com/sighphyre/go-unleash-api/api"
+)
+
+// Result is the outcome of evaluating a feature for a context.
+type Result struct {
+	Enabled bool
+	// Strategy is the first strategy that enabled the feature. It is nil when
+	// the feature is disabled or enabled without any strategies.
+	Strategy *api.FeatureStrategy
+}
+
+// Evaluate reports whether feature is enabled in environment for ctx, without
+// any segments: strategies limited to segments never match. Use
+// EvaluateWithSegments to evaluate them.
+func Evaluate(feature api.FeatureToggle, environment string, ctx Context) Result {
+	return EvaluateWithSegments(feature, environment, ctx, nil)
+}
+
+// EvaluateWithSegments reports whether feature is enabled in environment for
+// ctx. Archived features, unknown environments and disabled environments are
+// disabled. An enabled environment without strategies is enabled for
+// everyone; otherwise strategies are tried in sort order and the first one
+// whose constraints, segments and rule match enables the feature. The
+// constraints of a strategy's segments apply on top of its own constraints,
+// and a strategy using a segment missing from segments does not match.
+func EvaluateWithSegments(feature api.FeatureToggle, environment string, ctx Context, segments Segments) Result {
+	if feature.Archived {
+		return Result{}
+	}
+	env, ok := findEnvironment(feature, environment)
+	if !ok || !env.Enabled {
+		return Result{}
+	}
+	if len(env.Strategies) == 0 {
+		return Result{Enabled: true}
+	}
+
+	for _, strategy := range api.SortedStrategies(env.Strategies) {
+		if ConstraintsMatch(strategy.Constraints, ctx) && segments.match(strategy.Segments, ctx) && StrategyEnabled(feature.Name, strategy, ctx) {
+			matched := strategy
+			return Result{Enabled: true, Strategy: &matched}
+		}
+	}
+	return Result{}
+}
+
+func findEnvironment(feature api.FeatureToggle, name string) (api.Environment, bool) {
+	for _, env := range feature.Environments {
+		if env.Name == name {
+			return env, true
+		}
+	}
+	return api.Environment{}, false
+}
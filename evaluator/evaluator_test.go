@@ -0,0 +1,186 @@
+package evaluator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sighphyre/go-unleash-api/api"
+)
+
+func feature(strategies ...api.FeatureStrategy) api.FeatureToggle {
+	return api.FeatureToggle{
+		Name: "checkout",
+		Environments: []api.Environment{
+			{Name: "development", Enabled: false},
+			{Name: "production", Enabled: true, Strategies: strategies},
+		},
+	}
+}
+
+func TestNormalizedValue(t *testing.T) {
+	// Values from the Unleash client specification.
+	tests := []struct {
+		id      string
+		groupId string
+		want    int
+	}{
+		{"123", "gr1", 73},
+		{"999", "groupX", 25},
+		{"", "gr1", 0},
+	}
+	for _, tt := range tests {
+		if got := NormalizedValue(tt.id, tt.groupId); got != tt.want {
+			t.Errorf("NormalizedValue(%q, %q) = %d, want %d", tt.id, tt.groupId, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	rollout := func(percentage int, stickiness string) api.FeatureStrategy {
+		return api.NewFeatureStrategy(api.FlexibleRolloutParams{Rollout: percentage, Stickiness: stickiness, GroupId: "gr1"})
+	}
+	users := api.NewFeatureStrategy(api.UserWithIdParams{UserIds: []string{"alice", "bob"}})
+	ips := api.NewFeatureStrategy(api.RemoteAddressParams{IPs: []string{"10.0.0.0/8", "192.168.1.1"}})
+	hosts := api.NewFeatureStrategy(api.ApplicationHostnameParams{HostNames: []string{"web-1"}})
+	internal := api.FeatureStrategy{
+		Name:        api.StrategyDefault,
+		Constraints: []api.Constraint{{ContextName: "email", Operator: api.OperatorStrEndsWith, Values: []string{"@EXAMPLE.com"}, CaseInsensitive: true}},
+	}
+	gradual := api.FeatureStrategy{
+		Name:       StrategyGradualRolloutUserId,
+		Parameters: map[string]interface{}{"percentage": 73, "groupId": "gr1"},
+	}
+
+	tests := []struct {
+		name        string
+		feature     api.FeatureToggle
+		environment string
+		ctx         Context
+		want        bool
+	}{
+		{"NoStrategies", feature(), "production", Context{}, true},
+		{"DisabledEnvironment", feature(api.FeatureStrategy{Name: "default"}), "development", Context{}, false},
+		{"UnknownEnvironment", feature(api.FeatureStrategy{Name: "default"}), "staging", Context{}, false},
+		{"Default", feature(api.FeatureStrategy{Name: "default"}), "production", Context{}, true},
+		{"UnknownStrategy", feature(api.FeatureStrategy{Name: "custom"}), "production", Context{}, false},
+		{"UserWithId", feature(users), "production", Context{UserId: "bob"}, true},
+		{"UserWithIdOther", feature(users), "production", Context{UserId: "carol"}, false},
+		{"RolloutIncluded", feature(rollout(73, "userId")), "production", Context{UserId: "123"}, true},
+		{"RolloutExcluded", feature(rollout(72, "userId")), "production", Context{UserId: "123"}, false},
+		{"RolloutDefaultStickinessFallsBackToSession", feature(rollout(73, "default")), "production", Context{SessionId: "123"}, true},
+		{"RolloutCustomStickiness", feature(rollout(73, "tenant")), "production", Context{Properties: map[string]string{"tenant": "123"}}, true},
+		{"RolloutMissingStickiness", feature(rollout(100, "userId")), "production", Context{SessionId: "123"}, false},
+		{"RolloutZero", feature(rollout(0, "userId")), "production", Context{UserId: "123"}, false},
+		{"GradualRolloutUserId", feature(gradual), "production", Context{UserId: "123"}, true},
+		{"RemoteAddressCIDR", feature(ips), "production", Context{RemoteAddress: "10.1.2.3"}, true},
+		{"RemoteAddressExact", feature(ips), "production", Context{RemoteAddress: "192.168.1.1"}, true},
+		{"RemoteAddressOutside", feature(ips), "production", Context{RemoteAddress: "192.168.1.2"}, false},
+		{"ApplicationHostname", feature(hosts), "production", Context{Hostname: "WEB-1"}, true},
+		{"ConstraintMatches", feature(internal), "production", Context{Properties: map[string]string{"email": "jane@example.com"}}, true},
+		{"ConstraintFails", feature(internal), "production", Context{Properties: map[string]string{"email": "jane@other.com"}}, false},
+		{"SecondStrategyMatches", feature(users, api.FeatureStrategy{Name: "default"}), "production", Context{UserId: "carol"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Evaluate(tt.feature, tt.environment, tt.ctx); got.Enabled != tt.want {
+				t.Errorf("Evaluate() enabled = %v, want %v", got.Enabled, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluate_ArchivedFeature(t *testing.T) {
+	archived := feature(api.FeatureStrategy{Name: "default"})
+	archived.Archived = true
+	if got := Evaluate(archived, "production", Context{}); got.Enabled {
+		t.Error("Evaluate() enabled an archived feature")
+	}
+}
+
+func TestEvaluate_ReturnsMatchedStrategyInSortOrder(t *testing.T) {
+	toggle := feature(
+		api.FeatureStrategy{ID: "second", Name: "default", SortOrder: 2},
+		api.FeatureStrategy{ID: "first", Name: "default", SortOrder: 1},
+	)
+	got := Evaluate(toggle, "production", Context{})
+	if !got.Enabled || got.Strategy == nil || got.Strategy.ID != "first" {
+		t.Errorf("Evaluate() = %+v, want the strategy with the lowest sort order", got)
+	}
+}
+
+func TestEvaluateWithSegments(t *testing.T) {
+	segments := NewSegments([]api.Segment{
+		{ID: 1, Name: "web", Constraints: []api.Constraint{{ContextName: "appName", Operator: api.OperatorIn, Values: []string{"web"}}}},
+		{ID: 2, Name: "eu", Constraints: []api.Constraint{{ContextName: "region", Operator: api.OperatorIn, Values: []string{"eu"}}}},
+	})
+	strategy := func(constraints []api.Constraint, ids ...int) api.FeatureToggle {
+		return feature(api.FeatureStrategy{Name: "default", Constraints: constraints, Segments: ids})
+	}
+	inUS := []api.Constraint{{ContextName: "region", Operator: api.OperatorIn, Values: []string{"us"}}}
+	webEU := Context{AppName: "web", Properties: map[string]string{"region": "eu"}}
+
+	tests := []struct {
+		name     string
+		feature  api.FeatureToggle
+		segments Segments
+		ctx      Context
+		want     bool
+	}{
+		{"AllSegmentsMatch", strategy(nil, 1, 2), segments, webEU, true},
+		{"OneSegmentFails", strategy(nil, 1, 2), segments, Context{AppName: "web"}, false},
+		{"StrategyConstraintsStillApply", strategy(inUS, 1), segments, webEU, false},
+		{"UnknownSegment", strategy(nil, 1, 3), segments, webEU, false},
+		{"NoSegmentsGiven", strategy(nil, 1), nil, webEU, false},
+		{"NoSegmentsUsed", strategy(nil), nil, webEU, true},
+	}
+	for _, tt := range tests {
+		if got := EvaluateWithSegments(tt.feature, "production", tt.ctx, tt.segments); got.Enabled != tt.want {
+			t.Errorf("%s: EvaluateWithSegments() enabled = %v, want %v", tt.name, got.Enabled, tt.want)
+		}
+	}
+	if got := Evaluate(strategy(nil, 1), "production", webEU); got.Enabled {
+		t.Error("Evaluate() enabled a strategy limited to a segment")
+	}
+}
+
+func TestConstraintMatches(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	ctx := Context{
+		UserId:      "42",
+		AppName:     "web",
+		CurrentTime: now,
+		Properties:  map[string]string{"appVersion": "2.1.0-beta.2", "age": "30"},
+	}
+
+	tests := []struct {
+		name       string
+		constraint api.Constraint
+		want       bool
+	}{
+		{"In", api.Constraint{ContextName: "appName", Operator: api.OperatorIn, Values: []string{"ios", "web"}}, true},
+		{"InIsCaseSensitive", api.Constraint{ContextName: "appName", Operator: api.OperatorIn, Values: []string{"WEB"}}, false},
+		{"NotIn", api.Constraint{ContextName: "appName", Operator: api.OperatorNotIn, Values: []string{"ios"}}, true},
+		{"NotInMissingField", api.Constraint{ContextName: "region", Operator: api.OperatorNotIn, Values: []string{"eu"}}, true},
+		{"Inverted", api.Constraint{ContextName: "appName", Operator: api.OperatorIn, Values: []string{"web"}, Inverted: true}, false},
+		{"StrStartsWith", api.Constraint{ContextName: "appName", Operator: api.OperatorStrStartsWith, Values: []string{"w"}}, true},
+		{"StrContainsCaseSensitive", api.Constraint{ContextName: "appName", Operator: api.OperatorStrContains, Values: []string{"E"}}, false},
+		{"StrContainsCaseInsensitive", api.Constraint{ContextName: "appName", Operator: api.OperatorStrContains, Values: []string{"E"}, CaseInsensitive: true}, true},
+		{"NumGte", api.Constraint{ContextName: "age", Operator: api.OperatorNumGte, Value: "30"}, true},
+		{"NumLt", api.Constraint{ContextName: "age", Operator: api.OperatorNumLt, Value: "30"}, false},
+		{"NumEqUserId", api.Constraint{ContextName: "userId", Operator: api.OperatorNumEq, Value: "42.0"}, true},
+		{"NumNotANumber", api.Constraint{ContextName: "appName", Operator: api.OperatorNumGt, Value: "1"}, false},
+		{"NumNotANumberInverted", api.Constraint{ContextName: "appName", Operator: api.OperatorNumGt, Value: "1", Inverted: true}, true},
+		{"DateAfter", api.Constraint{ContextName: "currentTime", Operator: api.OperatorDateAfter, Value: "2024-01-01T00:00:00Z"}, true},
+		{"DateBefore", api.Constraint{ContextName: "currentTime", Operator: api.OperatorDateBefore, Value: "2024-01-01T00:00:00Z"}, false},
+		{"SemverGt", api.Constraint{ContextName: "appVersion", Operator: api.OperatorSemverGt, Value: "2.1.0-beta.1"}, true},
+		{"SemverLtRelease", api.Constraint{ContextName: "appVersion", Operator: api.OperatorSemverLt, Value: "2.1.0"}, true},
+		{"SemverEqInvalid", api.Constraint{ContextName: "appName", Operator: api.OperatorSemverEq, Value: "1.0.0"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConstraintMatches(tt.constraint, ctx); got != tt.want {
+				t.Errorf("ConstraintMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
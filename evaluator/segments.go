@@ -0,0 +1,28 @@
+package evaluator
+
+import "github.com/sighphyre/go-unleash-api/api"
+
+// Segments holds the constraints of segments by segment id, so strategies
+// limited to segments can be evaluated.
+type Segments map[int][]api.Constraint
+
+// NewSegments indexes segments, as returned by the Segments service, by id.
+func NewSegments(segments []api.Segment) Segments {
+	indexed := make(Segments, len(segments))
+	for _, segment := range segments {
+		indexed[segment.ID] = segment.Constraints
+	}
+	return indexed
+}
+
+// match reports whether ctx satisfies the constraints of every segment in
+// ids. Like the SDKs, a segment that is not known never matches.
+func (s Segments) match(ids []int, ctx Context) bool {
+	for _, id := range ids {
+		constraints, ok := s[id]
+		if !ok || !ConstraintsMatch(constraints, ctx) {
+			return false
+		}
+	}
+	return true
+}
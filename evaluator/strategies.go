@@ -0,0 +1,166 @@
+package evaluator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/sighphyre/go-unleash-api/api"
+	"github.com/sighphyre/go-unleash-api/internal/murmur3"
+)
+
+// Names of the deprecated gradual rollout strategies, which older Unleash
+// instances may still return.
+const (
+	StrategyGradualRolloutUserId    = "gradualRolloutUserId"
+	StrategyGradualRolloutSessionId = "gradualRolloutSessionId"
+	StrategyGradualRolloutRandom    = "gradualRolloutRandom"
+)
+
+// NormalizedValue maps id into a bucket between 1 and 100 for the given group,
+// exactly like the Unleash SDKs. It returns 0 when id is empty.
+func NormalizedValue(id string, groupId string) int {
+	return normalize(id, groupId, 0, 100)
+}
+
+func normalize(id string, groupId string, seed uint32, modulus uint32) int {
+	if id == "" {
+		return 0
+	}
+	return int(murmur3.Sum32([]byte(groupId+":"+id), seed)%modulus) + 1
+}
+
+// randomValue returns a bucket between 1 and 100 for random stickiness.
+var randomValue = func() int {
+	return rand.Intn(100) + 1
+}
+
+// StrategyEnabled reports whether the strategy's own rule, ignoring its
+// constraints, enables feature for ctx. Unknown strategies are disabled.
+func StrategyEnabled(feature string, strategy api.FeatureStrategy, ctx Context) bool {
+	switch strategy.Name {
+	case api.StrategyDefault:
+		return true
+	case api.StrategyUserWithId:
+		var params api.UserWithIdParams
+		if strategy.DecodeParameters(&params) != nil {
+			return false
+		}
+		return ctx.UserId != "" && contains(params.UserIds, ctx.UserId)
+	case api.StrategyFlexibleRollout:
+		var params api.FlexibleRolloutParams
+		if strategy.DecodeParameters(&params) != nil {
+			return false
+		}
+		groupId := params.GroupId
+		if groupId == "" {
+			groupId = feature
+		}
+		return inRollout(stickinessValue(params.Stickiness, ctx), groupId, params.Rollout)
+	case StrategyGradualRolloutUserId, StrategyGradualRolloutSessionId, StrategyGradualRolloutRandom:
+		return gradualRollout(feature, strategy, ctx)
+	case api.StrategyRemoteAddress:
+		var params api.RemoteAddressParams
+		if strategy.DecodeParameters(&params) != nil {
+			return false
+		}
+		return matchAddress(params.IPs, ctx.RemoteAddress)
+	case api.StrategyApplicationHostname:
+		var params api.ApplicationHostnameParams
+		if strategy.DecodeParameters(&params) != nil {
+			return false
+		}
+		for _, host := range params.HostNames {
+			if ctx.Hostname != "" && strings.EqualFold(host, ctx.Hostname) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// stickinessValue returns the context value used for bucketing, or "random"
+// to request a random bucket.
+func stickinessValue(stickiness string, ctx Context) string {
+	switch stickiness {
+	case "", "default":
+		if ctx.UserId != "" {
+			return ctx.UserId
+		}
+		if ctx.SessionId != "" {
+			return ctx.SessionId
+		}
+		return "random"
+	case "random":
+		return "random"
+	}
+	return ctx.Field(stickiness)
+}
+
+func inRollout(id string, groupId string, percentage int) bool {
+	if percentage <= 0 || id == "" {
+		return false
+	}
+	if id == "random" {
+		return randomValue() <= percentage
+	}
+	return NormalizedValue(id, groupId) <= percentage
+}
+
+func gradualRollout(feature string, strategy api.FeatureStrategy, ctx Context) bool {
+	params := stringParameters(strategy.Parameters)
+	percentage, err := strconv.ParseFloat(params["percentage"], 64)
+	if err != nil {
+		return false
+	}
+	groupId := params["groupId"]
+	if groupId == "" {
+		groupId = feature
+	}
+	switch strategy.Name {
+	case StrategyGradualRolloutUserId:
+		return ctx.UserId != "" && inRollout(ctx.UserId, groupId, int(percentage))
+	case StrategyGradualRolloutSessionId:
+		return ctx.SessionId != "" && inRollout(ctx.SessionId, groupId, int(percentage))
+	}
+	return inRollout("random", groupId, int(percentage))
+}
+
+func matchAddress(ips []string, remoteAddress string) bool {
+	addr := net.ParseIP(remoteAddress)
+	for _, ip := range ips {
+		if ip == remoteAddress {
+			return true
+		}
+		if addr == nil {
+			continue
+		}
+		if _, network, err := net.ParseCIDR(ip); err == nil && network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// stringParameters flattens untyped strategy parameters into strings.
+func stringParameters(parameters interface{}) map[string]string {
+	data, err := json.Marshal(parameters)
+	if err != nil {
+		return nil
+	}
+	var raw map[string]interface{}
+	if json.Unmarshal(data, &raw) != nil {
+		return nil
+	}
+	params := make(map[string]string, len(raw))
+	for name, value := range raw {
+		if value != nil {
+			params[name] = fmt.Sprint(value)
+		}
+	}
+	return params
+}
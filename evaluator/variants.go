@@ -0,0 +1,123 @@
+package evaluator
+
+import (
+	"math/rand"
+	"strconv"
+
+	"github.com/sighphyre/go-unleash-api/api"
+)
+
+// variantSeed is the murmur3 seed the Unleash SDKs use for variant hashing, so
+// that variant buckets are independent from rollout buckets.
+const variantSeed = 86028157
+
+// VariantResult is the variant a context receives.
+type VariantResult struct {
+	Name    string
+	Payload *api.VariantPayload
+	Enabled bool
+}
+
+// DisabledVariant is returned when a feature is disabled or has no variants.
+var DisabledVariant = VariantResult{Name: "disabled"}
+
+// randomSeed returns a stickiness value for contexts without one.
+var randomSeed = func() string {
+	return strconv.Itoa(rand.Int())
+}
+
+// GetVariant evaluates feature in environment for ctx without any segments,
+// like Evaluate, and selects its variant.
+func GetVariant(feature api.FeatureToggle, environment string, ctx Context) VariantResult {
+	return GetVariantWithSegments(feature, environment, ctx, nil)
+}
+
+// GetVariantWithSegments evaluates feature in environment for ctx, like
+// EvaluateWithSegments, and selects its variant. Variants of the matching
+// strategy take precedence over the environment variants, which take
+// precedence over the legacy feature variants. It returns DisabledVariant when
+// the feature is disabled for ctx.
+func GetVariantWithSegments(feature api.FeatureToggle, environment string, ctx Context, segments Segments) VariantResult {
+	result := EvaluateWithSegments(feature, environment, ctx, segments)
+	if !result.Enabled {
+		return DisabledVariant
+	}
+	if result.Strategy != nil && len(result.Strategy.Variants) > 0 {
+		groupId := feature.Name
+		var params api.FlexibleRolloutParams
+		if result.Strategy.Name == api.StrategyFlexibleRollout && result.Strategy.DecodeParameters(&params) == nil && params.GroupId != "" {
+			groupId = params.GroupId
+		}
+		return SelectVariant(groupId, result.Strategy.Variants, ctx)
+	}
+	if env, ok := findEnvironment(feature, environment); ok && len(env.Variants) > 0 {
+		return SelectVariant(feature.Name, env.Variants, ctx)
+	}
+	return SelectVariant(feature.Name, feature.Variants, ctx)
+}
+
+// SelectVariant picks the variant of the named feature that ctx receives, like
+// the Unleash SDKs: the first variant with an override matching ctx wins,
+// otherwise the stickiness value of the first variant is hashed into the total
+// weight. Weights of variable variants are spread over whatever the fixed
+// variants leave of 1000.
+func SelectVariant(featureName string, variants []api.Variant, ctx Context) VariantResult {
+	variants = api.DistributeVariantWeights(variants)
+
+	totalWeight := 0
+	for _, variant := range variants {
+		totalWeight += variant.Weight
+	}
+	if totalWeight <= 0 {
+		return DisabledVariant
+	}
+
+	for _, variant := range variants {
+		if overrideMatches(variant, ctx) {
+			return variantResult(variant)
+		}
+	}
+
+	target := normalize(variantStickinessValue(variants[0].Stickiness, ctx), featureName, variantSeed, uint32(totalWeight))
+	counter := 0
+	for _, variant := range variants {
+		if variant.Weight <= 0 {
+			continue
+		}
+		counter += variant.Weight
+		if counter >= target {
+			return variantResult(variant)
+		}
+	}
+	return DisabledVariant
+}
+
+func variantResult(variant api.Variant) VariantResult {
+	return VariantResult{Name: variant.Name, Payload: variant.Payload, Enabled: true}
+}
+
+func overrideMatches(variant api.Variant, ctx Context) bool {
+	for _, override := range variant.Overrides {
+		if contains(override.Values, ctx.Field(override.ContextName)) {
+			return true
+		}
+	}
+	return false
+}
+
+// variantStickinessValue differs from the rollout stickiness in falling back
+// to remoteAddress and in never returning an empty value.
+func variantStickinessValue(stickiness string, ctx Context) string {
+	if stickiness == "" || stickiness == "default" {
+		for _, value := range []string{ctx.UserId, ctx.SessionId, ctx.RemoteAddress} {
+			if value != "" {
+				return value
+			}
+		}
+		return randomSeed()
+	}
+	if value := ctx.Field(stickiness); value != "" {
+		return value
+	}
+	return randomSeed()
+}
@@ -0,0 +1,156 @@
+package evaluator
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/sighphyre/go-unleash-api/api"
+)
+
+func TestSelectVariant_NoVariants(t *testing.T) {
+	if got := SelectVariant("checkout", nil, Context{UserId: "1"}); got != DisabledVariant {
+		t.Errorf("SelectVariant() = %+v, want DisabledVariant", got)
+	}
+}
+
+func TestSelectVariant_ClientSpecification(t *testing.T) {
+	// Values from the Unleash client specification (08-variants). The SDKs
+	// receive weights already distributed, so they are fixed here.
+	weighted := func(weights ...int) []api.Variant {
+		variants := make([]api.Variant, len(weights))
+		for i, weight := range weights {
+			variants[i] = api.Variant{Name: "variant" + strconv.Itoa(i+1), Weight: weight, WeightType: api.WeightTypeFix}
+		}
+		return variants
+	}
+	tests := []struct {
+		feature  string
+		variants []api.Variant
+		userId   string
+		want     string
+	}{
+		{"Feature.Variants.A", weighted(1), "0", "variant1"},
+		{"Feature.Variants.B", weighted(1, 1), "2", "variant2"},
+		{"Feature.Variants.B", weighted(1, 1), "0", "variant1"},
+		{"Feature.Variants.C", weighted(33, 33, 33), "232", "variant1"},
+		{"Feature.Variants.C", weighted(33, 33, 33), "607", "variant2"},
+		{"Feature.Variants.C", weighted(33, 33, 33), "656", "variant3"},
+	}
+	for _, tt := range tests {
+		if got := SelectVariant(tt.feature, tt.variants, Context{UserId: tt.userId}); got.Name != tt.want {
+			t.Errorf("SelectVariant(%s, userId=%s) = %s, want %s", tt.feature, tt.userId, got.Name, tt.want)
+		}
+	}
+}
+
+func TestSelectVariant_Override(t *testing.T) {
+	variants := []api.Variant{
+		{Name: "blue", WeightType: "variable", Stickiness: "default"},
+		{Name: "green", WeightType: "variable", Stickiness: "default", Overrides: []api.VariantOverride{
+			{ContextName: "userId", Values: []string{"1", "2"}},
+		}},
+		{Name: "red", WeightType: "variable", Stickiness: "default", Overrides: []api.VariantOverride{
+			{ContextName: "tenant", Values: []string{"acme"}},
+		}},
+	}
+	for i := 0; i < 20; i++ {
+		if got := SelectVariant("checkout", variants, Context{UserId: "2"}); got.Name != "green" {
+			t.Fatalf("SelectVariant() = %s, want green from the userId override", got.Name)
+		}
+	}
+	ctx := Context{UserId: "3", Properties: map[string]string{"tenant": "acme"}}
+	if got := SelectVariant("checkout", variants, ctx); got.Name != "red" {
+		t.Errorf("SelectVariant() = %s, want red from the tenant override", got.Name)
+	}
+}
+
+func TestSelectVariant_IsSticky(t *testing.T) {
+	variants := []api.Variant{
+		{Name: "blue", WeightType: "variable", Stickiness: "default", Payload: &api.VariantPayload{Type: "string", Value: "b"}},
+		{Name: "green", WeightType: "variable", Stickiness: "default"},
+	}
+	for i := 0; i < 50; i++ {
+		ctx := Context{UserId: strconv.Itoa(i)}
+		first := SelectVariant("checkout", variants, ctx)
+		if !first.Enabled {
+			t.Fatalf("SelectVariant() = %+v, want an enabled variant", first)
+		}
+		if again := SelectVariant("checkout", variants, ctx); again != first {
+			t.Fatalf("SelectVariant() for user %d = %s then %s", i, first.Name, again.Name)
+		}
+		if first.Name == "blue" && (first.Payload == nil || first.Payload.Value != "b") {
+			t.Fatalf("SelectVariant() = %+v, want the blue payload", first)
+		}
+	}
+}
+
+func TestSelectVariant_CustomStickiness(t *testing.T) {
+	variants := []api.Variant{
+		{Name: "blue", WeightType: "variable", Stickiness: "tenant"},
+		{Name: "green", WeightType: "variable", Stickiness: "tenant"},
+	}
+	tenant := map[string]string{"tenant": "acme"}
+	want := SelectVariant("checkout", variants, Context{UserId: "1", Properties: tenant})
+	for i := 2; i < 50; i++ {
+		if got := SelectVariant("checkout", variants, Context{UserId: strconv.Itoa(i), Properties: tenant}); got != want {
+			t.Fatalf("SelectVariant() = %s for user %d, want %s for every user of the tenant", got.Name, i, want.Name)
+		}
+	}
+}
+
+func TestSelectVariant_Distribution(t *testing.T) {
+	variants := []api.Variant{
+		{Name: "fixed", WeightType: "fix", Weight: 500, Stickiness: "default"},
+		{Name: "blue", WeightType: "variable", Stickiness: "default"},
+		{Name: "green", WeightType: "variable", Stickiness: "default"},
+		{Name: "off", WeightType: "fix", Weight: 0, Stickiness: "default"},
+	}
+	const users = 20000
+	counts := make(map[string]int)
+	for i := 0; i < users; i++ {
+		counts[SelectVariant("checkout", variants, Context{UserId: strconv.Itoa(i)}).Name]++
+	}
+
+	want := map[string]float64{"fixed": 0.5, "blue": 0.25, "green": 0.25}
+	for name, share := range want {
+		got := float64(counts[name]) / users
+		if got < share-0.02 || got > share+0.02 {
+			t.Errorf("variant %s got %.3f of users, want about %.2f", name, got, share)
+		}
+	}
+	if counts["off"] != 0 {
+		t.Errorf("variant off with weight 0 got %d users", counts["off"])
+	}
+}
+
+func TestGetVariant(t *testing.T) {
+	toggle := feature(api.NewFeatureStrategy(api.UserWithIdParams{UserIds: []string{"alice"}}))
+	toggle.Variants = []api.Variant{{Name: "blue", WeightType: "variable", Stickiness: "default"}}
+
+	if got := GetVariant(toggle, "production", Context{UserId: "alice"}); got.Name != "blue" || !got.Enabled {
+		t.Errorf("GetVariant() for an enabled user = %+v, want blue", got)
+	}
+	if got := GetVariant(toggle, "production", Context{UserId: "bob"}); got != DisabledVariant {
+		t.Errorf("GetVariant() for a disabled user = %+v, want DisabledVariant", got)
+	}
+}
+
+func TestGetVariant_Precedence(t *testing.T) {
+	strategy := api.FeatureStrategy{Name: "default", Variants: []api.Variant{{Name: "strategy", Stickiness: "default"}}}
+	toggle := feature(strategy)
+	toggle.Variants = []api.Variant{{Name: "feature", Stickiness: "default"}}
+	toggle.Environments[1].Variants = []api.Variant{{Name: "environment", Stickiness: "default"}}
+
+	ctx := Context{UserId: "1"}
+	if got := GetVariant(toggle, "production", ctx); got.Name != "strategy" {
+		t.Errorf("GetVariant() = %s, want the strategy variant", got.Name)
+	}
+	toggle.Environments[1].Strategies[0].Variants = nil
+	if got := GetVariant(toggle, "production", ctx); got.Name != "environment" {
+		t.Errorf("GetVariant() = %s, want the environment variant", got.Name)
+	}
+	toggle.Environments[1].Variants = nil
+	if got := GetVariant(toggle, "production", ctx); got.Name != "feature" {
+		t.Errorf("GetVariant() = %s, want the feature variant", got.Name)
+	}
+}
@@ -0,0 +1,234 @@
+package flagfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/sighphyre/go-unleash-api/api"
+	"github.com/sighphyre/go-unleash-api/reconcile"
+)
+
+// DesiredState converts the file for reconcile.NewPlan. Features declare the
+// project they are listed in.
+func (f *File) DesiredState() reconcile.DesiredState {
+	state := reconcile.DesiredState{Projects: make([]reconcile.ProjectState, 0, len(f.Projects))}
+	for _, project := range f.Projects {
+		projectState := reconcile.ProjectState{
+			Project: api.Project{
+				Id:          project.Id,
+				Name:        project.Name,
+				Description: project.Description,
+			},
+			Environments: project.Environments,
+		}
+		for _, feature := range project.Features {
+			toggle := feature.FeatureToggle()
+			toggle.Project = project.Id
+			projectState.Features = append(projectState.Features, toggle)
+		}
+		state.Projects = append(state.Projects, projectState)
+	}
+	return state
+}
+
+// FromDesiredState converts a desired state into a file of the current
+// SchemaVersion. It fails when strategy parameters cannot be decoded.
+func FromDesiredState(state reconcile.DesiredState) (*File, error) {
+	file := &File{Version: SchemaVersion, Projects: make([]Project, 0, len(state.Projects))}
+	for _, projectState := range state.Projects {
+		project := Project{
+			Id:           projectState.Id,
+			Name:         projectState.Name,
+			Description:  projectState.Description,
+			Environments: projectState.Environments,
+		}
+		for _, toggle := range projectState.Features {
+			feature, err := FromFeatureToggle(toggle)
+			if err != nil {
+				return nil, err
+			}
+			project.Features = append(project.Features, feature)
+		}
+		file.Projects = append(file.Projects, project)
+	}
+	return file, nil
+}
+
+// FeatureToggle converts the feature into the api type. Strategies are given
+// the sort order of their position; the project is left empty.
+func (f Feature) FeatureToggle() api.FeatureToggle {
+	toggle := api.FeatureToggle{
+		Name:        f.Name,
+		Type:        f.Type,
+		Description: f.Description,
+		Stale:       f.Stale,
+		Variants:    apiVariants(f.Variants),
+	}
+	if toggle.Type == "" {
+		toggle.Type = "release"
+	}
+	for _, tag := range f.Tags {
+		toggle.Tags = append(toggle.Tags, api.FeatureTag{Type: tag.Type, Value: tag.Value})
+	}
+	for _, env := range f.Environments {
+		environment := api.Environment{
+			Name:     env.Name,
+			Enabled:  env.Enabled,
+			Variants: apiVariants(env.Variants),
+		}
+		for i, strategy := range env.Strategies {
+			environment.Strategies = append(environment.Strategies, strategy.featureStrategy(i))
+		}
+		toggle.Environments = append(toggle.Environments, environment)
+	}
+	return toggle
+}
+
+// FromFeatureToggle converts an api feature, ordering strategies by their sort
+// order. Ids, timestamps and the archived state are not part of the file. It
+// fails when strategy parameters cannot be decoded.
+func FromFeatureToggle(toggle api.FeatureToggle) (Feature, error) {
+	feature := Feature{
+		Name:        toggle.Name,
+		Type:        toggle.Type,
+		Description: toggle.Description,
+		Stale:       toggle.Stale,
+		Variants:    fileVariants(toggle.Variants),
+	}
+	for _, tag := range toggle.Tags {
+		feature.Tags = append(feature.Tags, Tag{Type: tag.Type, Value: tag.Value})
+	}
+	for _, environment := range toggle.Environments {
+		env := Environment{
+			Name:     environment.Name,
+			Enabled:  environment.Enabled,
+			Variants: fileVariants(environment.Variants),
+		}
+		for _, strategy := range api.SortedStrategies(environment.Strategies) {
+			s, err := fileStrategy(strategy)
+			if err != nil {
+				return Feature{}, fmt.Errorf("feature %s, environment %s: %w", toggle.Name, environment.Name, err)
+			}
+			env.Strategies = append(env.Strategies, s)
+		}
+		feature.Environments = append(feature.Environments, env)
+	}
+	return feature, nil
+}
+
+func (s Strategy) featureStrategy(sortOrder int) api.FeatureStrategy {
+	strategy := api.FeatureStrategy{
+		Name:      s.Name,
+		SortOrder: sortOrder,
+		Segments:  s.Segments,
+		Variants:  apiVariants(s.Variants),
+	}
+	if len(s.Parameters) > 0 {
+		strategy.Parameters = s.Parameters
+	}
+	for _, constraint := range s.Constraints {
+		strategy.Constraints = append(strategy.Constraints, constraint.apiConstraint())
+	}
+	return strategy
+}
+
+func fileStrategy(strategy api.FeatureStrategy) (Strategy, error) {
+	s := Strategy{
+		Name:     strategy.Name,
+		Segments: strategy.Segments,
+		Variants: fileVariants(strategy.Variants),
+	}
+	var raw map[string]interface{}
+	if err := strategy.DecodeParameters(&raw); err != nil {
+		return Strategy{}, fmt.Errorf("strategy %s parameters: %w", strategy.Name, err)
+	}
+	if len(raw) > 0 {
+		s.Parameters = make(map[string]string, len(raw))
+		for name, value := range raw {
+			s.Parameters[name] = parameterString(value)
+		}
+	}
+	for _, constraint := range strategy.Constraints {
+		s.Constraints = append(s.Constraints, Constraint{
+			ContextName:     constraint.ContextName,
+			Operator:        string(constraint.Operator),
+			Values:          constraint.Values,
+			Value:           constraint.Value,
+			CaseInsensitive: constraint.CaseInsensitive,
+			Inverted:        constraint.Inverted,
+		})
+	}
+	return s, nil
+}
+
+// parameterString encodes a parameter value the way Unleash stores it.
+func parameterString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+func (c Constraint) apiConstraint() api.Constraint {
+	return api.Constraint{
+		ContextName:     c.ContextName,
+		Operator:        api.Operator(c.Operator),
+		Values:          c.Values,
+		Value:           c.Value,
+		CaseInsensitive: c.CaseInsensitive,
+		Inverted:        c.Inverted,
+	}
+}
+
+func apiVariants(variants []Variant) []api.Variant {
+	if variants == nil {
+		return nil
+	}
+	converted := make([]api.Variant, len(variants))
+	for i, v := range variants {
+		converted[i] = api.Variant{
+			Name:       v.Name,
+			Weight:     v.Weight,
+			WeightType: v.WeightType,
+			Stickiness: v.Stickiness,
+		}
+		if v.Payload != nil {
+			converted[i].Payload = &api.VariantPayload{Type: v.Payload.Type, Value: v.Payload.Value}
+		}
+		for _, override := range v.Overrides {
+			converted[i].Overrides = append(converted[i].Overrides, api.VariantOverride{ContextName: override.ContextName, Values: override.Values})
+		}
+	}
+	return converted
+}
+
+func fileVariants(variants []api.Variant) []Variant {
+	if variants == nil {
+		return nil
+	}
+	converted := make([]Variant, len(variants))
+	for i, v := range variants {
+		converted[i] = Variant{
+			Name:       v.Name,
+			Weight:     v.Weight,
+			WeightType: v.WeightType,
+			Stickiness: v.Stickiness,
+		}
+		if v.Payload != nil {
+			converted[i].Payload = &Payload{Type: v.Payload.Type, Value: v.Payload.Value}
+		}
+		for _, override := range v.Overrides {
+			converted[i].Overrides = append(converted[i].Overrides, Override{ContextName: override.ContextName, Values: override.Values})
+		}
+	}
+	return converted
+}
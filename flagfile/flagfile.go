@@ -0,0 +1,260 @@
+// Package flagfile reads and writes flag files: versioned YAML or JSON
+// documents declaring projects, features, strategies, variants and tags.
+// Files are validated offline, with errors pointing at the offending line,
+// and convert to and from the api types and reconcile.DesiredState.
+package flagfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidFile is matched by the errors of Load and Validate.
+var ErrInvalidFile = errors.New("invalid flag file")
+
+// Format is the encoding of a flag file.
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+)
+
+// FormatOf returns the format matching the extension of path: .yaml, .yml or
+// .json.
+func FormatOf(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".json":
+		return FormatJSON, nil
+	}
+	return "", fmt.Errorf("cannot tell the format of %s from its extension", path)
+}
+
+// Options configures loading and validation.
+type Options struct {
+	// CustomStrategies are strategy names accepted besides the built-in ones.
+	CustomStrategies []string
+	// CustomStickiness are variant stickiness values accepted besides the
+	// built-in ones.
+	CustomStickiness []string
+}
+
+// ValidationError is a single problem in a flag file.
+type ValidationError struct {
+	// Line is the line of the problem, or 0 when it is not known.
+	Line int
+	// Path locates the value in the document, for example
+	// projects[0].features[2].name.
+	Path    string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	msg := e.Message
+	if e.Path != "" {
+		msg = e.Path + ": " + msg
+	}
+	if e.Line > 0 {
+		msg = fmt.Sprintf("line %d: %s", e.Line, msg)
+	}
+	return msg
+}
+
+// ValidationErrors reports every problem found in a flag file. It matches
+// ErrInvalidFile with errors.Is.
+type ValidationErrors struct {
+	Errors []ValidationError
+}
+
+func (e *ValidationErrors) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%v: %s", ErrInvalidFile, strings.Join(messages, "; "))
+}
+
+func (e *ValidationErrors) Is(target error) bool {
+	return target == ErrInvalidFile
+}
+
+func (e *ValidationErrors) add(line int, path string, format string, args ...interface{}) {
+	e.Errors = append(e.Errors, ValidationError{Line: line, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (e *ValidationErrors) orNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// LoadFile loads and validates the flag file at path, choosing the format
+// from its extension.
+func LoadFile(path string, opts Options) (*File, error) {
+	format, err := FormatOf(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f, format, opts)
+}
+
+// Load reads and validates a flag file. Syntax errors, unsupported schema
+// versions and invalid declarations are reported as *ValidationErrors.
+func Load(r io.Reader, format Format, opts Options) (*File, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	errs := &ValidationErrors{}
+
+	switch format {
+	case FormatJSON:
+		// the YAML parser below accepts JSON, but also YAML in a JSON file
+		var syntax *json.SyntaxError
+		if err := json.Unmarshal(data, new(interface{})); errors.As(err, &syntax) {
+			errs.add(lineAt(data, syntax.Offset), "", "%v", err)
+			return nil, errs
+		} else if err != nil {
+			return nil, err
+		}
+	case FormatYAML:
+	default:
+		return nil, fmt.Errorf("unknown flag file format %q", format)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		addYAMLError(errs, err)
+		return nil, errs
+	}
+	if len(doc.Content) == 0 {
+		errs.add(1, "", "the file is empty")
+		return nil, errs
+	}
+	root := doc.Content[0]
+	if !checkVersion(root, errs) {
+		return nil, errs
+	}
+
+	var file File
+	if err := root.Decode(&file); err != nil {
+		addYAMLError(errs, err)
+		return nil, errs
+	}
+	if err := file.Validate(opts); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// checkVersion makes sure the document declares a schema version this
+// package reads before it is decoded.
+func checkVersion(root *yaml.Node, errs *ValidationErrors) bool {
+	if root.Kind != yaml.MappingNode {
+		errs.add(root.Line, "", "the file must be a mapping with version and projects")
+		return false
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "version" {
+			continue
+		}
+		value := root.Content[i+1]
+		version, err := strconv.Atoi(value.Value)
+		if err != nil || value.Kind != yaml.ScalarNode {
+			errs.add(value.Line, "version", "version must be a number, got %q", value.Value)
+			return false
+		}
+		if version < 1 || version > SchemaVersion {
+			errs.add(value.Line, "version", "unsupported schema version %d, this library reads version %d", version, SchemaVersion)
+			return false
+		}
+		return true
+	}
+	errs.add(root.Line, "version", "version is required")
+	return false
+}
+
+var yamlLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// addYAMLError turns the errors of the YAML parser and decoder, which carry
+// their line in the message, into validation errors.
+func addYAMLError(errs *ValidationErrors, err error) {
+	messages := []string{err.Error()}
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		messages = typeErr.Errors
+	}
+	for _, message := range messages {
+		if m := yamlLine.FindStringSubmatch(message); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			errs.add(line, "", "%s", m[2])
+		} else {
+			errs.add(0, "", "%s", strings.TrimPrefix(message, "yaml: "))
+		}
+	}
+}
+
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+// WriteFile writes f to path, choosing the format from its extension.
+func WriteFile(path string, f *File) error {
+	format, err := FormatOf(path)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, f, format); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// Write encodes f in format. Files without a version are written with the
+// current SchemaVersion.
+func Write(w io.Writer, f *File, format Format) error {
+	out := *f
+	if out.Version == 0 {
+		out.Version = SchemaVersion
+	}
+	switch format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(data, '\n'))
+		return err
+	case FormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	return fmt.Errorf("unknown flag file format %q", format)
+}
@@ -0,0 +1,359 @@
+package flagfile_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sighphyre/go-unleash-api/api"
+	"github.com/sighphyre/go-unleash-api/flagfile"
+	"github.com/sighphyre/go-unleash-api/reconcile"
+	"github.com/sighphyre/go-unleash-api/unleashtest"
+)
+
+const checkoutYAML = `version: 1
+projects:
+  - id: payments
+    name: Payments
+    environments: [production]
+    features:
+      - name: checkout
+        type: experiment
+        description: New checkout
+        tags:
+          - type: simple
+            value: team-payments
+        variants:
+          - name: control
+            weight: 200
+            weightType: fix
+          - name: treatment
+        environments:
+          - name: production
+            enabled: true
+            strategies:
+              - name: flexibleRollout
+                parameters:
+                  rollout: 25
+                  stickiness: default
+                  groupId: checkout
+                constraints:
+                  - contextName: appName
+                    operator: IN
+                    values: [web]
+              - name: default
+            variants:
+              - name: blue
+              - name: green
+`
+
+func checkoutToggle() api.FeatureToggle {
+	rollout := api.NewFeatureStrategy(api.FlexibleRolloutParams{Rollout: 25, Stickiness: "default", GroupId: "checkout"})
+	rollout.Constraints = []api.Constraint{{ContextName: "appName", Operator: api.OperatorIn, Values: []string{"web"}}}
+	return api.FeatureToggle{
+		Name:        "checkout",
+		Type:        "experiment",
+		Description: "New checkout",
+		Environments: []api.Environment{{
+			Name:       "production",
+			Enabled:    true,
+			Strategies: []api.FeatureStrategy{rollout, {Name: "default", SortOrder: 1}},
+			Variants:   []api.Variant{{Name: "blue"}, {Name: "green"}},
+		}},
+		Variants: []api.Variant{{Name: "control", Weight: 200, WeightType: api.WeightTypeFix}, {Name: "treatment"}},
+		Tags:     []api.FeatureTag{{Type: "simple", Value: "team-payments"}},
+	}
+}
+
+// sameJSON compares api values by their encoding, since parameters decoded
+// from a file are plain maps rather than the typed parameter structs.
+func sameJSON(t *testing.T, got interface{}, want interface{}) {
+	t.Helper()
+	g, _ := json.Marshal(got)
+	w, _ := json.Marshal(want)
+	if !bytes.Equal(g, w) {
+		t.Errorf("got  %s\nwant %s", g, w)
+	}
+}
+
+func TestLoad_YAML(t *testing.T) {
+	file, err := flagfile.Load(strings.NewReader(checkoutYAML), flagfile.FormatYAML, flagfile.Options{})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	state := file.DesiredState()
+	if len(state.Projects) != 1 || state.Projects[0].Id != "payments" || state.Projects[0].Name != "Payments" {
+		t.Fatalf("projects = %+v", state.Projects)
+	}
+	want := checkoutToggle()
+	want.Project = "payments"
+	sameJSON(t, state.Projects[0].Features[0], want)
+}
+
+func TestWriteLoad_RoundTrip(t *testing.T) {
+	want := reconcile.DesiredState{Projects: []reconcile.ProjectState{{
+		Project:      api.Project{Id: "payments", Name: "Payments", Description: "Payment flows"},
+		Environments: []string{"development", "production"},
+		Features:     []api.FeatureToggle{checkoutToggle()},
+	}}}
+	want.Projects[0].Features[0].Project = "payments"
+	file, err := flagfile.FromDesiredState(want)
+	if err != nil {
+		t.Fatalf("FromDesiredState() error = %v", err)
+	}
+
+	for _, format := range []flagfile.Format{flagfile.FormatYAML, flagfile.FormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := flagfile.Write(&buf, file, format); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			loaded, err := flagfile.Load(&buf, format, flagfile.Options{})
+			if err != nil {
+				t.Fatalf("Load() error = %v\n%s", err, buf.String())
+			}
+			if loaded.Version != flagfile.SchemaVersion {
+				t.Errorf("Version = %d, want %d", loaded.Version, flagfile.SchemaVersion)
+			}
+			sameJSON(t, loaded.DesiredState(), want)
+		})
+	}
+}
+
+func TestWriteFile_LoadFile(t *testing.T) {
+	file, err := flagfile.Load(strings.NewReader(checkoutYAML), flagfile.FormatYAML, flagfile.Options{})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	for _, name := range []string{"flags.json", "flags.yml"} {
+		path := filepath.Join(t.TempDir(), name)
+		if err := flagfile.WriteFile(path, file); err != nil {
+			t.Fatalf("WriteFile(%s) error = %v", name, err)
+		}
+		loaded, err := flagfile.LoadFile(path, flagfile.Options{})
+		if err != nil {
+			t.Fatalf("LoadFile(%s) error = %v", name, err)
+		}
+		sameJSON(t, loaded.DesiredState(), file.DesiredState())
+	}
+	if err := flagfile.WriteFile(filepath.Join(t.TempDir(), "flags.toml"), file); err == nil {
+		t.Error("WriteFile(flags.toml) error = nil, want an unknown format error")
+	}
+}
+
+func TestLoad_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format flagfile.Format
+		input  string
+		want   []string
+	}{
+		{
+			name:   "unknown strategy",
+			format: flagfile.FormatYAML,
+			input: `version: 1
+projects:
+  - id: default
+    features:
+      - name: search
+        environments:
+          - name: production
+            strategies:
+              - name: gradualRollout
+`,
+			want: []string{`line 9: projects[0].features[0].environments[0].strategies[0].name: unknown strategy "gradualRollout"`},
+		},
+		{
+			name:   "bad constraint operator",
+			format: flagfile.FormatYAML,
+			input: `version: 1
+projects:
+  - id: default
+    features:
+      - name: search
+        environments:
+          - name: production
+            strategies:
+              - name: default
+                constraints:
+                  - contextName: appName
+                    operator: EQUALS
+                    values: [web]
+`,
+			want: []string{`line 12: projects[0].features[0].environments[0].strategies[0].constraints[0]: unknown operator "EQUALS" on appName`},
+		},
+		{
+			name:   "weight overflow",
+			format: flagfile.FormatYAML,
+			input: `version: 1
+projects:
+  - id: default
+    features:
+      - name: search
+        variants:
+          - name: a
+            weight: 700
+            weightType: fix
+          - name: b
+            weight: 1200
+            weightType: fix
+`,
+			want: []string{
+				`line 10: projects[0].features[0].variants[1]: fixed weight must be between 0 and 1000, got 1200`,
+				`line 6: projects[0].features[0].variants: fixed weights add up to 1900, more than 1000`,
+			},
+		},
+		{
+			name:   "invalid parameters and unknown field",
+			format: flagfile.FormatJSON,
+			input: `{
+	"version": 1,
+	"projects": [{
+		"id": "default",
+		"features": [{
+			"name": "search",
+			"owner": "search-team",
+			"environments": [{
+				"name": "production",
+				"strategies": [{"name": "flexibleRollout", "parameters": {"rollout": "150"}}]
+			}]
+		}]
+	}]
+}`,
+			want: []string{
+				`line 7: projects[0].features[0].owner: unknown field "owner"`,
+				`line 10: projects[0].features[0].environments[0].strategies[0].parameters: flexibleRollout rollout must be between 0 and 100, got 150`,
+			},
+		},
+		{
+			name:   "duplicates",
+			format: flagfile.FormatYAML,
+			input: `version: 1
+projects:
+  - id: default
+    features:
+      - name: search
+  - id: default
+    features:
+      - name: search
+`,
+			want: []string{
+				`line 6: projects[1].id: project default is declared twice`,
+				`line 8: projects[1].features[0].name: feature search is already declared in project default`,
+			},
+		},
+		{
+			name:   "unsupported version",
+			format: flagfile.FormatYAML,
+			input:  "version: 2\nprojects: []\n",
+			want:   []string{`line 1: version: unsupported schema version 2, this library reads version 1`},
+		},
+		{
+			name:   "missing version",
+			format: flagfile.FormatYAML,
+			input:  "projects: []\n",
+			want:   []string{`line 1: version: version is required`},
+		},
+		{
+			name:   "wrong type",
+			format: flagfile.FormatYAML,
+			input:  "version: 1\nprojects:\n  - id: default\n    features: search\n",
+			want:   []string{"line 4: cannot unmarshal !!str `search` into []flagfile.Feature"},
+		},
+		{
+			name:   "JSON syntax",
+			format: flagfile.FormatJSON,
+			input:  "{\n  \"version\": 1,\n  \"projects\": [,]\n}",
+			want:   []string{"line 3: invalid character ',' looking for beginning of value"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := flagfile.Load(strings.NewReader(tt.input), tt.format, flagfile.Options{})
+			if !errors.Is(err, flagfile.ErrInvalidFile) {
+				t.Fatalf("Load() error = %v, want ErrInvalidFile", err)
+			}
+			var verr *flagfile.ValidationErrors
+			if !errors.As(err, &verr) {
+				t.Fatalf("Load() error = %T, want *ValidationErrors", err)
+			}
+			var got []string
+			for _, e := range verr.Errors {
+				got = append(got, e.Error())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("errors =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestLoad_CustomStrategiesAndStickiness(t *testing.T) {
+	input := `version: 1
+projects:
+  - id: default
+    features:
+      - name: search
+        variants:
+          - name: a
+            stickiness: tenantId
+        environments:
+          - name: production
+            strategies:
+              - name: byTenant
+                parameters:
+                  tenants: acme
+`
+	if _, err := flagfile.Load(strings.NewReader(input), flagfile.FormatYAML, flagfile.Options{}); err == nil {
+		t.Fatal("Load() error = nil, want unknown strategy and stickiness")
+	}
+	opts := flagfile.Options{CustomStrategies: []string{"byTenant"}, CustomStickiness: []string{"tenantId"}}
+	if _, err := flagfile.Load(strings.NewReader(input), flagfile.FormatYAML, opts); err != nil {
+		t.Errorf("Load() with custom strategies error = %v", err)
+	}
+}
+
+func TestFile_DesiredStatePlansAgainstServer(t *testing.T) {
+	srv := unleashtest.NewServer()
+	defer srv.Close()
+	client, err := srv.NewClient()
+	if err != nil {
+		t.Fatalf("Server.NewClient() error = %v", err)
+	}
+	file, err := flagfile.Load(strings.NewReader(checkoutYAML), flagfile.FormatYAML, flagfile.Options{})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	ctx := context.Background()
+	p, err := reconcile.NewPlan(ctx, client, file.DesiredState(), reconcile.Options{})
+	if err != nil {
+		t.Fatalf("NewPlan() error = %v", err)
+	}
+	if _, err := p.Apply(ctx); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	feature, ok := srv.Feature("checkout")
+	if !ok {
+		t.Fatal("feature checkout was not created")
+	}
+	exported, err := flagfile.FromFeatureToggle(feature)
+	if err != nil {
+		t.Fatalf("FromFeatureToggle() error = %v", err)
+	}
+	if got := exported.Environments; len(got) < 1 || got[len(got)-1].Name != "production" || len(got[len(got)-1].Strategies) != 2 {
+		t.Errorf("exported environments = %+v", got)
+	}
+
+	again, err := reconcile.NewPlan(ctx, client, file.DesiredState(), reconcile.Options{})
+	if err != nil {
+		t.Fatalf("NewPlan() error = %v", err)
+	}
+	if !again.Empty() {
+		t.Errorf("plan after apply =\n%s", again)
+	}
+}
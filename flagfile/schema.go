@@ -0,0 +1,208 @@
+package flagfile
+
+import (
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion is the version of the file schema this package reads and
+// writes.
+const SchemaVersion = 1
+
+// File is a flag file: the projects and features it declares.
+type File struct {
+	Version  int       `json:"version" yaml:"version"`
+	Projects []Project `json:"projects" yaml:"projects"`
+
+	pos position
+}
+
+// Project declares a project and its features.
+type Project struct {
+	Id          string `json:"id" yaml:"id"`
+	Name        string `json:"name,omitempty" yaml:"name,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Environments are the environments enabled in the project. They are
+	// left as they are when omitted.
+	Environments []string  `json:"environments,omitempty" yaml:"environments,omitempty"`
+	Features     []Feature `json:"features,omitempty" yaml:"features,omitempty"`
+
+	pos position
+}
+
+// Feature declares a feature toggle.
+type Feature struct {
+	Name string `json:"name" yaml:"name"`
+	// Type defaults to release.
+	Type         string        `json:"type,omitempty" yaml:"type,omitempty"`
+	Description  string        `json:"description,omitempty" yaml:"description,omitempty"`
+	Stale        bool          `json:"stale,omitempty" yaml:"stale,omitempty"`
+	Tags         []Tag         `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Variants     []Variant     `json:"variants,omitempty" yaml:"variants,omitempty"`
+	Environments []Environment `json:"environments,omitempty" yaml:"environments,omitempty"`
+
+	pos position
+}
+
+// Environment declares the configuration of a feature in an environment.
+type Environment struct {
+	Name    string `json:"name" yaml:"name"`
+	Enabled bool   `json:"enabled" yaml:"enabled"`
+	// Strategies are listed in evaluation order.
+	Strategies []Strategy `json:"strategies,omitempty" yaml:"strategies,omitempty"`
+	Variants   []Variant  `json:"variants,omitempty" yaml:"variants,omitempty"`
+
+	pos position
+}
+
+// Strategy declares an activation strategy.
+type Strategy struct {
+	Name        string            `json:"name" yaml:"name"`
+	Parameters  map[string]string `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Constraints []Constraint      `json:"constraints,omitempty" yaml:"constraints,omitempty"`
+	Segments    []int             `json:"segments,omitempty" yaml:"segments,omitempty"`
+	Variants    []Variant         `json:"variants,omitempty" yaml:"variants,omitempty"`
+
+	pos position
+}
+
+// Constraint declares a strategy constraint.
+type Constraint struct {
+	ContextName     string   `json:"contextName" yaml:"contextName"`
+	Operator        string   `json:"operator" yaml:"operator"`
+	Values          []string `json:"values,omitempty" yaml:"values,omitempty"`
+	Value           string   `json:"value,omitempty" yaml:"value,omitempty"`
+	CaseInsensitive bool     `json:"caseInsensitive,omitempty" yaml:"caseInsensitive,omitempty"`
+	Inverted        bool     `json:"inverted,omitempty" yaml:"inverted,omitempty"`
+
+	pos position
+}
+
+// Variant declares a variant of a feature, environment or strategy.
+type Variant struct {
+	Name       string     `json:"name" yaml:"name"`
+	Weight     int        `json:"weight,omitempty" yaml:"weight,omitempty"`
+	WeightType string     `json:"weightType,omitempty" yaml:"weightType,omitempty"`
+	Stickiness string     `json:"stickiness,omitempty" yaml:"stickiness,omitempty"`
+	Payload    *Payload   `json:"payload,omitempty" yaml:"payload,omitempty"`
+	Overrides  []Override `json:"overrides,omitempty" yaml:"overrides,omitempty"`
+
+	pos position
+}
+
+// Payload is the payload of a variant.
+type Payload struct {
+	Type  string `json:"type" yaml:"type"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// Override assigns a variant to contexts whose field matches one of Values.
+type Override struct {
+	ContextName string   `json:"contextName" yaml:"contextName"`
+	Values      []string `json:"values" yaml:"values"`
+}
+
+// Tag is a feature tag.
+type Tag struct {
+	Type  string `json:"type" yaml:"type"`
+	Value string `json:"value" yaml:"value"`
+
+	pos position
+}
+
+// position records where a value was declared in a loaded file, so
+// validation errors can point at it. It is empty for values built in code.
+type position struct {
+	line    int
+	fields  map[string]int
+	unknown []unknownField
+}
+
+type unknownField struct {
+	name string
+	line int
+}
+
+// at returns the line of field, or of the value itself when the field is not
+// present.
+func (p position) at(field string) int {
+	if line, ok := p.fields[field]; ok {
+		return line
+	}
+	return p.line
+}
+
+func (f *File) UnmarshalYAML(node *yaml.Node) error {
+	type plain File
+	return decodeMapping(node, (*plain)(f), &f.pos)
+}
+
+func (p *Project) UnmarshalYAML(node *yaml.Node) error {
+	type plain Project
+	return decodeMapping(node, (*plain)(p), &p.pos)
+}
+
+func (f *Feature) UnmarshalYAML(node *yaml.Node) error {
+	type plain Feature
+	return decodeMapping(node, (*plain)(f), &f.pos)
+}
+
+func (e *Environment) UnmarshalYAML(node *yaml.Node) error {
+	type plain Environment
+	return decodeMapping(node, (*plain)(e), &e.pos)
+}
+
+func (s *Strategy) UnmarshalYAML(node *yaml.Node) error {
+	type plain Strategy
+	return decodeMapping(node, (*plain)(s), &s.pos)
+}
+
+func (c *Constraint) UnmarshalYAML(node *yaml.Node) error {
+	type plain Constraint
+	return decodeMapping(node, (*plain)(c), &c.pos)
+}
+
+func (v *Variant) UnmarshalYAML(node *yaml.Node) error {
+	type plain Variant
+	return decodeMapping(node, (*plain)(v), &v.pos)
+}
+
+func (t *Tag) UnmarshalYAML(node *yaml.Node) error {
+	type plain Tag
+	return decodeMapping(node, (*plain)(t), &t.pos)
+}
+
+// decodeMapping decodes node into v, a pointer to a struct, recording the
+// lines of the value and its fields and the fields v does not know.
+func decodeMapping(node *yaml.Node, v interface{}, pos *position) error {
+	if err := node.Decode(v); err != nil {
+		return err
+	}
+	pos.line = node.Line
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	known := yamlFields(reflect.TypeOf(v).Elem())
+	pos.fields = make(map[string]int, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		pos.fields[key.Value] = key.Line
+		if !known[key.Value] {
+			pos.unknown = append(pos.unknown, unknownField{name: key.Value, line: key.Line})
+		}
+	}
+	return nil
+}
+
+func yamlFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("yaml")
+		if name := strings.Split(tag, ",")[0]; name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+}
@@ -0,0 +1,195 @@
+package flagfile
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sighphyre/go-unleash-api/api"
+)
+
+// featureTypes are the feature types every Unleash instance has.
+var featureTypes = map[string]bool{
+	"release":     true,
+	"experiment":  true,
+	"operational": true,
+	"kill-switch": true,
+	"permission":  true,
+}
+
+// Validate checks the file without contacting Unleash: the schema version,
+// unknown fields, missing and duplicate names, feature types, strategy names
+// and parameters, constraints and variants. Every problem is reported in a
+// *ValidationErrors, with lines when the file was loaded.
+func (f *File) Validate(opts Options) error {
+	v := &validator{opts: opts, errs: &ValidationErrors{}}
+	v.unknown(f.pos, "")
+	if f.Version != SchemaVersion {
+		v.errs.add(f.pos.at("version"), "version", "unsupported schema version %d, this library reads version %d", f.Version, SchemaVersion)
+	}
+
+	projects := make(map[string]bool)
+	features := make(map[string]string)
+	for i, project := range f.Projects {
+		path := fmt.Sprintf("projects[%d]", i)
+		v.unknown(project.pos, path)
+		if project.Id == "" {
+			v.errs.add(project.pos.line, path+".id", "id is required")
+		} else if projects[project.Id] {
+			v.errs.add(project.pos.at("id"), path+".id", "project %s is declared twice", project.Id)
+		}
+		projects[project.Id] = true
+
+		for j, feature := range project.Features {
+			featurePath := fmt.Sprintf("%s.features[%d]", path, j)
+			if feature.Name != "" {
+				if other, ok := features[feature.Name]; ok {
+					v.errs.add(feature.pos.at("name"), featurePath+".name", "feature %s is already declared in project %s", feature.Name, other)
+				}
+				features[feature.Name] = project.Id
+			}
+			v.feature(feature, featurePath)
+		}
+	}
+	return v.errs.orNil()
+}
+
+type validator struct {
+	opts Options
+	errs *ValidationErrors
+}
+
+func (v *validator) unknown(pos position, path string) {
+	for _, field := range pos.unknown {
+		fieldPath := field.name
+		if path != "" {
+			fieldPath = path + "." + field.name
+		}
+		v.errs.add(field.line, fieldPath, "unknown field %q", field.name)
+	}
+}
+
+func (v *validator) feature(feature Feature, path string) {
+	v.unknown(feature.pos, path)
+	if feature.Name == "" {
+		v.errs.add(feature.pos.line, path+".name", "name is required")
+	}
+	if feature.Type != "" && !featureTypes[feature.Type] {
+		v.errs.add(feature.pos.at("type"), path+".type", "unknown feature type %q", feature.Type)
+	}
+	for i, tag := range feature.Tags {
+		tagPath := fmt.Sprintf("%s.tags[%d]", path, i)
+		v.unknown(tag.pos, tagPath)
+		if tag.Type == "" || tag.Value == "" {
+			v.errs.add(tag.pos.line, tagPath, "tags need a type and a value")
+		}
+	}
+	v.variants(feature.Variants, feature.pos, path)
+
+	environments := make(map[string]bool)
+	for i, env := range feature.Environments {
+		envPath := fmt.Sprintf("%s.environments[%d]", path, i)
+		v.unknown(env.pos, envPath)
+		if env.Name == "" {
+			v.errs.add(env.pos.line, envPath+".name", "name is required")
+		} else if environments[env.Name] {
+			v.errs.add(env.pos.at("name"), envPath+".name", "environment %s is declared twice", env.Name)
+		}
+		environments[env.Name] = true
+		for j, strategy := range env.Strategies {
+			v.strategy(strategy, fmt.Sprintf("%s.strategies[%d]", envPath, j))
+		}
+		v.variants(env.Variants, env.pos, envPath)
+	}
+}
+
+func (v *validator) strategy(strategy Strategy, path string) {
+	v.unknown(strategy.pos, path)
+	switch {
+	case strategy.Name == "":
+		v.errs.add(strategy.pos.line, path+".name", "name is required")
+	case builtinParameters(strategy.Name) != nil:
+		params := builtinParameters(strategy.Name)
+		converted := strategy.featureStrategy(0)
+		err := converted.DecodeParameters(params)
+		if err == nil {
+			err = params.Validate()
+		}
+		if err != nil {
+			v.errs.add(strategy.pos.at("parameters"), path+".parameters", "%v", err)
+		}
+	case !contains(v.opts.CustomStrategies, strategy.Name):
+		v.errs.add(strategy.pos.at("name"), path+".name", "unknown strategy %q", strategy.Name)
+	}
+
+	for i, constraint := range strategy.Constraints {
+		constraintPath := fmt.Sprintf("%s.constraints[%d]", path, i)
+		v.unknown(constraint.pos, constraintPath)
+		c := constraint.apiConstraint()
+		if err := c.Validate(); err != nil {
+			line := constraint.pos.line
+			if c.ContextName != "" && !c.Operator.IsValid() {
+				line = constraint.pos.at("operator")
+			}
+			v.errs.add(line, constraintPath, "%s", strings.TrimPrefix(err.Error(), api.ErrInvalidConstraint.Error()+": "))
+		}
+	}
+	for i, segment := range strategy.Segments {
+		if segment <= 0 {
+			v.errs.add(strategy.pos.at("segments"), fmt.Sprintf("%s.segments[%d]", path, i), "segment ids are positive, got %d", segment)
+		}
+	}
+	v.variants(strategy.Variants, strategy.pos, path)
+}
+
+// variants validates the variants declared by parent, reporting problems with
+// a single variant on its line and problems with the set on the variants key.
+func (v *validator) variants(variants []Variant, parent position, path string) {
+	for i, variant := range variants {
+		v.unknown(variant.pos, fmt.Sprintf("%s.variants[%d]", path, i))
+	}
+	err := api.ValidateVariants(apiVariants(variants), v.opts.CustomStickiness...)
+	var verr *api.VariantsError
+	if !errors.As(err, &verr) {
+		return
+	}
+	for _, e := range verr.Errors {
+		if e.Index < 0 {
+			v.errs.add(parent.at("variants"), path+".variants", "%s", e.Message)
+			continue
+		}
+		v.errs.add(variants[e.Index].pos.line, fmt.Sprintf("%s.variants[%d]", path, e.Index), "%s", e.Message)
+	}
+}
+
+// builtinParameters returns the typed parameters of a built-in strategy, or
+// nil for other strategies. The default strategy takes no parameters.
+func builtinParameters(name string) api.StrategyParameters {
+	switch name {
+	case api.StrategyDefault:
+		return &defaultParams{}
+	case api.StrategyFlexibleRollout:
+		return &api.FlexibleRolloutParams{}
+	case api.StrategyUserWithId:
+		return &api.UserWithIdParams{}
+	case api.StrategyRemoteAddress:
+		return &api.RemoteAddressParams{}
+	case api.StrategyApplicationHostname:
+		return &api.ApplicationHostnameParams{}
+	}
+	return nil
+}
+
+type defaultParams struct{}
+
+func (defaultParams) StrategyName() string { return api.StrategyDefault }
+func (defaultParams) Validate() error      { return nil }
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
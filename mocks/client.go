@@ -0,0 +1,267 @@
+package mocks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// TestingT is the subset of testing.TB used to report unexpected requests.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Responder builds the response for a matched request.
+type Responder func(req *http.Request) (*http.Response, error)
+
+// RecordedRequest is a request received by a Client.
+type RecordedRequest struct {
+	Method string
+	// Path is relative to the API root, e.g. "admin/projects/default".
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+	// JSON holds the decoded body when it is valid JSON, nil otherwise.
+	JSON interface{}
+}
+
+// DecodeBody decodes the recorded body into v.
+func (r RecordedRequest) DecodeBody(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// Client is an api.HTTPClient that dispatches requests to handlers registered
+// by method and path pattern and records every request it receives. Unlike
+// MockClient it keeps no global state, so tests using it can run in parallel.
+type Client struct {
+	t TestingT
+
+	mu       sync.Mutex
+	routes   []*Route
+	requests []RecordedRequest
+}
+
+// NewClient returns a Client reporting unexpected requests to t.
+func NewClient(t TestingT) *Client {
+	return &Client{t: t}
+}
+
+// Route is a handler registered with Client.On.
+type Route struct {
+	client     *Client
+	method     string
+	segments   []string
+	responders []Responder
+	calls      int
+}
+
+// On registers a route for method and pattern. Patterns are relative to the
+// API root; segments starting with ':' or equal to '*' match any value, for
+// example "admin/projects/:project/features".
+func (c *Client) On(method string, pattern string) *Route {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	route := &Route{
+		client:   c,
+		method:   method,
+		segments: splitPath(pattern),
+	}
+	c.routes = append(c.routes, route)
+	return route
+}
+
+// ReplyFunc appends a responder to the route. Responders are used in the order
+// they were added; the last one keeps answering once the others are used up.
+func (r *Route) ReplyFunc(responder Responder) *Route {
+	r.client.mu.Lock()
+	defer r.client.mu.Unlock()
+	r.responders = append(r.responders, responder)
+	return r
+}
+
+// Reply appends a response with the given status code and body.
+func (r *Route) Reply(statusCode int, body string) *Route {
+	return r.ReplyFunc(func(req *http.Request) (*http.Response, error) {
+		return NewResponse(req, statusCode, body), nil
+	})
+}
+
+// ReplyJSON appends a response with the given status code and v encoded as JSON.
+func (r *Route) ReplyJSON(statusCode int, v interface{}) *Route {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("mocks: cannot encode reply: %v", err))
+	}
+	return r.Reply(statusCode, string(body))
+}
+
+// ReplyError appends a transport level error.
+func (r *Route) ReplyError(err error) *Route {
+	return r.ReplyFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, err
+	})
+}
+
+// Do implements api.HTTPClient.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	recorded, err := record(req)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.requests = append(c.requests, recorded)
+	responder := c.match(recorded)
+	c.mu.Unlock()
+
+	if responder == nil {
+		c.t.Helper()
+		c.t.Errorf("mocks: unexpected request %s %s", recorded.Method, recorded.Path)
+		return nil, fmt.Errorf("mocks: unexpected request %s %s", recorded.Method, recorded.Path)
+	}
+	return responder(req)
+}
+
+func (c *Client) match(req RecordedRequest) Responder {
+	segments := splitPath(req.Path)
+	for _, route := range c.routes {
+		if route.method != req.Method || !matchSegments(route.segments, segments) || len(route.responders) == 0 {
+			continue
+		}
+		i := route.calls
+		if i >= len(route.responders) {
+			i = len(route.responders) - 1
+		}
+		route.calls++
+		return route.responders[i]
+	}
+	return nil
+}
+
+// Calls returns how many requests the route has answered.
+func (r *Route) Calls() int {
+	r.client.mu.Lock()
+	defer r.client.mu.Unlock()
+	return r.calls
+}
+
+// Requests returns every request received so far, in order.
+func (c *Client) Requests() []RecordedRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]RecordedRequest(nil), c.requests...)
+}
+
+// RequestsTo returns the requests received so far matching method and pattern.
+func (c *Client) RequestsTo(method string, pattern string) []RecordedRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var matched []RecordedRequest
+	want := splitPath(pattern)
+	for _, req := range c.requests {
+		if req.Method == method && matchSegments(want, splitPath(req.Path)) {
+			matched = append(matched, req)
+		}
+	}
+	return matched
+}
+
+// AssertCalled reports every registered route that never answered a request.
+func (c *Client) AssertCalled() {
+	c.t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, route := range c.routes {
+		if route.calls == 0 {
+			c.t.Errorf("mocks: expected a request %s %s", route.method, strings.Join(route.segments, "/"))
+		}
+	}
+}
+
+// NewResponse builds an *http.Response for req.
+func NewResponse(req *http.Request, statusCode int, body string) *http.Response {
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		StatusCode: statusCode,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+func record(req *http.Request) (RecordedRequest, error) {
+	path, rawQuery := requestPath(req.URL)
+	query, _ := url.ParseQuery(rawQuery)
+
+	recorded := RecordedRequest{
+		Method: req.Method,
+		Path:   path,
+		Query:  query,
+		Header: req.Header.Clone(),
+	}
+	if req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return recorded, err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		recorded.Body = body
+
+		var decoded interface{}
+		if json.Unmarshal(body, &decoded) == nil {
+			recorded.JSON = decoded
+		}
+	}
+	return recorded, nil
+}
+
+// requestPath returns the request path relative to the API root. The api
+// package keeps the path in URL.Opaque, sometimes including the query.
+func requestPath(u *url.URL) (string, string) {
+	path, rawQuery := u.Path, u.RawQuery
+	if u.Opaque != "" {
+		path = u.Opaque
+		if i := strings.Index(path, "?"); i >= 0 {
+			query := path[i+1:]
+			if rawQuery != "" {
+				query += "&" + rawQuery
+			}
+			path, rawQuery = path[:i], query
+		}
+	}
+	path = strings.TrimPrefix(path, "/")
+	if i := strings.Index(path, "api/"); i == 0 || (i > 0 && path[i-1] == '/') {
+		path = path[i+len("api/"):]
+	}
+	return path, rawQuery
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+func matchSegments(pattern []string, segments []string) bool {
+	if len(pattern) != len(segments) {
+		return false
+	}
+	for i, segment := range pattern {
+		if segment == "*" || strings.HasPrefix(segment, ":") {
+			continue
+		}
+		if segment != segments[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+package mocks_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sighphyre/go-unleash-api/api"
+	"github.com/sighphyre/go-unleash-api/mocks"
+)
+
+type fakeT struct {
+	errors []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func newApiClient(t *testing.T, mock *mocks.Client, opts ...api.Option) *api.ApiClient {
+	t.Helper()
+	client, err := api.NewClient("https://unleash.example.com/api", "myToken", append([]api.Option{api.WithHTTPClient(mock)}, opts...)...)
+	if err != nil {
+		t.Fatalf("api.NewClient() error = %v", err)
+	}
+	return client
+}
+
+func TestClient_RoutesAndRecordsRequests(t *testing.T) {
+	t.Parallel()
+	mock := mocks.NewClient(t)
+	mock.On(http.MethodPost, "admin/projects/:project/features").
+		Reply(http.StatusCreated, `{"name":"checkout","project":"default"}`)
+	client := newApiClient(t, mock)
+
+	feature, _, err := client.FeatureToggles.CreateFeature("default", api.FeatureToggle{Name: "checkout", Type: "release"})
+	if err != nil {
+		t.Fatalf("CreateFeature() error = %v", err)
+	}
+	if feature.Name != "checkout" {
+		t.Errorf("CreateFeature() got = %v", feature)
+	}
+
+	requests := mock.RequestsTo(http.MethodPost, "admin/projects/default/features")
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(requests))
+	}
+	body, ok := requests[0].JSON.(map[string]interface{})
+	if !ok || body["name"] != "checkout" || body["type"] != "release" {
+		t.Errorf("recorded body = %v", requests[0].JSON)
+	}
+	if got := requests[0].Header.Get("Authorization"); got != "myToken" {
+		t.Errorf("recorded Authorization header = %q", got)
+	}
+	mock.AssertCalled()
+}
+
+func TestClient_ScriptsResponseSequences(t *testing.T) {
+	t.Parallel()
+	mock := mocks.NewClient(t)
+	route := mock.On(http.MethodGet, "admin/feature-types").
+		Reply(http.StatusServiceUnavailable, "").
+		ReplyError(errors.New("connection reset")).
+		Reply(http.StatusOK, `{"version":1,"types":[{"id":"release","name":"Release"}]}`)
+	client := newApiClient(t, mock, api.WithRetryPolicy(&api.RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond}))
+
+	types, _, err := client.FeatureTypes.GetAllFeatureTypes()
+	if err != nil {
+		t.Fatalf("GetAllFeatureTypes() error = %v", err)
+	}
+	if len(types.Types) != 1 {
+		t.Errorf("GetAllFeatureTypes() got = %v", types)
+	}
+	if route.Calls() != 3 {
+		t.Errorf("route answered %d calls, want 3", route.Calls())
+	}
+}
+
+func TestClient_ReportsUnexpectedRequests(t *testing.T) {
+	t.Parallel()
+	ft := &fakeT{}
+	mock := mocks.NewClient(ft)
+	mock.On(http.MethodGet, "admin/projects/:project")
+	client := newApiClient(t, mock)
+
+	if _, err := client.Projects.DeleteProject("default"); err == nil {
+		t.Error("DeleteProject() expected an error")
+	}
+	if len(ft.errors) != 1 {
+		t.Errorf("expected 1 reported error, got %v", ft.errors)
+	}
+
+	mock.AssertCalled()
+	if len(ft.errors) != 2 {
+		t.Errorf("expected the unused route to be reported, got %v", ft.errors)
+	}
+}
+
+func TestClient_RecordsQuery(t *testing.T) {
+	t.Parallel()
+	mock := mocks.NewClient(t)
+	mock.On(http.MethodGet, "admin/user-admin/search").Reply(http.StatusOK, `[]`)
+	client := newApiClient(t, mock)
+
+	if _, _, err := client.Users.SearchUser("jane"); err != nil {
+		t.Fatalf("SearchUser() error = %v", err)
+	}
+	if got := mock.Requests()[0].Query.Get("q"); got != "jane" {
+		t.Errorf("recorded query q = %q, want %q", got, "jane")
+	}
+}
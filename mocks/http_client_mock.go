@@ -4,6 +4,9 @@ import "net/http"
 
 var (
 	// GetDoFunc fetches the mock client's `Do` func
+	//
+	// Deprecated: GetDoFunc is shared by every MockClient, so tests using it
+	// cannot run in parallel. Set MockClient.DoFunc or use NewClient instead.
 	GetDoFunc func(req *http.Request) (*http.Response, error)
 )
 
@@ -12,7 +15,11 @@ type MockClient struct {
 	DoFunc func(req *http.Request) (*http.Response, error)
 }
 
-// Do is the mock client's `Do` func
+// Do is the mock client's `Do` func. It calls DoFunc when set and falls back
+// to the package level GetDoFunc otherwise.
 func (m *MockClient) Do(req *http.Request) (*http.Response, error) {
+	if m.DoFunc != nil {
+		return m.DoFunc(req)
+	}
 	return GetDoFunc(req)
 }
@@ -0,0 +1,494 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sighphyre/go-unleash-api/api"
+)
+
+// Kinds of Action.
+const (
+	Create = "create"
+	Update = "update"
+	Delete = "delete"
+)
+
+// Resources an Action changes.
+const (
+	ResourceProject             = "project"
+	ResourceProjectEnvironment  = "project environment"
+	ResourceFeature             = "feature"
+	ResourceStrategy            = "strategy"
+	ResourceFeatureVariants     = "feature variants"
+	ResourceEnvironmentVariants = "environment variants"
+	ResourceTag                 = "tag"
+	ResourceEnvironmentState    = "environment state"
+)
+
+// Phases order actions so that everything an action depends on exists when
+// it runs. Features are enabled only once their strategies are in place, and
+// pruning happens last.
+const (
+	phaseProjects = iota
+	phaseProjectEnvironments
+	phaseFeatures
+	phaseStrategies
+	phaseStrategyDeletes
+	phaseVariants
+	phaseTags
+	phaseEnvironmentStates
+	phasePruneFeatures
+	phasePruneProjectEnvironments
+)
+
+// Options configures Plan.
+type Options struct {
+	// Prune archives the features of declared projects that the state does
+	// not declare and removes undeclared environments from projects listing
+	// their environments. Undeclared projects are never touched.
+	Prune bool
+}
+
+// Action is a single change of a plan.
+type Action struct {
+	Kind     string
+	Resource string
+	Project  string
+	// Feature and Environment are empty for actions on projects.
+	Feature     string
+	Environment string
+	// Name identifies strategies and tags, for example flexibleRollout#0 or
+	// simple:team-a.
+	Name string
+	// Changes lists the fields an update changes, as JSON Patch operations.
+	Changes []api.PatchOperation
+
+	phase int
+	apply func(ctx context.Context) error
+}
+
+func (a Action) String() string {
+	path := []string{a.Project}
+	for _, part := range []string{a.Feature, a.Environment, a.Name} {
+		if part != "" {
+			path = append(path, part)
+		}
+	}
+	s := a.Kind + " " + a.Resource + " " + strings.Join(path, "/")
+	if len(a.Changes) > 0 {
+		fields := make([]string, len(a.Changes))
+		for i, change := range a.Changes {
+			fields[i] = change.Path
+		}
+		s += " (" + strings.Join(fields, ", ") + ")"
+	}
+	return s
+}
+
+// Plan is the list of actions turning the live state into the desired one.
+type Plan struct {
+	Actions []Action
+}
+
+// Empty reports whether the live state already matches the desired state.
+func (p *Plan) Empty() bool {
+	return len(p.Actions) == 0
+}
+
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "no changes"
+	}
+	lines := make([]string, len(p.Actions))
+	for i, action := range p.Actions {
+		lines[i] = action.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Apply carries out the actions in order and stops at the first failure,
+// returning how many actions succeeded. Planning again after a failure picks
+// up where Apply stopped.
+func (p *Plan) Apply(ctx context.Context) (int, error) {
+	for i, action := range p.Actions {
+		if err := action.apply(ctx); err != nil {
+			return i, fmt.Errorf("%s: %w", action, err)
+		}
+	}
+	return len(p.Actions), nil
+}
+
+// NewPlan validates desired and compares it against the live state read
+// through client, returning the actions that reconcile them.
+func NewPlan(ctx context.Context, client *api.ApiClient, desired DesiredState, opts Options) (*Plan, error) {
+	if err := desired.Validate(); err != nil {
+		return nil, err
+	}
+	planner := &planner{client: client, opts: opts}
+	for _, project := range desired.Projects {
+		if err := planner.planProject(ctx, project); err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(planner.actions, func(i, j int) bool {
+		return planner.actions[i].phase < planner.actions[j].phase
+	})
+	return &Plan{Actions: planner.actions}, nil
+}
+
+type planner struct {
+	client  *api.ApiClient
+	opts    Options
+	actions []Action
+}
+
+func (p *planner) add(action Action) {
+	p.actions = append(p.actions, action)
+}
+
+func (p *planner) planProject(ctx context.Context, desired ProjectState) error {
+	id := desired.Id
+	projects := p.client.Projects
+	live, _, err := projects.GetProjectByIdWithContext(ctx, id)
+	exists := err == nil
+	if err != nil && !errors.Is(err, api.ErrNotFound) {
+		return fmt.Errorf("read project %s: %w", id, err)
+	}
+
+	project := desired.Project
+	if project.Name == "" {
+		project.Name = id
+	}
+	switch {
+	case !exists:
+		environments, prune := desired.Environments, p.opts.Prune
+		p.add(Action{Kind: Create, Resource: ResourceProject, Project: id, phase: phaseProjects,
+			apply: func(ctx context.Context) error {
+				if _, _, err := projects.CreateProjectWithContext(ctx, project); err != nil {
+					return err
+				}
+				if environments == nil {
+					return nil
+				}
+				return syncProjectEnvironments(ctx, projects, id, environments, prune)
+			}})
+	case live.Name != project.Name || live.Description != project.Description:
+		var changes []api.PatchOperation
+		if live.Name != project.Name {
+			changes = append(changes, api.PatchOperation{Op: api.PatchReplace, Path: "/name", Value: project.Name})
+		}
+		if live.Description != project.Description {
+			changes = append(changes, api.PatchOperation{Op: api.PatchReplace, Path: "/description", Value: project.Description})
+		}
+		p.add(Action{Kind: Update, Resource: ResourceProject, Project: id, Changes: changes, phase: phaseProjects,
+			apply: func(ctx context.Context) error {
+				_, _, err := projects.UpdateProjectWithContext(ctx, id, project)
+				return err
+			}})
+	}
+
+	// the environments of new projects are only known once they exist
+	if exists && desired.Environments != nil {
+		p.planProjectEnvironments(id, live, desired.Environments)
+	}
+
+	liveFeatures := make(map[string]bool)
+	if exists {
+		features, _, err := p.client.FeatureToggles.GetFeaturesByProjectWithContext(ctx, id)
+		if err != nil {
+			return fmt.Errorf("read the features of project %s: %w", id, err)
+		}
+		for _, feature := range *features {
+			liveFeatures[feature.Name] = true
+		}
+	}
+
+	declared := make(map[string]bool)
+	for _, feature := range desired.Features {
+		declared[feature.Name] = true
+		if err := p.planFeature(ctx, id, feature, liveFeatures[feature.Name]); err != nil {
+			return err
+		}
+	}
+
+	if p.opts.Prune {
+		var undeclared []string
+		for name := range liveFeatures {
+			if !declared[name] {
+				undeclared = append(undeclared, name)
+			}
+		}
+		sort.Strings(undeclared)
+		toggles := p.client.FeatureToggles
+		for _, name := range undeclared {
+			name := name
+			p.add(Action{Kind: Delete, Resource: ResourceFeature, Project: id, Feature: name, phase: phasePruneFeatures,
+				apply: func(ctx context.Context) error {
+					_, _, err := toggles.ArchiveFeatureWithContext(ctx, id, name)
+					return err
+				}})
+		}
+	}
+	return nil
+}
+
+func (p *planner) planProjectEnvironments(id string, live *api.ProjectDetails, desired []string) {
+	enabled := make(map[string]bool)
+	for _, env := range live.Environments {
+		enabled[env.Environment] = true
+	}
+	wanted := make(map[string]bool)
+	projects := p.client.Projects
+	for _, env := range desired {
+		env := env
+		wanted[env] = true
+		if !enabled[env] {
+			p.add(Action{Kind: Create, Resource: ResourceProjectEnvironment, Project: id, Environment: env, phase: phaseProjectEnvironments,
+				apply: func(ctx context.Context) error {
+					_, _, err := projects.AddEnvironmentToProjectWithContext(ctx, id, env)
+					return err
+				}})
+		}
+	}
+	if !p.opts.Prune {
+		return
+	}
+	for _, env := range live.Environments {
+		env := env.Environment
+		if !wanted[env] {
+			p.add(Action{Kind: Delete, Resource: ResourceProjectEnvironment, Project: id, Environment: env, phase: phasePruneProjectEnvironments,
+				apply: func(ctx context.Context) error {
+					_, _, err := projects.RemoveEnvironmentFromProjectWithContext(ctx, id, env)
+					return err
+				}})
+		}
+	}
+}
+
+// syncProjectEnvironments enables the desired environments in a project that
+// was just created and, when pruning, disables the others.
+func syncProjectEnvironments(ctx context.Context, projects *api.ProjectsService, id string, desired []string, prune bool) error {
+	live, _, err := projects.GetProjectByIdWithContext(ctx, id)
+	if err != nil {
+		return err
+	}
+	enabled := make(map[string]bool)
+	for _, env := range live.Environments {
+		enabled[env.Environment] = true
+	}
+	wanted := make(map[string]bool)
+	for _, env := range desired {
+		wanted[env] = true
+		if !enabled[env] {
+			if _, _, err := projects.AddEnvironmentToProjectWithContext(ctx, id, env); err != nil {
+				return err
+			}
+		}
+	}
+	if !prune {
+		return nil
+	}
+	for _, env := range live.Environments {
+		if !wanted[env.Environment] {
+			if _, _, err := projects.RemoveEnvironmentFromProjectWithContext(ctx, id, env.Environment); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *planner) planFeature(ctx context.Context, projectId string, desired api.FeatureToggle, exists bool) error {
+	name := desired.Name
+	toggles := p.client.FeatureToggles
+	if desired.Type == "" {
+		desired.Type = "release"
+	}
+
+	live := &api.FeatureToggle{Name: name}
+	liveTags := []api.FeatureTag{}
+	if exists {
+		var err error
+		if live, _, err = toggles.GetFeatureByNameWithContext(ctx, projectId, name); err != nil {
+			return fmt.Errorf("read feature %s: %w", name, err)
+		}
+		tags, _, err := p.client.FeatureTags.GetAllFeatureTagsWithContext(ctx, name)
+		if err != nil {
+			return fmt.Errorf("read the tags of %s: %w", name, err)
+		}
+		liveTags = tags.Tags
+	}
+
+	if !exists {
+		feature := api.FeatureToggle{Name: name, Type: desired.Type, Description: desired.Description}
+		stale := desired.Stale
+		p.add(Action{Kind: Create, Resource: ResourceFeature, Project: projectId, Feature: name, phase: phaseFeatures,
+			apply: func(ctx context.Context) error {
+				if _, _, err := toggles.CreateFeatureWithContext(ctx, projectId, feature); err != nil {
+					return err
+				}
+				if stale {
+					_, _, err := toggles.MarkFeaturesStaleWithContext(ctx, projectId, []string{name}, true)
+					return err
+				}
+				return nil
+			}})
+	} else if ops := api.DiffFeature(*live, desired); len(ops) > 0 {
+		p.add(Action{Kind: Update, Resource: ResourceFeature, Project: projectId, Feature: name, Changes: ops, phase: phaseFeatures,
+			apply: func(ctx context.Context) error {
+				_, _, err := toggles.PatchFeatureWithContext(ctx, projectId, name, ops)
+				return err
+			}})
+	}
+
+	for _, env := range desired.Environments {
+		liveEnv := api.Environment{Name: env.Name}
+		for _, candidate := range live.Environments {
+			if candidate.Name == env.Name {
+				liveEnv = candidate
+			}
+		}
+		if err := p.planEnvironment(projectId, name, env, liveEnv); err != nil {
+			return err
+		}
+	}
+
+	variants, err := api.NormalizeVariants(desired.Variants, p.client.CustomStickiness()...)
+	if err != nil {
+		return fmt.Errorf("variants of %s: %w", name, err)
+	}
+	if !sameVariants(live.Variants, variants) {
+		p.add(Action{Kind: Update, Resource: ResourceFeatureVariants, Project: projectId, Feature: name, phase: phaseVariants,
+			apply: func(ctx context.Context) error {
+				_, _, err := p.client.Variants.AddVariantsForFeatureToggleWithContext(ctx, projectId, name, variants)
+				return err
+			}})
+	}
+
+	p.planTags(projectId, name, liveTags, desired.Tags)
+	return nil
+}
+
+// planEnvironment pairs the declared and live strategies by position in the
+// evaluation order.
+func (p *planner) planEnvironment(projectId string, feature string, desired api.Environment, live api.Environment) error {
+	toggles := p.client.FeatureToggles
+	environment := desired.Name
+	liveStrategies := api.SortedStrategies(live.Strategies)
+
+	for i, strategy := range desired.Strategies {
+		strategy := strategy
+		strategy.ID = ""
+		strategy.SortOrder = i
+		variants, err := api.NormalizeVariants(strategy.Variants, p.client.CustomStickiness()...)
+		if err != nil {
+			return fmt.Errorf("variants of strategy %d of %s in %s: %w", i, feature, environment, err)
+		}
+		strategy.Variants = variants
+		if len(strategy.Variants) == 0 {
+			strategy.Variants = nil
+		}
+		label := fmt.Sprintf("%s#%d", strategy.Name, i)
+
+		if i >= len(liveStrategies) {
+			p.add(Action{Kind: Create, Resource: ResourceStrategy, Project: projectId, Feature: feature, Environment: environment, Name: label, phase: phaseStrategies,
+				apply: func(ctx context.Context) error {
+					_, _, err := toggles.AddStrategyToFeatureWithContext(ctx, projectId, feature, environment, strategy)
+					return err
+				}})
+			continue
+		}
+		strategy.ID = liveStrategies[i].ID
+		ops, err := api.DiffFeatureStrategy(liveStrategies[i], strategy)
+		if err != nil {
+			return fmt.Errorf("compare strategy %d of %s in %s: %w", i, feature, environment, err)
+		}
+		if len(ops) > 0 {
+			p.add(Action{Kind: Update, Resource: ResourceStrategy, Project: projectId, Feature: feature, Environment: environment, Name: label, Changes: ops, phase: phaseStrategies,
+				apply: func(ctx context.Context) error {
+					_, _, err := toggles.UpdateFeatureStrategyWithContext(ctx, projectId, feature, environment, strategy)
+					return err
+				}})
+		}
+	}
+	for i := len(desired.Strategies); i < len(liveStrategies); i++ {
+		id := liveStrategies[i].ID
+		p.add(Action{Kind: Delete, Resource: ResourceStrategy, Project: projectId, Feature: feature, Environment: environment, Name: fmt.Sprintf("%s#%d", liveStrategies[i].Name, i), phase: phaseStrategyDeletes,
+			apply: func(ctx context.Context) error {
+				_, _, err := toggles.DeleteStrategyFromFeatureWithContext(ctx, projectId, feature, environment, id)
+				return err
+			}})
+	}
+
+	variants, err := api.NormalizeVariants(desired.Variants, p.client.CustomStickiness()...)
+	if err != nil {
+		return fmt.Errorf("variants of %s in %s: %w", feature, environment, err)
+	}
+	if !sameVariants(live.Variants, variants) {
+		p.add(Action{Kind: Update, Resource: ResourceEnvironmentVariants, Project: projectId, Feature: feature, Environment: environment, phase: phaseVariants,
+			apply: func(ctx context.Context) error {
+				_, _, err := p.client.Variants.SetEnvironmentVariantsWithContext(ctx, projectId, feature, environment, variants)
+				return err
+			}})
+	}
+
+	if desired.Enabled != live.Enabled {
+		enabled := desired.Enabled
+		state := "disabled"
+		if enabled {
+			state = "enabled"
+		}
+		p.add(Action{Kind: Update, Resource: ResourceEnvironmentState, Project: projectId, Feature: feature, Environment: environment, Name: state, phase: phaseEnvironmentStates,
+			apply: func(ctx context.Context) error {
+				_, _, err := toggles.EnableFeatureOnEnvironmentWithContext(ctx, projectId, feature, environment, enabled)
+				return err
+			}})
+	}
+	return nil
+}
+
+func (p *planner) planTags(projectId string, feature string, live []api.FeatureTag, desired []api.FeatureTag) {
+	tags := p.client.FeatureTags
+	has := func(tags []api.FeatureTag, tag api.FeatureTag) bool {
+		for _, t := range tags {
+			if t == tag {
+				return true
+			}
+		}
+		return false
+	}
+	for _, tag := range desired {
+		tag := tag
+		if !has(live, tag) {
+			p.add(Action{Kind: Create, Resource: ResourceTag, Project: projectId, Feature: feature, Name: tag.Type + ":" + tag.Value, phase: phaseTags,
+				apply: func(ctx context.Context) error {
+					_, _, err := tags.CreateFeatureTagsWithContext(ctx, feature, tag)
+					return err
+				}})
+		}
+	}
+	for _, tag := range live {
+		tag := tag
+		if !has(desired, tag) {
+			p.add(Action{Kind: Delete, Resource: ResourceTag, Project: projectId, Feature: feature, Name: tag.Type + ":" + tag.Value, phase: phaseTags,
+				apply: func(ctx context.Context) error {
+					_, err := tags.DeleteFeatureTagsWithContext(ctx, feature, tag)
+					return err
+				}})
+		}
+	}
+}
+
+func sameVariants(live []api.Variant, desired []api.Variant) bool {
+	if len(live) == 0 && len(desired) == 0 {
+		return true
+	}
+	a, errA := json.Marshal(live)
+	b, errB := json.Marshal(desired)
+	return errA == nil && errB == nil && string(a) == string(b)
+}
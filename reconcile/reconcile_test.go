@@ -0,0 +1,186 @@
+package reconcile_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sighphyre/go-unleash-api/api"
+	"github.com/sighphyre/go-unleash-api/reconcile"
+	"github.com/sighphyre/go-unleash-api/unleashtest"
+)
+
+func newTestClient(t *testing.T) (*unleashtest.Server, *api.ApiClient) {
+	t.Helper()
+	srv := unleashtest.NewServer()
+	t.Cleanup(srv.Close)
+	client, err := srv.NewClient()
+	if err != nil {
+		t.Fatalf("Server.NewClient() error = %v", err)
+	}
+	return srv, client
+}
+
+func desiredState() reconcile.DesiredState {
+	rollout := api.NewFeatureStrategy(api.FlexibleRolloutParams{Rollout: 25, Stickiness: "default", GroupId: "checkout"})
+	rollout.Constraints = []api.Constraint{{ContextName: "appName", Operator: api.OperatorIn, Values: []string{"web"}}}
+	return reconcile.DesiredState{Projects: []reconcile.ProjectState{{
+		Project:      api.Project{Id: "payments", Name: "Payments"},
+		Environments: []string{"production"},
+		Features: []api.FeatureToggle{{
+			Name:        "checkout",
+			Type:        "experiment",
+			Description: "New checkout",
+			Environments: []api.Environment{{
+				Name:       "production",
+				Enabled:    true,
+				Strategies: []api.FeatureStrategy{rollout, {Name: "default"}},
+				Variants:   []api.Variant{{Name: "blue"}, {Name: "green"}},
+			}},
+			Variants: []api.Variant{{Name: "control", Weight: 200, WeightType: api.WeightTypeFix}, {Name: "treatment"}},
+			Tags:     []api.FeatureTag{{Type: "simple", Value: "team-payments"}},
+		}},
+	}}}
+}
+
+func plan(t *testing.T, client *api.ApiClient, desired reconcile.DesiredState, opts reconcile.Options) *reconcile.Plan {
+	t.Helper()
+	p, err := reconcile.NewPlan(context.Background(), client, desired, opts)
+	if err != nil {
+		t.Fatalf("NewPlan() error = %v", err)
+	}
+	return p
+}
+
+func apply(t *testing.T, p *reconcile.Plan) {
+	t.Helper()
+	if n, err := p.Apply(context.Background()); err != nil {
+		t.Fatalf("Apply() error after %d actions = %v", n, err)
+	}
+}
+
+func TestPlan_CreatesAndConverges(t *testing.T) {
+	srv, client := newTestClient(t)
+	desired := desiredState()
+
+	p := plan(t, client, desired, reconcile.Options{Prune: true})
+	want := []string{
+		"create project payments",
+		"create feature payments/checkout",
+		"create strategy payments/checkout/production/flexibleRollout#0",
+		"create strategy payments/checkout/production/default#1",
+		"update environment variants payments/checkout/production",
+		"update feature variants payments/checkout",
+		"create tag payments/checkout/simple:team-payments",
+		"update environment state payments/checkout/production/enabled",
+	}
+	if got := strings.Split(p.String(), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("NewPlan() =\n%s\nwant\n%s", p, strings.Join(want, "\n"))
+	}
+	apply(t, p)
+
+	feature, ok := srv.Feature("checkout")
+	if !ok || feature.Project != "payments" || feature.Type != "experiment" {
+		t.Fatalf("checkout after Apply() = %+v", feature)
+	}
+	if production := feature.Environments[1]; !production.Enabled || len(production.Strategies) != 2 || len(production.Variants) != 2 {
+		t.Errorf("production after Apply() = %+v", production)
+	}
+	if feature.Variants[0].Weight != 200 || feature.Variants[1].Weight != 800 {
+		t.Errorf("feature variants after Apply() = %+v", feature.Variants)
+	}
+	details, _, err := client.Projects.GetProjectById("payments")
+	if err != nil || len(details.Environments) != 1 || details.Environments[0].Environment != "production" {
+		t.Errorf("payments environments = %+v, %v, want only production", details, err)
+	}
+
+	if p := plan(t, client, desired, reconcile.Options{Prune: true}); !p.Empty() {
+		t.Errorf("NewPlan() after Apply() =\n%s\nwant no changes", p)
+	}
+}
+
+func TestPlan_UpdatesInPlace(t *testing.T) {
+	_, client := newTestClient(t)
+	desired := desiredState()
+	apply(t, plan(t, client, desired, reconcile.Options{}))
+
+	feature := &desired.Projects[0].Features[0]
+	feature.Description = "Checkout v2"
+	feature.Tags = nil
+	production := &feature.Environments[0]
+	production.Enabled = false
+	production.Strategies = production.Strategies[:1]
+	production.Strategies[0].Parameters = api.FlexibleRolloutParams{Rollout: 75, Stickiness: "default", GroupId: "checkout"}
+
+	p := plan(t, client, desired, reconcile.Options{})
+	want := []string{
+		"update feature payments/checkout (/description)",
+		"update strategy payments/checkout/production/flexibleRollout#0 (/parameters/rollout)",
+		"delete strategy payments/checkout/production/default#1",
+		"delete tag payments/checkout/simple:team-payments",
+		"update environment state payments/checkout/production/disabled",
+	}
+	if p.String() != strings.Join(want, "\n") {
+		t.Fatalf("NewPlan() =\n%s\nwant\n%s", p, strings.Join(want, "\n"))
+	}
+	apply(t, p)
+	if p := plan(t, client, desired, reconcile.Options{}); !p.Empty() {
+		t.Errorf("NewPlan() after Apply() =\n%s\nwant no changes", p)
+	}
+}
+
+func TestPlan_RejectsInvalidVariants(t *testing.T) {
+	_, client := newTestClient(t)
+	desired := desiredState()
+	feature := &desired.Projects[0].Features[0]
+	feature.Variants = []api.Variant{{Name: "blue"}, {Name: "blue"}}
+
+	_, err := reconcile.NewPlan(context.Background(), client, desired, reconcile.Options{})
+	var verr *api.VariantsError
+	if !errors.As(err, &verr) {
+		t.Fatalf("NewPlan() error = %v, want a VariantsError", err)
+	}
+}
+
+func TestPlan_PrunesOnlyWhenAsked(t *testing.T) {
+	srv, client := newTestClient(t)
+	desired := desiredState()
+	apply(t, plan(t, client, desired, reconcile.Options{Prune: true}))
+	if _, _, err := client.FeatureToggles.CreateFeature("payments", api.FeatureToggle{Name: "legacy"}); err != nil {
+		t.Fatalf("CreateFeature() error = %v", err)
+	}
+	if _, _, err := client.Projects.AddEnvironmentToProject("payments", "development"); err != nil {
+		t.Fatalf("AddEnvironmentToProject() error = %v", err)
+	}
+
+	if p := plan(t, client, desired, reconcile.Options{}); !p.Empty() {
+		t.Errorf("NewPlan() without pruning =\n%s\nwant no changes", p)
+	}
+	p := plan(t, client, desired, reconcile.Options{Prune: true})
+	want := "delete feature payments/legacy\ndelete project environment payments/development"
+	if p.String() != want {
+		t.Fatalf("NewPlan() with pruning =\n%s\nwant\n%s", p, want)
+	}
+	apply(t, p)
+	if _, ok := srv.Feature("legacy"); ok {
+		t.Error("legacy was not archived")
+	}
+}
+
+func TestDesiredState_Validate(t *testing.T) {
+	desired := desiredState()
+	desired.Projects = append(desired.Projects, reconcile.ProjectState{
+		Project:  api.Project{Id: "default"},
+		Features: []api.FeatureToggle{{Name: "checkout"}, {Name: "broken", Environments: []api.Environment{{Name: "production", Strategies: []api.FeatureStrategy{{}}}}}},
+	})
+	err := desired.Validate()
+	if err == nil {
+		t.Fatal("Validate() succeeded")
+	}
+	for _, want := range []string{"feature checkout is declared in payments and default", "feature broken, environment production, strategy 0"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() error = %v, want it to mention %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Package reconcile manages Unleash projects and features declaratively. A
+// DesiredState is compared against the live instance to produce a Plan of
+// create, update and delete actions, which Apply then carries out in
+// dependency order. Applying a plan and planning again yields an empty plan.
+package reconcile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sighphyre/go-unleash-api/api"
+)
+
+// DesiredState describes the projects and features an instance should have.
+type DesiredState struct {
+	Projects []ProjectState
+}
+
+// ProjectState is a project and the features it should contain.
+type ProjectState struct {
+	api.Project
+	// Environments are the environments enabled in the project. They are
+	// left as they are when nil.
+	Environments []string
+	// Features are managed entirely: their strategies and variants in the
+	// listed environments, feature variants and tags match the declaration.
+	// Environments a feature does not list are left as they are. The Project
+	// field of the features is ignored.
+	Features []api.FeatureToggle
+}
+
+// Validate checks the state for mistakes that can be found without
+// contacting Unleash: missing or duplicate names and invalid strategies.
+func (s DesiredState) Validate() error {
+	var problems []string
+	projects := make(map[string]bool)
+	features := make(map[string]string)
+	for _, project := range s.Projects {
+		if project.Id == "" {
+			problems = append(problems, "a project has no id")
+			continue
+		}
+		if projects[project.Id] {
+			problems = append(problems, "project "+project.Id+" is declared twice")
+		}
+		projects[project.Id] = true
+
+		for _, feature := range project.Features {
+			if feature.Name == "" {
+				problems = append(problems, "project "+project.Id+" has a feature without a name")
+				continue
+			}
+			if other, ok := features[feature.Name]; ok {
+				problems = append(problems, fmt.Sprintf("feature %s is declared in %s and %s", feature.Name, other, project.Id))
+			}
+			features[feature.Name] = project.Id
+
+			environments := make(map[string]bool)
+			for _, env := range feature.Environments {
+				if environments[env.Name] {
+					problems = append(problems, fmt.Sprintf("feature %s declares environment %s twice", feature.Name, env.Name))
+				}
+				environments[env.Name] = true
+				for i, strategy := range env.Strategies {
+					if err := strategy.Validate(); err != nil {
+						problems = append(problems, fmt.Sprintf("feature %s, environment %s, strategy %d: %v", feature.Name, env.Name, i, err))
+					}
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid desired state: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
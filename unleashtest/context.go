@@ -0,0 +1,76 @@
+package unleashtest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/sighphyre/go-unleash-api/api"
+)
+
+// getContextFields lists the fields by sort order, then name, like Unleash.
+func (s *Server) getContextFields(w http.ResponseWriter, r *http.Request, p params) {
+	fields := make([]api.ContextField, 0, len(s.contexts))
+	for _, field := range s.contexts {
+		fields = append(fields, *field)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		if fields[i].SortOrder != fields[j].SortOrder {
+			return fields[i].SortOrder < fields[j].SortOrder
+		}
+		return fields[i].Name < fields[j].Name
+	})
+	writeJSON(w, http.StatusOK, fields)
+}
+
+func (s *Server) getContextField(w http.ResponseWriter, r *http.Request, p params) {
+	field, ok := s.contexts[p["name"]]
+	if !ok {
+		writeNotFound(w, "Could not find context field with name "+p["name"])
+		return
+	}
+	writeJSON(w, http.StatusOK, field)
+}
+
+func (s *Server) createContextField(w http.ResponseWriter, r *http.Request, p params) {
+	var field api.ContextField
+	if !decodeBody(w, r, &field) {
+		return
+	}
+	if field.Name == "" || !urlFriendly.MatchString(field.Name) {
+		writeValidationError(w, `"name" must be URL friendly`)
+		return
+	}
+	if _, exists := s.contexts[field.Name]; exists {
+		writeError(w, http.StatusConflict, "NameExistsError", "A context field with name "+field.Name+" already exists")
+		return
+	}
+	field.CreatedAt = s.now()
+	s.contexts[field.Name] = &field
+	writeJSON(w, http.StatusCreated, field)
+}
+
+func (s *Server) updateContextField(w http.ResponseWriter, r *http.Request, p params) {
+	existing, ok := s.contexts[p["name"]]
+	if !ok {
+		writeNotFound(w, "Could not find context field with name "+p["name"])
+		return
+	}
+	var field api.ContextField
+	if !decodeBody(w, r, &field) {
+		return
+	}
+	existing.Description = field.Description
+	existing.Stickiness = field.Stickiness
+	existing.SortOrder = field.SortOrder
+	existing.LegalValues = field.LegalValues
+	writeJSON(w, http.StatusOK, nil)
+}
+
+func (s *Server) deleteContextField(w http.ResponseWriter, r *http.Request, p params) {
+	if _, ok := s.contexts[p["name"]]; !ok {
+		writeNotFound(w, "Could not find context field with name "+p["name"])
+		return
+	}
+	delete(s.contexts, p["name"])
+	writeJSON(w, http.StatusOK, nil)
+}
@@ -0,0 +1,708 @@
+package unleashtest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/sighphyre/go-unleash-api/api"
+)
+
+var featureTypeIds = map[string]bool{
+	"release":     true,
+	"experiment":  true,
+	"operational": true,
+	"kill-switch": true,
+	"permission":  true,
+}
+
+// lookupFeature resolves the project and feature named in the path, answering
+// with 404 and returning false when either does not exist.
+func (s *Server) lookupFeature(w http.ResponseWriter, p params) (*api.FeatureToggle, bool) {
+	if _, ok := s.projects[p["project"]]; !ok {
+		writeNotFound(w, "Could not find project with id "+p["project"])
+		return nil, false
+	}
+	feature, ok := s.features[p["feature"]]
+	if !ok || feature.Project != p["project"] {
+		writeNotFound(w, "Could not find feature toggle with name "+p["feature"])
+		return nil, false
+	}
+	return feature, true
+}
+
+// lookupReadableEnvironment resolves the feature environment named in the
+// path for reads, which Unleash serves for archived features too.
+func (s *Server) lookupReadableEnvironment(w http.ResponseWriter, p params) (*api.Environment, bool) {
+	if _, ok := s.projects[p["project"]]; !ok {
+		writeNotFound(w, "Could not find project with id "+p["project"])
+		return nil, false
+	}
+	feature, ok := s.features[p["feature"]]
+	if !ok {
+		feature, ok = s.archived[p["feature"]]
+	}
+	if !ok || feature.Project != p["project"] {
+		writeNotFound(w, "Could not find feature toggle with name "+p["feature"])
+		return nil, false
+	}
+	for i := range feature.Environments {
+		if feature.Environments[i].Name == p["environment"] {
+			return &feature.Environments[i], true
+		}
+	}
+	writeNotFound(w, "Could not find environment "+p["environment"]+" for feature "+feature.Name)
+	return nil, false
+}
+
+// lookupEnvironment resolves the feature environment named in the path.
+func (s *Server) lookupEnvironment(w http.ResponseWriter, p params) (*api.FeatureToggle, *api.Environment, bool) {
+	feature, ok := s.lookupFeature(w, p)
+	if !ok {
+		return nil, nil, false
+	}
+	for i := range feature.Environments {
+		if feature.Environments[i].Name == p["environment"] {
+			return feature, &feature.Environments[i], true
+		}
+	}
+	writeNotFound(w, "Could not find environment "+p["environment"]+" for feature "+feature.Name)
+	return nil, nil, false
+}
+
+func (s *Server) getFeatures(w http.ResponseWriter, r *http.Request, p params) {
+	if _, ok := s.projects[p["project"]]; !ok {
+		writeNotFound(w, "Could not find project with id "+p["project"])
+		return
+	}
+
+	features := []api.FeatureToggle{}
+	for _, name := range sortedKeys(s.features) {
+		if s.features[name].Project == p["project"] {
+			features = append(features, *s.features[name])
+		}
+	}
+	writeJSON(w, http.StatusOK, struct {
+		Version  int                 `json:"version"`
+		Features []api.FeatureToggle `json:"features"`
+	}{2, features})
+}
+
+func (s *Server) createFeature(w http.ResponseWriter, r *http.Request, p params) {
+	if _, ok := s.projects[p["project"]]; !ok {
+		writeNotFound(w, "Could not find project with id "+p["project"])
+		return
+	}
+	var body api.FeatureToggle
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if body.Name == "" || !urlFriendly.MatchString(body.Name) {
+		writeValidationError(w, `"name" must be URL friendly`)
+		return
+	}
+	if body.Type == "" {
+		body.Type = "release"
+	}
+	if !featureTypeIds[body.Type] {
+		writeValidationError(w, `"type" must be one of release, experiment, operational, kill-switch or permission`)
+		return
+	}
+	_, live := s.features[body.Name]
+	_, archived := s.archived[body.Name]
+	if live || archived {
+		writeError(w, http.StatusConflict, "NameExistsError", "Feature "+body.Name+" already exists")
+		return
+	}
+
+	feature := &api.FeatureToggle{
+		Name:         body.Name,
+		Description:  body.Description,
+		Type:         body.Type,
+		Project:      p["project"],
+		CreatedAt:    s.now(),
+		Environments: []api.Environment{},
+		Variants:     []api.Variant{},
+	}
+	for _, env := range s.environments {
+		feature.Environments = append(feature.Environments, api.Environment{
+			Name:       env.Name,
+			Type:       env.Type,
+			Strategies: []api.FeatureStrategy{},
+		})
+	}
+	s.features[feature.Name] = feature
+	writeJSON(w, http.StatusCreated, feature)
+}
+
+func (s *Server) getFeature(w http.ResponseWriter, r *http.Request, p params) {
+	feature, ok := s.lookupFeature(w, p)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, feature)
+}
+
+func (s *Server) cloneFeature(w http.ResponseWriter, r *http.Request, p params) {
+	source, ok := s.lookupFeature(w, p)
+	if !ok {
+		return
+	}
+	var body struct {
+		Name           string `json:"name"`
+		ReplaceGroupId bool   `json:"replaceGroupId"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if body.Name == "" || !urlFriendly.MatchString(body.Name) {
+		writeValidationError(w, `"name" must be URL friendly`)
+		return
+	}
+	_, live := s.features[body.Name]
+	_, archived := s.archived[body.Name]
+	if live || archived {
+		writeError(w, http.StatusConflict, "NameExistsError", "Feature "+body.Name+" already exists")
+		return
+	}
+
+	// like Unleash, the clone copies strategies and variants but starts
+	// disabled in every environment
+	clone := &api.FeatureToggle{}
+	deepCopy(source, clone)
+	clone.Name = body.Name
+	clone.CreatedAt = s.now()
+	clone.LastSeenAt = ""
+	clone.Stale = false
+	for i := range clone.Environments {
+		env := &clone.Environments[i]
+		env.Enabled = false
+		for j := range env.Strategies {
+			strategy := &env.Strategies[j]
+			strategy.ID = s.nextID()
+			if body.ReplaceGroupId && strategy.Name == api.StrategyFlexibleRollout {
+				var params api.FlexibleRolloutParams
+				if err := strategy.DecodeParameters(&params); err == nil {
+					params.GroupId = body.Name
+					strategy.Parameters = params
+				}
+			}
+		}
+	}
+	s.features[clone.Name] = clone
+	writeJSON(w, http.StatusCreated, clone)
+}
+
+func (s *Server) changeProject(w http.ResponseWriter, r *http.Request, p params) {
+	feature, ok := s.lookupFeature(w, p)
+	if !ok {
+		return
+	}
+	var body struct {
+		NewProjectId string `json:"newProjectId"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	target, ok := s.projects[body.NewProjectId]
+	if !ok {
+		writeNotFound(w, "Could not find project with id "+body.NewProjectId)
+		return
+	}
+	// like Unleash, refuse to drop environments the feature is configured in
+	for _, env := range feature.Environments {
+		if indexOf(target.Environments, env.Name) < 0 {
+			writeError(w, http.StatusConflict, "IncompatibleProjectError",
+				"Changing project not allowed. Project "+target.Id+" does not have the environment "+env.Name)
+			return
+		}
+	}
+	feature.Project = target.Id
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) updateFeature(w http.ResponseWriter, r *http.Request, p params) {
+	feature, ok := s.lookupFeature(w, p)
+	if !ok {
+		return
+	}
+	var body api.FeatureToggle
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if body.Name != "" && body.Name != feature.Name {
+		writeValidationError(w, "Feature name in the body does not match the path")
+		return
+	}
+	if body.Type != "" {
+		if !featureTypeIds[body.Type] {
+			writeValidationError(w, `"type" must be one of release, experiment, operational, kill-switch or permission`)
+			return
+		}
+		feature.Type = body.Type
+	}
+	feature.Description = body.Description
+	feature.Stale = body.Stale
+	writeJSON(w, http.StatusOK, feature)
+}
+
+func (s *Server) patchFeature(w http.ResponseWriter, r *http.Request, p params) {
+	feature, ok := s.lookupFeature(w, p)
+	if !ok {
+		return
+	}
+	var patched api.FeatureToggle
+	if !s.applyPatch(w, r, feature, &patched) {
+		return
+	}
+	if !featureTypeIds[patched.Type] {
+		writeValidationError(w, `"type" must be one of release, experiment, operational, kill-switch or permission`)
+		return
+	}
+	// like Unleash, only the descriptive fields can be patched
+	feature.Description = patched.Description
+	feature.Type = patched.Type
+	feature.Stale = patched.Stale
+	writeJSON(w, http.StatusOK, feature)
+}
+
+func (s *Server) markFeaturesStale(w http.ResponseWriter, r *http.Request, p params) {
+	if _, ok := s.projects[p["project"]]; !ok {
+		writeNotFound(w, "Could not find project with id "+p["project"])
+		return
+	}
+	var body struct {
+		Features []string `json:"features"`
+		Stale    bool     `json:"stale"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if len(body.Features) == 0 {
+		writeValidationError(w, `"features" must contain at least one feature`)
+		return
+	}
+	features := make([]*api.FeatureToggle, len(body.Features))
+	for i, name := range body.Features {
+		feature, ok := s.features[name]
+		if !ok || feature.Project != p["project"] {
+			writeNotFound(w, "Could not find feature toggle with name "+name)
+			return
+		}
+		features[i] = feature
+	}
+	for _, feature := range features {
+		feature.Stale = body.Stale
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) archiveFeature(w http.ResponseWriter, r *http.Request, p params) {
+	feature, ok := s.lookupFeature(w, p)
+	if !ok {
+		return
+	}
+	feature.Archived = true
+	feature.ArchivedAt = s.now()
+	delete(s.features, feature.Name)
+	s.archived[feature.Name] = feature
+	writeJSON(w, http.StatusAccepted, nil)
+}
+
+func (s *Server) deleteArchivedFeature(w http.ResponseWriter, r *http.Request, p params) {
+	if _, ok := s.archived[p["feature"]]; !ok {
+		writeNotFound(w, "Could not find archived feature toggle with name "+p["feature"])
+		return
+	}
+	delete(s.archived, p["feature"])
+	delete(s.tags, p["feature"])
+	writeJSON(w, http.StatusAccepted, nil)
+}
+
+func (s *Server) getArchivedFeatures(w http.ResponseWriter, r *http.Request, p params) {
+	if project := p["project"]; project != "" {
+		if _, ok := s.projects[project]; !ok {
+			writeNotFound(w, "Could not find project with id "+project)
+			return
+		}
+	}
+	// like Unleash, the archive lists features without their strategies and
+	// variants
+	features := []api.FeatureToggle{}
+	for _, name := range sortedKeys(s.archived) {
+		if p["project"] == "" || s.archived[name].Project == p["project"] {
+			feature := *s.archived[name]
+			feature.Variants = nil
+			feature.Environments = make([]api.Environment, len(s.archived[name].Environments))
+			for i, env := range s.archived[name].Environments {
+				feature.Environments[i] = api.Environment{Name: env.Name, Type: env.Type, Enabled: env.Enabled}
+			}
+			features = append(features, feature)
+		}
+	}
+	writeJSON(w, http.StatusOK, struct {
+		Version  int                 `json:"version"`
+		Features []api.FeatureToggle `json:"features"`
+	}{2, features})
+}
+
+func (s *Server) reviveFeature(w http.ResponseWriter, r *http.Request, p params) {
+	feature, ok := s.archived[p["feature"]]
+	if !ok {
+		writeNotFound(w, "Could not find archived feature toggle with name "+p["feature"])
+		return
+	}
+	if _, ok := s.projects[feature.Project]; !ok {
+		writeNotFound(w, "Could not find project with id "+feature.Project)
+		return
+	}
+	s.revive(feature)
+	writeJSON(w, http.StatusOK, nil)
+}
+
+func (s *Server) revive(feature *api.FeatureToggle) {
+	feature.Archived = false
+	feature.ArchivedAt = ""
+	delete(s.archived, feature.Name)
+	s.features[feature.Name] = feature
+}
+
+// bulkArchived resolves the archived features of the project listed in the
+// request body, answering with an error and returning false when one of them
+// is not archived in that project.
+func (s *Server) bulkArchived(w http.ResponseWriter, r *http.Request, p params) ([]*api.FeatureToggle, bool) {
+	if _, ok := s.projects[p["project"]]; !ok {
+		writeNotFound(w, "Could not find project with id "+p["project"])
+		return nil, false
+	}
+	var body struct {
+		Features []string `json:"features"`
+	}
+	if !decodeBody(w, r, &body) {
+		return nil, false
+	}
+	if len(body.Features) == 0 {
+		writeValidationError(w, `"features" must contain at least 1 items`)
+		return nil, false
+	}
+	features := make([]*api.FeatureToggle, 0, len(body.Features))
+	for _, name := range body.Features {
+		feature, ok := s.archived[name]
+		if !ok || feature.Project != p["project"] {
+			writeValidationError(w, "Feature "+name+" is not archived in project "+p["project"])
+			return nil, false
+		}
+		features = append(features, feature)
+	}
+	return features, true
+}
+
+func (s *Server) reviveFeatures(w http.ResponseWriter, r *http.Request, p params) {
+	features, ok := s.bulkArchived(w, r, p)
+	if !ok {
+		return
+	}
+	for _, feature := range features {
+		s.revive(feature)
+	}
+	writeJSON(w, http.StatusOK, nil)
+}
+
+func (s *Server) deleteArchivedFeatures(w http.ResponseWriter, r *http.Request, p params) {
+	features, ok := s.bulkArchived(w, r, p)
+	if !ok {
+		return
+	}
+	for _, feature := range features {
+		delete(s.archived, feature.Name)
+		delete(s.tags, feature.Name)
+	}
+	writeJSON(w, http.StatusOK, nil)
+}
+
+func (s *Server) putVariants(w http.ResponseWriter, r *http.Request, p params) {
+	feature, ok := s.lookupFeature(w, p)
+	if !ok {
+		return
+	}
+	var variants []api.Variant
+	if !decodeBody(w, r, &variants) {
+		return
+	}
+	if variants, ok = storedVariants(w, variants); !ok {
+		return
+	}
+	feature.Variants = variants
+	writeJSON(w, http.StatusOK, api.VariantsResponse{Version: 1, Variants: variants})
+}
+
+func (s *Server) getEnvironmentVariants(w http.ResponseWriter, r *http.Request, p params) {
+	env, ok := s.lookupReadableEnvironment(w, p)
+	if !ok {
+		return
+	}
+	variants := env.Variants
+	if variants == nil {
+		variants = []api.Variant{}
+	}
+	writeJSON(w, http.StatusOK, api.VariantsResponse{Version: 1, Variants: variants})
+}
+
+func (s *Server) putEnvironmentVariants(w http.ResponseWriter, r *http.Request, p params) {
+	_, env, ok := s.lookupEnvironment(w, p)
+	if !ok {
+		return
+	}
+	var variants []api.Variant
+	if !decodeBody(w, r, &variants) {
+		return
+	}
+	if variants, ok = storedVariants(w, variants); !ok {
+		return
+	}
+	env.Variants = variants
+	writeJSON(w, http.StatusOK, api.VariantsResponse{Version: 1, Variants: variants})
+}
+
+func (s *Server) putVariantsBatch(w http.ResponseWriter, r *http.Request, p params) {
+	feature, ok := s.lookupFeature(w, p)
+	if !ok {
+		return
+	}
+	var body struct {
+		Variants     []api.Variant `json:"variants"`
+		Environments []string      `json:"environments"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	variants, ok := storedVariants(w, body.Variants)
+	if !ok {
+		return
+	}
+
+	// check every environment before changing any of them
+	targets := make([]*api.Environment, 0, len(body.Environments))
+	for _, name := range body.Environments {
+		var target *api.Environment
+		for i := range feature.Environments {
+			if feature.Environments[i].Name == name {
+				target = &feature.Environments[i]
+			}
+		}
+		if target == nil {
+			writeNotFound(w, "Could not find environment "+name+" for feature "+feature.Name)
+			return
+		}
+		targets = append(targets, target)
+	}
+	for _, env := range targets {
+		env.Variants = append([]api.Variant(nil), variants...)
+	}
+	writeJSON(w, http.StatusOK, api.VariantsResponse{Version: 1, Variants: variants})
+}
+
+// storedVariants checks variants like Unleash and returns them with the
+// weights of variable variants distributed, answering with 400 and returning
+// false when they are invalid.
+func storedVariants(w http.ResponseWriter, variants []api.Variant) ([]api.Variant, bool) {
+	names := make(map[string]bool)
+	fixedWeight := 0
+	variableCount := 0
+	for _, variant := range variants {
+		if variant.Name == "" {
+			writeValidationError(w, "Every variant needs a name")
+			return nil, false
+		}
+		if names[variant.Name] {
+			writeValidationError(w, "Variant names must be unique: "+variant.Name)
+			return nil, false
+		}
+		names[variant.Name] = true
+		if variant.WeightType == api.WeightTypeFix {
+			fixedWeight += variant.Weight
+		} else {
+			variableCount++
+		}
+	}
+	if fixedWeight > api.TotalVariantWeight {
+		writeValidationError(w, "The sum of the fixed variant weights can not exceed 1000")
+		return nil, false
+	}
+	if variableCount == 0 && len(variants) > 0 && fixedWeight != api.TotalVariantWeight {
+		writeValidationError(w, "The weights of fixed variants must add up to 1000 when there are no variable variants")
+		return nil, false
+	}
+
+	variants = api.DistributeVariantWeights(variants)
+	for i := range variants {
+		if variants[i].WeightType != api.WeightTypeFix {
+			variants[i].WeightType = api.WeightTypeVariable
+		}
+	}
+	if variants == nil {
+		variants = []api.Variant{}
+	}
+	return variants, true
+}
+
+func (s *Server) toggleEnvironment(enabled bool) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, p params) {
+		_, env, ok := s.lookupEnvironment(w, p)
+		if !ok {
+			return
+		}
+		env.Enabled = enabled
+		writeJSON(w, http.StatusOK, nil)
+	}
+}
+
+func (s *Server) addFeatureStrategy(w http.ResponseWriter, r *http.Request, p params) {
+	_, env, ok := s.lookupEnvironment(w, p)
+	if !ok {
+		return
+	}
+	var strategy api.FeatureStrategy
+	if !decodeBody(w, r, &strategy) || !s.checkStrategy(w, &strategy) {
+		return
+	}
+
+	strategy.ID = s.nextID()
+	env.Strategies = append(env.Strategies, strategy)
+	writeJSON(w, http.StatusOK, strategy)
+}
+
+// lookupStrategy resolves the feature strategy named in the path.
+func (s *Server) lookupStrategy(w http.ResponseWriter, p params) (*api.FeatureStrategy, bool) {
+	_, env, ok := s.lookupEnvironment(w, p)
+	if !ok {
+		return nil, false
+	}
+	for i := range env.Strategies {
+		if env.Strategies[i].ID == p["strategy"] {
+			return &env.Strategies[i], true
+		}
+	}
+	writeNotFound(w, "Could not find strategy with id "+p["strategy"])
+	return nil, false
+}
+
+// checkStrategy validates a strategy sent by a client and normalizes its
+// variants, answering with an error and returning false when it is invalid.
+func (s *Server) checkStrategy(w http.ResponseWriter, strategy *api.FeatureStrategy) bool {
+	if _, known := s.strategies[strategy.Name]; !known {
+		writeNotFound(w, "Could not find strategy with name "+strategy.Name)
+		return false
+	}
+	if err := strategy.Validate(); err != nil {
+		writeValidationError(w, err.Error())
+		return false
+	}
+	if len(strategy.Variants) > 0 {
+		variants, ok := storedVariants(w, strategy.Variants)
+		if !ok {
+			return false
+		}
+		strategy.Variants = variants
+	}
+	return true
+}
+
+func (s *Server) getFeatureStrategies(w http.ResponseWriter, r *http.Request, p params) {
+	env, ok := s.lookupReadableEnvironment(w, p)
+	if !ok {
+		return
+	}
+	strategies := env.Strategies
+	if strategies == nil {
+		strategies = []api.FeatureStrategy{}
+	}
+	writeJSON(w, http.StatusOK, strategies)
+}
+
+func (s *Server) getFeatureStrategy(w http.ResponseWriter, r *http.Request, p params) {
+	strategy, ok := s.lookupStrategy(w, p)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, strategy)
+}
+
+func (s *Server) updateFeatureStrategy(w http.ResponseWriter, r *http.Request, p params) {
+	stored, ok := s.lookupStrategy(w, p)
+	if !ok {
+		return
+	}
+	var strategy api.FeatureStrategy
+	if !decodeBody(w, r, &strategy) || !s.checkStrategy(w, &strategy) {
+		return
+	}
+	strategy.ID = stored.ID
+	*stored = strategy
+	writeJSON(w, http.StatusOK, strategy)
+}
+
+func (s *Server) patchFeatureStrategy(w http.ResponseWriter, r *http.Request, p params) {
+	stored, ok := s.lookupStrategy(w, p)
+	if !ok {
+		return
+	}
+	var strategy api.FeatureStrategy
+	if !s.applyPatch(w, r, *stored, &strategy) || !s.checkStrategy(w, &strategy) {
+		return
+	}
+	strategy.ID = stored.ID
+	*stored = strategy
+	writeJSON(w, http.StatusOK, strategy)
+}
+
+func (s *Server) setStrategySortOrder(w http.ResponseWriter, r *http.Request, p params) {
+	_, env, ok := s.lookupEnvironment(w, p)
+	if !ok {
+		return
+	}
+	var body []struct {
+		ID        string `json:"id"`
+		SortOrder int    `json:"sortOrder"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	orders := make(map[string]int, len(body))
+	for _, entry := range body {
+		orders[entry.ID] = entry.SortOrder
+	}
+	for id := range orders {
+		found := false
+		for _, strategy := range env.Strategies {
+			found = found || strategy.ID == id
+		}
+		if !found {
+			writeNotFound(w, "Could not find strategy with id "+id)
+			return
+		}
+	}
+	for i := range env.Strategies {
+		if order, ok := orders[env.Strategies[i].ID]; ok {
+			env.Strategies[i].SortOrder = order
+		}
+	}
+	sort.SliceStable(env.Strategies, func(i, j int) bool {
+		return env.Strategies[i].SortOrder < env.Strategies[j].SortOrder
+	})
+	writeJSON(w, http.StatusOK, nil)
+}
+
+func (s *Server) deleteFeatureStrategy(w http.ResponseWriter, r *http.Request, p params) {
+	_, env, ok := s.lookupEnvironment(w, p)
+	if !ok {
+		return
+	}
+	for i := range env.Strategies {
+		if env.Strategies[i].ID == p["strategy"] {
+			env.Strategies = append(env.Strategies[:i], env.Strategies[i+1:]...)
+			writeJSON(w, http.StatusOK, nil)
+			return
+		}
+	}
+	writeNotFound(w, "Could not find strategy with id "+p["strategy"])
+}
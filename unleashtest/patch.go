@@ -0,0 +1,215 @@
+package unleashtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/sighphyre/go-unleash-api/api"
+)
+
+// applyPatch applies the JSON Patch in the request body to the JSON form of
+// current and decodes the outcome into patched, answering with an error and
+// returning false when the patch is malformed or does not apply.
+func (s *Server) applyPatch(w http.ResponseWriter, r *http.Request, current interface{}, patched interface{}) bool {
+	var ops []api.PatchOperation
+	if !decodeBody(w, r, &ops) {
+		return false
+	}
+	var doc interface{}
+	deepCopy(current, &doc)
+	for i, op := range ops {
+		var err error
+		if doc, err = applyOperation(doc, op); err != nil {
+			writeValidationError(w, fmt.Sprintf("patch operation %d (%s %s): %v", i, op.Op, op.Path, err))
+			return false
+		}
+	}
+	data, err := json.Marshal(doc)
+	if err == nil {
+		err = json.Unmarshal(data, patched)
+	}
+	if err != nil {
+		writeValidationError(w, "The patched document is invalid: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func applyOperation(doc interface{}, op api.PatchOperation) (interface{}, error) {
+	switch op.Op {
+	case api.PatchAdd:
+		return addValue(doc, op.Path, op.Value)
+	case api.PatchRemove:
+		doc, _, err := removeValue(doc, op.Path)
+		return doc, err
+	case api.PatchReplace:
+		doc, _, err := removeValue(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, op.Path, op.Value)
+	case api.PatchMove:
+		doc, value, err := removeValue(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, op.Path, value)
+	case api.PatchCopy:
+		value, err := getValue(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		var clone interface{}
+		deepCopy(value, &clone)
+		return addValue(doc, op.Path, clone)
+	case api.PatchTest:
+		value, err := getValue(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		var want interface{}
+		deepCopy(op.Value, &want)
+		if !reflect.DeepEqual(value, want) {
+			return nil, fmt.Errorf("value is %v", value)
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown operation %q", op.Op)
+}
+
+func splitPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("pointer %q must start with /", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+func getValue(doc interface{}, pointer string) (interface{}, error) {
+	tokens, err := splitPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	for _, token := range tokens {
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("%s does not exist", pointer)
+			}
+			doc = value
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, fmt.Errorf("%s does not exist", pointer)
+			}
+			doc = node[i]
+		default:
+			return nil, fmt.Errorf("%s does not exist", pointer)
+		}
+	}
+	return doc, nil
+}
+
+// addValue adds value at pointer and returns the document, which is replaced
+// entirely when pointer is empty.
+func addValue(doc interface{}, pointer string, value interface{}) (interface{}, error) {
+	if pointer == "" {
+		return value, nil
+	}
+	parentPointer, last, err := splitLast(pointer)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := getValue(doc, parentPointer)
+	if err != nil {
+		return nil, err
+	}
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[last] = value
+		return doc, nil
+	case []interface{}:
+		i := len(node)
+		if last != "-" {
+			if i, err = strconv.Atoi(last); err != nil || i < 0 || i > len(node) {
+				return nil, fmt.Errorf("index %s is out of range", last)
+			}
+		}
+		node = append(node, nil)
+		copy(node[i+1:], node[i:])
+		node[i] = value
+		return setValue(doc, parentPointer, node)
+	}
+	return nil, fmt.Errorf("%s is not an object or array", parentPointer)
+}
+
+// removeValue removes the value at pointer, returning the document and the
+// removed value.
+func removeValue(doc interface{}, pointer string) (interface{}, interface{}, error) {
+	value, err := getValue(doc, pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pointer == "" {
+		return nil, value, nil
+	}
+	parentPointer, last, err := splitLast(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+	parent, _ := getValue(doc, parentPointer)
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		delete(node, last)
+		return doc, value, nil
+	case []interface{}:
+		i, _ := strconv.Atoi(last)
+		node = append(node[:i:i], node[i+1:]...)
+		doc, err = setValue(doc, parentPointer, node)
+		return doc, value, err
+	}
+	return nil, nil, fmt.Errorf("%s cannot be removed", pointer)
+}
+
+// setValue overwrites the existing value at pointer, which arrays need after
+// growing or shrinking.
+func setValue(doc interface{}, pointer string, value interface{}) (interface{}, error) {
+	if pointer == "" {
+		return value, nil
+	}
+	parentPointer, last, err := splitLast(pointer)
+	if err != nil {
+		return nil, err
+	}
+	parent, _ := getValue(doc, parentPointer)
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[last] = value
+	case []interface{}:
+		i, _ := strconv.Atoi(last)
+		node[i] = value
+	}
+	return doc, nil
+}
+
+// splitLast splits pointer into the pointer of its parent and its unescaped
+// last token.
+func splitLast(pointer string) (string, string, error) {
+	tokens, err := splitPointer(pointer)
+	if err != nil {
+		return "", "", err
+	}
+	i := strings.LastIndex(pointer, "/")
+	return pointer[:i], tokens[len(tokens)-1], nil
+}
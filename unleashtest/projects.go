@@ -0,0 +1,285 @@
+package unleashtest
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/sighphyre/go-unleash-api/api"
+)
+
+var urlFriendly = regexp.MustCompile(`^[a-zA-Z0-9_.~-]+$`)
+
+func (s *Server) getEnvironments(w http.ResponseWriter, r *http.Request, p params) {
+	writeJSON(w, http.StatusOK, struct {
+		Version      int           `json:"version"`
+		Environments []environment `json:"environments"`
+	}{1, s.environments})
+}
+
+func (s *Server) getFeatureTypes(w http.ResponseWriter, r *http.Request, p params) {
+	writeJSON(w, http.StatusOK, api.AllFeatureTypesResponse{Version: 1, Types: s.featureTypes})
+}
+
+func (s *Server) updateFeatureTypeLifetime(w http.ResponseWriter, r *http.Request, p params) {
+	var body struct {
+		LifetimeDays *int `json:"lifetimeDays"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if body.LifetimeDays == nil || *body.LifetimeDays < 0 {
+		writeValidationError(w, `"lifetimeDays" must be a number of days, 0 or more`)
+		return
+	}
+	for i := range s.featureTypes {
+		if s.featureTypes[i].ID == p["type"] {
+			s.featureTypes[i].LifetimeDays = *body.LifetimeDays
+			writeJSON(w, http.StatusOK, s.featureTypes[i])
+			return
+		}
+	}
+	writeNotFound(w, "Could not find feature type with id "+p["type"])
+}
+
+func (s *Server) projectDetails(proj *project) api.ProjectDetails {
+	details := api.ProjectDetails{
+		Name:         proj.Name,
+		Description:  proj.Description,
+		Health:       100,
+		UpdatedAt:    proj.UpdatedAt,
+		Environments: []api.ProjEnvironment{},
+	}
+	for _, env := range proj.Environments {
+		details.Environments = append(details.Environments, api.ProjEnvironment{Environment: env})
+	}
+	return details
+}
+
+func (s *Server) environmentNames() []string {
+	names := make([]string, len(s.environments))
+	for i, env := range s.environments {
+		names[i] = env.Name
+	}
+	return names
+}
+
+func (s *Server) addProjectEnvironment(w http.ResponseWriter, r *http.Request, p params) {
+	proj, ok := s.projects[p["project"]]
+	if !ok {
+		writeNotFound(w, "Could not find project with id "+p["project"])
+		return
+	}
+	var body struct {
+		Environment string `json:"environment"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if !s.environmentExists(body.Environment) {
+		writeNotFound(w, "Could not find environment "+body.Environment)
+		return
+	}
+	if indexOf(proj.Environments, body.Environment) >= 0 {
+		writeError(w, http.StatusConflict, "NameExistsError", "Environment "+body.Environment+" is already enabled in project "+proj.Id)
+		return
+	}
+	proj.Environments = append(proj.Environments, body.Environment)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) removeProjectEnvironment(w http.ResponseWriter, r *http.Request, p params) {
+	proj, ok := s.projects[p["project"]]
+	if !ok {
+		writeNotFound(w, "Could not find project with id "+p["project"])
+		return
+	}
+	i := indexOf(proj.Environments, p["environment"])
+	if i < 0 {
+		writeNotFound(w, "Environment "+p["environment"]+" is not enabled in project "+proj.Id)
+		return
+	}
+	proj.Environments = append(proj.Environments[:i], proj.Environments[i+1:]...)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) environmentExists(name string) bool {
+	for _, env := range s.environments {
+		if env.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) getProjects(w http.ResponseWriter, r *http.Request, p params) {
+	ids := make([]string, 0, len(s.projects))
+	for id := range s.projects {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	projects := make([]api.ProjectSummary, 0, len(ids))
+	for _, id := range ids {
+		proj := s.projects[id]
+		projects = append(projects, api.ProjectSummary{
+			Id:           proj.Id,
+			Name:         proj.Name,
+			Description:  proj.Description,
+			FeatureCount: s.featureCount(id),
+			CreatedAt:    proj.CreatedAt,
+			UpdatedAt:    proj.UpdatedAt,
+		})
+	}
+	writeJSON(w, http.StatusOK, struct {
+		Version  int                  `json:"version"`
+		Projects []api.ProjectSummary `json:"projects"`
+	}{1, projects})
+}
+
+func (s *Server) getProject(w http.ResponseWriter, r *http.Request, p params) {
+	proj, ok := s.projects[p["project"]]
+	if !ok {
+		writeNotFound(w, "Could not find project with id "+p["project"])
+		return
+	}
+	writeJSON(w, http.StatusOK, s.projectDetails(proj))
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request, p params) {
+	var body api.Project
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if body.Id == "" || !urlFriendly.MatchString(body.Id) {
+		writeValidationError(w, `"id" must be URL friendly`)
+		return
+	}
+	if body.Name == "" {
+		writeValidationError(w, `"name" is required`)
+		return
+	}
+	if _, exists := s.projects[body.Id]; exists {
+		writeError(w, http.StatusConflict, "NameExistsError", "A project with id "+body.Id+" already exists")
+		return
+	}
+
+	proj := &project{Project: body, CreatedAt: s.now(), UpdatedAt: s.now(), Roles: make(map[int]int), Environments: s.environmentNames()}
+	s.projects[body.Id] = proj
+	writeJSON(w, http.StatusCreated, api.CreateProjectResponse{
+		Id:          proj.Id,
+		Name:        proj.Name,
+		Description: proj.Description,
+		CreatedAt:   proj.CreatedAt,
+	})
+}
+
+func (s *Server) updateProject(w http.ResponseWriter, r *http.Request, p params) {
+	proj, ok := s.projects[p["project"]]
+	if !ok {
+		writeNotFound(w, "Could not find project with id "+p["project"])
+		return
+	}
+	var body api.Project
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if body.Name == "" {
+		writeValidationError(w, `"name" is required`)
+		return
+	}
+
+	proj.Name = body.Name
+	proj.Description = body.Description
+	proj.UpdatedAt = s.now()
+	writeJSON(w, http.StatusOK, api.CreateProjectResponse{
+		Id:          proj.Id,
+		Name:        proj.Name,
+		Description: proj.Description,
+		CreatedAt:   proj.CreatedAt,
+	})
+}
+
+func (s *Server) deleteProject(w http.ResponseWriter, r *http.Request, p params) {
+	id := p["project"]
+	if _, ok := s.projects[id]; !ok {
+		writeNotFound(w, "Could not find project with id "+id)
+		return
+	}
+	if id == "default" {
+		writeError(w, http.StatusForbidden, "InvalidOperationError", "You can not delete the default project!")
+		return
+	}
+	if s.featureCount(id) > 0 {
+		writeError(w, http.StatusForbidden, "InvalidOperationError", "You can not delete a project with active feature toggles")
+		return
+	}
+
+	delete(s.projects, id)
+	writeJSON(w, http.StatusOK, nil)
+}
+
+func (s *Server) setUserRole(w http.ResponseWriter, r *http.Request, p params) {
+	proj, userId, roleId, ok := s.projectUserRole(w, p)
+	if !ok {
+		return
+	}
+	proj.Roles[userId] = roleId
+	writeJSON(w, http.StatusOK, api.AddUserRoleResponse{UserId: userId, ProjectId: proj.Id, RoleId: roleId})
+}
+
+func (s *Server) removeUserRole(w http.ResponseWriter, r *http.Request, p params) {
+	proj, userId, roleId, ok := s.projectUserRole(w, p)
+	if !ok {
+		return
+	}
+	if proj.Roles[userId] != roleId {
+		writeNotFound(w, "User does not have that role in the project")
+		return
+	}
+	delete(proj.Roles, userId)
+	writeJSON(w, http.StatusOK, api.AddUserRoleResponse{UserId: userId, ProjectId: proj.Id, RoleId: roleId})
+}
+
+func (s *Server) projectUserRole(w http.ResponseWriter, p params) (*project, int, int, bool) {
+	proj, ok := s.projects[p["project"]]
+	if !ok {
+		writeNotFound(w, "Could not find project with id "+p["project"])
+		return nil, 0, 0, false
+	}
+	userId, err := strconv.Atoi(p["user"])
+	if err != nil {
+		writeValidationError(w, "userId must be a number")
+		return nil, 0, 0, false
+	}
+	roleId, err := strconv.Atoi(p["role"])
+	if err != nil {
+		writeValidationError(w, "roleId must be a number")
+		return nil, 0, 0, false
+	}
+	if _, ok := s.users[userId]; !ok {
+		writeNotFound(w, "Could not find user with id "+p["user"])
+		return nil, 0, 0, false
+	}
+	return proj, userId, roleId, true
+}
+
+func (s *Server) featureCount(projectId string) int {
+	count := 0
+	for _, feature := range s.features {
+		if feature.Project == projectId {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,91 @@
+package unleashtest
+
+import "net/http"
+
+func (s *Server) registerRoutes() {
+	s.handle(http.MethodGet, "admin/environments", s.getEnvironments)
+	s.handle(http.MethodGet, "admin/feature-types", s.getFeatureTypes)
+	s.handle(http.MethodPut, "admin/feature-types/:type/lifetime", s.updateFeatureTypeLifetime)
+
+	s.handle(http.MethodGet, "admin/projects", s.getProjects)
+	s.handle(http.MethodPost, "admin/projects", s.createProject)
+	s.handle(http.MethodGet, "admin/projects/:project", s.getProject)
+	s.handle(http.MethodPut, "admin/projects/:project", s.updateProject)
+	s.handle(http.MethodDelete, "admin/projects/:project", s.deleteProject)
+	s.handle(http.MethodPost, "admin/projects/:project/environments", s.addProjectEnvironment)
+	s.handle(http.MethodDelete, "admin/projects/:project/environments/:environment", s.removeProjectEnvironment)
+	s.handle(http.MethodPost, "admin/projects/:project/users/:user/roles/:role", s.setUserRole)
+	s.handle(http.MethodPut, "admin/projects/:project/users/:user/roles/:role", s.setUserRole)
+	s.handle(http.MethodDelete, "admin/projects/:project/users/:user/roles/:role", s.removeUserRole)
+
+	s.handle(http.MethodGet, "admin/projects/:project/features", s.getFeatures)
+	s.handle(http.MethodPost, "admin/projects/:project/features", s.createFeature)
+	s.handle(http.MethodGet, "admin/projects/:project/features/:feature", s.getFeature)
+	s.handle(http.MethodPut, "admin/projects/:project/features/:feature", s.updateFeature)
+	s.handle(http.MethodPatch, "admin/projects/:project/features/:feature", s.patchFeature)
+	s.handle(http.MethodDelete, "admin/projects/:project/features/:feature", s.archiveFeature)
+	s.handle(http.MethodPut, "admin/projects/:project/features/:feature/variants", s.putVariants)
+	s.handle(http.MethodPost, "admin/projects/:project/features/:feature/clone", s.cloneFeature)
+	s.handle(http.MethodPost, "admin/projects/:project/features/:feature/changeProject", s.changeProject)
+	s.handle(http.MethodPut, "admin/projects/:project/features/:feature/variants-batch", s.putVariantsBatch)
+	s.handle(http.MethodGet, "admin/projects/:project/features/:feature/environments/:environment/variants", s.getEnvironmentVariants)
+	s.handle(http.MethodPut, "admin/projects/:project/features/:feature/environments/:environment/variants", s.putEnvironmentVariants)
+	s.handle(http.MethodPost, "admin/projects/:project/features/:feature/environments/:environment/on", s.toggleEnvironment(true))
+	s.handle(http.MethodPost, "admin/projects/:project/features/:feature/environments/:environment/off", s.toggleEnvironment(false))
+	s.handle(http.MethodGet, "admin/projects/:project/features/:feature/environments/:environment/strategies", s.getFeatureStrategies)
+	s.handle(http.MethodPost, "admin/projects/:project/features/:feature/environments/:environment/strategies", s.addFeatureStrategy)
+	s.handle(http.MethodPost, "admin/projects/:project/features/:feature/environments/:environment/strategies/set-sort-order", s.setStrategySortOrder)
+	s.handle(http.MethodGet, "admin/projects/:project/features/:feature/environments/:environment/strategies/:strategy", s.getFeatureStrategy)
+	s.handle(http.MethodPut, "admin/projects/:project/features/:feature/environments/:environment/strategies/:strategy", s.updateFeatureStrategy)
+	s.handle(http.MethodPatch, "admin/projects/:project/features/:feature/environments/:environment/strategies/:strategy", s.patchFeatureStrategy)
+	s.handle(http.MethodDelete, "admin/projects/:project/features/:feature/environments/:environment/strategies/:strategy", s.deleteFeatureStrategy)
+	s.handle(http.MethodDelete, "admin/archive/:feature", s.deleteArchivedFeature)
+	s.handle(http.MethodGet, "admin/archive/features", s.getArchivedFeatures)
+	s.handle(http.MethodGet, "admin/archive/features/:project", s.getArchivedFeatures)
+	s.handle(http.MethodPost, "admin/archive/revive/:feature", s.reviveFeature)
+	s.handle(http.MethodPost, "admin/projects/:project/stale", s.markFeaturesStale)
+	s.handle(http.MethodPost, "admin/projects/:project/revive", s.reviveFeatures)
+	s.handle(http.MethodPost, "admin/projects/:project/delete", s.deleteArchivedFeatures)
+	s.handle(http.MethodGet, "admin/search/features", s.searchFeatures)
+
+	s.handle(http.MethodGet, "admin/features/:feature/tags", s.getFeatureTags)
+	s.handle(http.MethodPost, "admin/features/:feature/tags", s.addFeatureTag)
+	s.handle(http.MethodPut, "admin/features/:feature/tags", s.updateFeatureTags)
+	s.handle(http.MethodDelete, "admin/features/:feature/tags/:type/:value", s.deleteFeatureTag)
+
+	s.handle(http.MethodGet, "admin/tag-types", s.getTagTypes)
+	s.handle(http.MethodPost, "admin/tag-types", s.createTagType)
+	s.handle(http.MethodGet, "admin/tag-types/:name", s.getTagType)
+	s.handle(http.MethodPut, "admin/tag-types/:name", s.updateTagType)
+	s.handle(http.MethodDelete, "admin/tag-types/:name", s.deleteTagType)
+
+	s.handle(http.MethodGet, "admin/context", s.getContextFields)
+	s.handle(http.MethodPost, "admin/context", s.createContextField)
+	s.handle(http.MethodGet, "admin/context/:name", s.getContextField)
+	s.handle(http.MethodPut, "admin/context/:name", s.updateContextField)
+	s.handle(http.MethodDelete, "admin/context/:name", s.deleteContextField)
+
+	s.handle(http.MethodGet, "admin/segments", s.getSegments)
+	s.handle(http.MethodPost, "admin/segments", s.createSegment)
+	s.handle(http.MethodGet, "admin/segments/:id", s.getSegment)
+	s.handle(http.MethodPut, "admin/segments/:id", s.updateSegment)
+	s.handle(http.MethodDelete, "admin/segments/:id", s.deleteSegment)
+
+	s.handle(http.MethodGet, "admin/strategies", s.getStrategies)
+	s.handle(http.MethodPost, "admin/strategies", s.createStrategy)
+	s.handle(http.MethodGet, "admin/strategies/:strategy", s.getStrategy)
+	s.handle(http.MethodPut, "admin/strategies/:strategy", s.updateStrategy)
+	s.handle(http.MethodPost, "admin/strategies/:strategy/deprecate", s.setStrategyDeprecated(true))
+	s.handle(http.MethodPost, "admin/strategies/:strategy/reactivate", s.setStrategyDeprecated(false))
+
+	s.handle(http.MethodGet, "admin/user-admin/search", s.searchUsers)
+	s.handle(http.MethodPost, "admin/user-admin", s.createUser)
+	s.handle(http.MethodGet, "admin/user-admin/:user", s.getUser)
+	s.handle(http.MethodPut, "admin/user-admin/:user", s.updateUser)
+	s.handle(http.MethodDelete, "admin/user-admin/:user", s.deleteUser)
+
+	s.handle(http.MethodGet, "admin/api-tokens", s.getApiTokens)
+	s.handle(http.MethodPost, "admin/api-tokens", s.createApiToken)
+	s.handle(http.MethodPut, "admin/api-tokens/:secret", s.updateApiToken)
+	s.handle(http.MethodDelete, "admin/api-tokens/:secret", s.deleteApiToken)
+}
@@ -0,0 +1,125 @@
+package unleashtest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sighphyre/go-unleash-api/api"
+)
+
+// searchFilter is a parsed OPERATOR:values query parameter.
+type searchFilter struct {
+	operator string
+	values   []string
+}
+
+func parseSearchFilter(raw string) (searchFilter, bool) {
+	if raw == "" {
+		return searchFilter{}, true
+	}
+	i := strings.IndexByte(raw, ':')
+	if i < 0 {
+		return searchFilter{}, false
+	}
+	return searchFilter{operator: raw[:i], values: strings.Split(raw[i+1:], ",")}, true
+}
+
+// matches reports whether any of the feature values satisfies the filter.
+func (f searchFilter) matches(values ...string) bool {
+	if f.operator == "" {
+		return true
+	}
+	for _, value := range values {
+		for _, want := range f.values {
+			if value == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *Server) searchFeatures(w http.ResponseWriter, r *http.Request, p params) {
+	q := r.URL.Query()
+	filters := make(map[string]searchFilter)
+	for _, name := range []string{"project", "tag", "type", "state", "status", "createdAt"} {
+		filter, ok := parseSearchFilter(q.Get(name))
+		if !ok {
+			writeValidationError(w, "Invalid filter for "+name+": "+q.Get(name))
+			return
+		}
+		filters[name] = filter
+	}
+	createdAt := filters["createdAt"]
+	if createdAt.operator != "" && (createdAt.operator != "IS_ON_OR_AFTER" || len(createdAt.values) != 1) {
+		writeValidationError(w, "Unsupported createdAt filter: "+q.Get("createdAt"))
+		return
+	}
+	offset, _ := strconv.Atoi(q.Get("offset"))
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 {
+		limit = 50
+	}
+	text := strings.ToLower(q.Get("query"))
+
+	matched := []api.FeatureToggle{}
+	for _, name := range sortedKeys(s.features) {
+		feature := *s.features[name]
+		if text != "" && !strings.Contains(strings.ToLower(feature.Name), text) && !strings.Contains(strings.ToLower(feature.Description), text) {
+			continue
+		}
+		state := "active"
+		if feature.Stale {
+			state = "stale"
+		}
+		var tags, statuses []string
+		for _, tag := range s.tags[name] {
+			tags = append(tags, tag.Type+":"+tag.Value)
+		}
+		for _, env := range feature.Environments {
+			if env.Enabled {
+				statuses = append(statuses, env.Name+":enabled")
+			} else {
+				statuses = append(statuses, env.Name+":disabled")
+			}
+		}
+		if !filters["project"].matches(feature.Project) || !filters["type"].matches(feature.Type) ||
+			!filters["state"].matches(state) || !filters["tag"].matches(tags...) || !filters["status"].matches(statuses...) {
+			continue
+		}
+		if createdAt.operator != "" && feature.CreatedAt[:10] < createdAt.values[0] {
+			continue
+		}
+		feature.Tags = s.featureTags(name)
+		matched = append(matched, feature)
+	}
+
+	sortFeatures(matched, q.Get("sortBy"), q.Get("sortOrder") == "desc")
+	total := len(matched)
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	writeJSON(w, http.StatusOK, api.FeatureSearchResult{Features: matched[offset:end], Total: total})
+}
+
+func sortFeatures(features []api.FeatureToggle, sortBy string, descending bool) {
+	key := func(f api.FeatureToggle) string { return f.Name }
+	switch sortBy {
+	case "type":
+		key = func(f api.FeatureToggle) string { return f.Type }
+	case "createdAt":
+		key = func(f api.FeatureToggle) string { return f.CreatedAt }
+	}
+	sort.SliceStable(features, func(i, j int) bool {
+		if descending {
+			return key(features[i]) > key(features[j])
+		}
+		return key(features[i]) < key(features[j])
+	})
+}
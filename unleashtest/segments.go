@@ -0,0 +1,112 @@
+package unleashtest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/sighphyre/go-unleash-api/api"
+)
+
+func (s *Server) getSegments(w http.ResponseWriter, r *http.Request, p params) {
+	ids := make([]int, 0, len(s.segments))
+	for id := range s.segments {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	segments := make([]api.Segment, 0, len(ids))
+	for _, id := range ids {
+		segments = append(segments, *s.segments[id])
+	}
+	writeJSON(w, http.StatusOK, struct {
+		Segments []api.Segment `json:"segments"`
+	}{segments})
+}
+
+func (s *Server) lookupSegment(w http.ResponseWriter, p params) (*api.Segment, bool) {
+	id, _ := strconv.Atoi(p["id"])
+	segment, ok := s.segments[id]
+	if !ok {
+		writeNotFound(w, "Could not find segment with id "+p["id"])
+		return nil, false
+	}
+	return segment, true
+}
+
+func (s *Server) getSegment(w http.ResponseWriter, r *http.Request, p params) {
+	if segment, ok := s.lookupSegment(w, p); ok {
+		writeJSON(w, http.StatusOK, segment)
+	}
+}
+
+func (s *Server) createSegment(w http.ResponseWriter, r *http.Request, p params) {
+	var segment api.Segment
+	if !decodeBody(w, r, &segment) || !s.validSegment(w, segment, 0) {
+		return
+	}
+	s.segmentSeq++
+	segment.ID = s.segmentSeq
+	segment.CreatedAt = s.now()
+	s.segments[segment.ID] = &segment
+	writeJSON(w, http.StatusCreated, segment)
+}
+
+func (s *Server) updateSegment(w http.ResponseWriter, r *http.Request, p params) {
+	existing, ok := s.lookupSegment(w, p)
+	if !ok {
+		return
+	}
+	var segment api.Segment
+	if !decodeBody(w, r, &segment) || !s.validSegment(w, segment, existing.ID) {
+		return
+	}
+	existing.Name = segment.Name
+	existing.Description = segment.Description
+	existing.Project = segment.Project
+	existing.Constraints = segment.Constraints
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteSegment(w http.ResponseWriter, r *http.Request, p params) {
+	segment, ok := s.lookupSegment(w, p)
+	if !ok {
+		return
+	}
+	for _, feature := range s.features {
+		for _, env := range feature.Environments {
+			for _, strategy := range env.Strategies {
+				for _, id := range strategy.Segments {
+					if id == segment.ID {
+						writeError(w, http.StatusConflict, "SegmentInUseError", "The segment is used by "+feature.Name)
+						return
+					}
+				}
+			}
+		}
+	}
+	delete(s.segments, segment.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validSegment checks the name of a segment, which is unique, and its
+// constraints. id is the segment being updated, or 0.
+func (s *Server) validSegment(w http.ResponseWriter, segment api.Segment, id int) bool {
+	if segment.Name == "" {
+		writeValidationError(w, `"name" is required`)
+		return false
+	}
+	for _, other := range s.segments {
+		if other.Name == segment.Name && other.ID != id {
+			writeError(w, http.StatusConflict, "NameExistsError", "There is already a segment named "+segment.Name)
+			return false
+		}
+	}
+	for _, constraint := range segment.Constraints {
+		if err := constraint.Validate(); err != nil {
+			writeValidationError(w, err.Error())
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,353 @@
+// Package unleashtest provides an in-memory fake of the Unleash Admin API for
+// tests that want to exercise the real api.ApiClient end to end.
+//
+//	srv := unleashtest.NewServer()
+//	defer srv.Close()
+//
+//	client, err := srv.NewClient()
+package unleashtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sighphyre/go-unleash-api/api"
+)
+
+// DefaultToken is the admin token accepted by a Server unless changed with
+// WithToken.
+const DefaultToken = "*:*.unleashtest"
+
+// Server is an httptest.Server implementing the parts of the Unleash Admin API
+// covered by the api package, backed by in-memory state. It is safe for
+// concurrent use, so every parallel test can start its own Server or share one.
+type Server struct {
+	*httptest.Server
+
+	token  string
+	routes []route
+
+	mu           sync.Mutex
+	sequence     int
+	environments []environment
+	featureTypes []api.FeatureType
+	projects     map[string]*project
+	features     map[string]*api.FeatureToggle
+	archived     map[string]*api.FeatureToggle
+	tags         map[string][]api.FeatureTag
+	strategies   map[string]*api.Strategy
+	tagTypes     map[string]*api.TagType
+	contexts     map[string]*api.ContextField
+	segments     map[int]*api.Segment
+	segmentSeq   int
+	users        map[int]*api.UserDetails
+	tokens       map[string]*api.ApiToken
+	clock        func() time.Time
+}
+
+type environment struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Enabled   bool   `json:"enabled"`
+	SortOrder int    `json:"sortOrder"`
+}
+
+type project struct {
+	api.Project
+	CreatedAt string
+	UpdatedAt string
+	Roles     map[int]int
+	// Environments are the names of the environments enabled in the project.
+	Environments []string
+}
+
+// ServerOption configures a Server in NewServer.
+type ServerOption func(s *Server)
+
+// WithToken changes the admin token the Server accepts.
+func WithToken(token string) ServerOption {
+	return func(s *Server) {
+		s.token = token
+	}
+}
+
+// WithClock sets the clock the Server stamps creation and archival times
+// with. It defaults to time.Now.
+func WithClock(clock func() time.Time) ServerOption {
+	return func(s *Server) {
+		s.clock = clock
+	}
+}
+
+// WithEnvironment adds an environment to the Server. development and
+// production exist by default.
+func WithEnvironment(name string, environmentType string) ServerOption {
+	return func(s *Server) {
+		s.environments = append(s.environments, environment{
+			Name:      name,
+			Type:      environmentType,
+			Enabled:   true,
+			SortOrder: len(s.environments) + 1,
+		})
+	}
+}
+
+// NewServer starts a Server seeded like a fresh Unleash instance: a default
+// project, the development and production environments, the built-in feature
+// types and the built-in activation strategies. Callers must Close it.
+func NewServer(opts ...ServerOption) *Server {
+	s := &Server{
+		token: DefaultToken,
+		clock: time.Now,
+		environments: []environment{
+			{Name: "development", Type: "development", Enabled: true, SortOrder: 1},
+			{Name: "production", Type: "production", Enabled: true, SortOrder: 2},
+		},
+		featureTypes: []api.FeatureType{
+			{ID: "release", Name: "Release", Description: "Release feature toggles are used to release new features.", LifetimeDays: 40},
+			{ID: "experiment", Name: "Experiment", Description: "Experiment feature toggles are used to test and verify multiple different versions of a feature.", LifetimeDays: 40},
+			{ID: "operational", Name: "Operational", Description: "Operational feature toggles are used to control aspects of a rollout.", LifetimeDays: 7},
+			{ID: "kill-switch", Name: "Kill switch", Description: "Kill switch feature toggles are used to quickly turn on or off critical functionality in your system."},
+			{ID: "permission", Name: "Permission", Description: "Permission feature toggles are used to control permissions in your system."},
+		},
+		projects: make(map[string]*project),
+		features: make(map[string]*api.FeatureToggle),
+		archived: make(map[string]*api.FeatureToggle),
+		tags:     make(map[string][]api.FeatureTag),
+		strategies: map[string]*api.Strategy{
+			"default":             {Name: "default", DisplayName: "Standard", Description: "The standard strategy is strictly on / off for your entire userbase."},
+			"userWithId":          {Name: "userWithId", DisplayName: "UserIDs", Description: "Enable the feature for a specific set of userIds.", Parameters: []api.StrategyParameter{{Name: "userIds", Type: "list"}}},
+			"flexibleRollout":     {Name: "flexibleRollout", DisplayName: "Gradual rollout", Description: "Gradually activate feature toggle based on sane stickiness", Parameters: []api.StrategyParameter{{Name: "rollout", Type: "percentage"}, {Name: "stickiness", Type: "string"}, {Name: "groupId", Type: "string"}}},
+			"remoteAddress":       {Name: "remoteAddress", DisplayName: "IPs", Description: "Enable the feature for a specific set of IP addresses.", Parameters: []api.StrategyParameter{{Name: "IPs", Type: "list"}}},
+			"applicationHostname": {Name: "applicationHostname", DisplayName: "Hosts", Description: "Enable the feature for a specific set of hostnames.", Parameters: []api.StrategyParameter{{Name: "hostNames", Type: "list"}}},
+		},
+		tagTypes: map[string]*api.TagType{
+			"simple": {Name: "simple", Description: "Used to simplify filtering of features", Icon: "#"},
+		},
+		contexts: map[string]*api.ContextField{
+			"environment": {Name: "environment", Description: "Allows you to constrain on application environment", SortOrder: 0},
+			"userId":      {Name: "userId", Description: "Allows you to constrain on userId", SortOrder: 1},
+			"appName":     {Name: "appName", Description: "Allows you to constrain on application name", SortOrder: 2},
+		},
+		segments: make(map[int]*api.Segment),
+		users:    make(map[int]*api.UserDetails),
+		tokens:   make(map[string]*api.ApiToken),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.projects["default"] = &project{
+		Project:      api.Project{Id: "default", Name: "Default", Description: "Default project"},
+		CreatedAt:    s.now(),
+		UpdatedAt:    s.now(),
+		Roles:        make(map[int]int),
+		Environments: s.environmentNames(),
+	}
+	s.registerRoutes()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Token returns the admin token the Server accepts.
+func (s *Server) Token() string {
+	return s.token
+}
+
+// NewClient returns an api.ApiClient talking to the Server. Extra options are
+// applied after the ones pointing it at the Server.
+func (s *Server) NewClient(opts ...api.Option) (*api.ApiClient, error) {
+	opts = append([]api.Option{api.WithHTTPClient(s.Client())}, opts...)
+	return api.NewClient(s.URL+"/api/", s.token, opts...)
+}
+
+// Feature returns a copy of the live feature with the given name.
+func (s *Server) Feature(name string) (api.FeatureToggle, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feature, ok := s.features[name]
+	if !ok {
+		return api.FeatureToggle{}, false
+	}
+	var copied api.FeatureToggle
+	deepCopy(feature, &copied)
+	return copied, true
+}
+
+// Features returns copies of all live features, sorted by name.
+func (s *Server) Features() []api.FeatureToggle {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	features := make([]api.FeatureToggle, 0, len(s.features))
+	for _, name := range sortedKeys(s.features) {
+		var copied api.FeatureToggle
+		deepCopy(s.features[name], &copied)
+		features = append(features, copied)
+	}
+	return features
+}
+
+// ArchivedFeatures returns copies of all archived features, sorted by name.
+func (s *Server) ArchivedFeatures() []api.FeatureToggle {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	features := make([]api.FeatureToggle, 0, len(s.archived))
+	for _, name := range sortedKeys(s.archived) {
+		var copied api.FeatureToggle
+		deepCopy(s.archived[name], &copied)
+		features = append(features, copied)
+	}
+	return features
+}
+
+type params map[string]string
+
+type handlerFunc func(w http.ResponseWriter, r *http.Request, p params)
+
+type route struct {
+	method   string
+	segments []string
+	handler  handlerFunc
+}
+
+func (s *Server) handle(method string, pattern string, handler handlerFunc) {
+	s.routes = append(s.routes, route{
+		method:   method,
+		segments: strings.Split(pattern, "/"),
+		handler:  handler,
+	})
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != s.token {
+		writeError(w, http.StatusUnauthorized, "AuthenticationRequired", "You must log in to use Unleash.")
+		return
+	}
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/"), "/")
+	segments := strings.Split(path, "/")
+
+	pathMatched := false
+	for _, rt := range s.routes {
+		p, ok := rt.match(segments)
+		if !ok {
+			continue
+		}
+		pathMatched = true
+		if rt.method != r.Method {
+			continue
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		rt.handler(w, r, p)
+		return
+	}
+
+	if pathMatched {
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowedError", fmt.Sprintf("%s is not allowed on /api/%s", r.Method, path))
+		return
+	}
+	writeError(w, http.StatusNotFound, "NotFoundError", "The path you were looking for (/api/"+path+") is not available.")
+}
+
+func (rt route) match(segments []string) (params, bool) {
+	if len(rt.segments) != len(segments) {
+		return nil, false
+	}
+	p := make(params)
+	for i, segment := range rt.segments {
+		if strings.HasPrefix(segment, ":") {
+			p[segment[1:]] = segments[i]
+			continue
+		}
+		if segment != segments[i] {
+			return nil, false
+		}
+	}
+	return p, true
+}
+
+func (s *Server) nextID() string {
+	s.sequence++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", s.sequence)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if v != nil {
+		_ = json.NewEncoder(w).Encode(v)
+	}
+}
+
+type errorBody struct {
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	Message string            `json:"message"`
+	Details []api.ErrorDetail `json:"details,omitempty"`
+}
+
+func writeError(w http.ResponseWriter, status int, name string, message string) {
+	writeJSON(w, status, errorBody{
+		ID:      fmt.Sprintf("%08x-0000-4000-8000-000000000000", time.Now().UnixNano()&0xffffffff),
+		Name:    name,
+		Message: message,
+	})
+}
+
+func writeNotFound(w http.ResponseWriter, message string) {
+	writeError(w, http.StatusNotFound, "NotFoundError", message)
+}
+
+func writeValidationError(w http.ResponseWriter, message string) {
+	body := errorBody{
+		Name:    "ValidationError",
+		Message: "Request validation failed: your request body or params contain invalid data. Refer to the `details` list for more information.",
+		Details: []api.ErrorDetail{{Message: message, Description: message}},
+	}
+	writeJSON(w, http.StatusBadRequest, body)
+}
+
+// decodeBody decodes the JSON request body into v, answering with a
+// validation error and returning false when it is malformed.
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeValidationError(w, "The request body is not valid JSON: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func deepCopy(from interface{}, to interface{}) {
+	data, err := json.Marshal(from)
+	if err != nil {
+		panic(err)
+	}
+	if err := json.Unmarshal(data, to); err != nil {
+		panic(err)
+	}
+}
+
+func sortedKeys(m map[string]*api.FeatureToggle) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// now returns the current time of the server clock in the format Unleash
+// uses for timestamps.
+func (s *Server) now() string {
+	return s.clock().UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,815 @@
+package unleashtest
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sighphyre/go-unleash-api/api"
+)
+
+func newTestClient(t *testing.T) (*Server, *api.ApiClient) {
+	t.Helper()
+	srv := NewServer()
+	t.Cleanup(srv.Close)
+
+	client, err := srv.NewClient()
+	if err != nil {
+		t.Fatalf("Server.NewClient() error = %v", err)
+	}
+	return srv, client
+}
+
+func TestServer_Projects(t *testing.T) {
+	t.Parallel()
+	_, client := newTestClient(t)
+
+	if _, _, err := client.Projects.CreateProject(api.Project{Id: "payments", Name: "Payments"}); err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	if _, _, err := client.Projects.CreateProject(api.Project{Id: "payments", Name: "Payments"}); !errors.Is(err, api.ErrConflict) {
+		t.Errorf("CreateProject() on an existing project error = %v, want ErrConflict", err)
+	}
+
+	details, _, err := client.Projects.GetProjectById("payments")
+	if err != nil {
+		t.Fatalf("GetProjectById() error = %v", err)
+	}
+	if details.Name != "Payments" || len(details.Environments) != 2 {
+		t.Errorf("GetProjectById() got = %+v", details)
+	}
+
+	if _, err := client.Projects.DeleteProject("payments"); err != nil {
+		t.Fatalf("DeleteProject() error = %v", err)
+	}
+	if _, _, err := client.Projects.GetProjectById("payments"); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("GetProjectById() after delete error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestServer_FeatureLifecycle(t *testing.T) {
+	t.Parallel()
+	srv, client := newTestClient(t)
+
+	created, _, err := client.FeatureToggles.CreateFeature("default", api.FeatureToggle{Name: "checkout", Type: "release"})
+	if err != nil {
+		t.Fatalf("CreateFeature() error = %v", err)
+	}
+	if len(created.Environments) != 2 {
+		t.Errorf("CreateFeature() environments = %v, want development and production", created.Environments)
+	}
+
+	strategy, _, err := client.FeatureToggles.AddStrategyToFeature("default", "checkout", "production", api.FeatureStrategy{Name: "default"})
+	if err != nil {
+		t.Fatalf("AddStrategyToFeature() error = %v", err)
+	}
+	if strategy.ID == "" {
+		t.Error("AddStrategyToFeature() returned a strategy without id")
+	}
+	if _, _, err := client.FeatureToggles.EnableFeatureOnEnvironment("default", "checkout", "production", true); err != nil {
+		t.Fatalf("EnableFeatureOnEnvironment() error = %v", err)
+	}
+	variants, _, err := client.Variants.AddVariantsForFeatureToggle("default", "checkout", []api.Variant{
+		{Name: "blue", WeightType: "variable", Stickiness: "default"},
+		{Name: "green", WeightType: "variable", Stickiness: "default"},
+		{Name: "red", WeightType: "variable", Stickiness: "default"},
+	})
+	if err != nil {
+		t.Fatalf("AddVariantsForFeatureToggle() error = %v", err)
+	}
+	if total := variants.Variants[0].Weight + variants.Variants[1].Weight + variants.Variants[2].Weight; total != 1000 {
+		t.Errorf("variant weights add up to %d, want 1000", total)
+	}
+	if _, _, err := client.FeatureTags.CreateFeatureTags("checkout", api.FeatureTag{Type: "simple", Value: "team-a"}); err != nil {
+		t.Fatalf("CreateFeatureTags() error = %v", err)
+	}
+
+	feature, _, err := client.FeatureToggles.GetFeatureByName("default", "checkout")
+	if err != nil {
+		t.Fatalf("GetFeatureByName() error = %v", err)
+	}
+	production := feature.Environments[1]
+	if !production.Enabled || len(production.Strategies) != 1 || production.Strategies[0].ID != strategy.ID {
+		t.Errorf("production environment = %+v", production)
+	}
+
+	features, _, err := client.FeatureToggles.GetFeaturesByProject("default")
+	if err != nil {
+		t.Fatalf("GetFeaturesByProject() error = %v", err)
+	}
+	if len(*features) != 1 || (*features)[0].Name != "checkout" {
+		t.Errorf("GetFeaturesByProject() got = %v", *features)
+	}
+
+	if ok, _, err := client.FeatureToggles.ArchiveFeature("default", "checkout"); !ok || err != nil {
+		t.Fatalf("ArchiveFeature() = %v, %v", ok, err)
+	}
+	if _, ok := srv.Feature("checkout"); ok {
+		t.Error("archived feature is still live")
+	}
+	if ok, _, err := client.FeatureToggles.DeleteArchivedFeature("checkout"); !ok || err != nil {
+		t.Fatalf("DeleteArchivedFeature() = %v, %v", ok, err)
+	}
+	if _, _, err := client.FeatureToggles.GetFeatureByName("default", "checkout"); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("GetFeatureByName() after delete error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestServer_Validation(t *testing.T) {
+	t.Parallel()
+	_, client := newTestClient(t)
+
+	_, _, err := client.FeatureToggles.CreateFeature("default", api.FeatureToggle{Name: "has spaces"})
+	if !errors.Is(err, api.ErrValidation) {
+		t.Errorf("CreateFeature() with an invalid name error = %v, want ErrValidation", err)
+	}
+	_, _, err = client.FeatureToggles.CreateFeature("missing", api.FeatureToggle{Name: "valid"})
+	if !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("CreateFeature() in a missing project error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestServer_UsersAndTokens(t *testing.T) {
+	t.Parallel()
+	_, client := newTestClient(t)
+
+	user, _, err := client.Users.CreateUser(api.User{Name: "Jane Doe", Email: "jane@example.com", RootRole: 2})
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	found, _, err := client.Users.SearchUser("jane")
+	if err != nil {
+		t.Fatalf("SearchUser() error = %v", err)
+	}
+	if len(*found) != 1 || (*found)[0].Id != user.Id {
+		t.Errorf("SearchUser() got = %v", *found)
+	}
+	if _, _, err := client.Projects.AddUserProject(user.Id, "default", 4); err != nil {
+		t.Errorf("AddUserProject() error = %v", err)
+	}
+	if ok, _, err := client.Users.DeleteUser(strconv.Itoa(user.Id)); !ok || err != nil {
+		t.Errorf("DeleteUser() = %v, %v", ok, err)
+	}
+
+	token, _, err := client.ApiTokens.CreateApiToken(api.ApiToken{Username: "ci", Type: "client", Environment: "production"})
+	if err != nil {
+		t.Fatalf("CreateApiToken() error = %v", err)
+	}
+	tokens, _, err := client.ApiTokens.GetAllApiTokens()
+	if err != nil {
+		t.Fatalf("GetAllApiTokens() error = %v", err)
+	}
+	if len(tokens.Tokens) != 1 || tokens.Tokens[0].Secret != token.Secret {
+		t.Errorf("GetAllApiTokens() got = %v", tokens.Tokens)
+	}
+}
+
+func TestServer_RejectsWrongToken(t *testing.T) {
+	t.Parallel()
+	srv := NewServer()
+	defer srv.Close()
+
+	client, err := api.NewClient(srv.URL+"/api/", "wrong", api.WithHTTPClient(srv.Client()))
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if _, _, err := client.FeatureTypes.GetAllFeatureTypes(); !errors.Is(err, api.ErrUnauthorized) {
+		t.Errorf("GetAllFeatureTypes() error = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestServer_EnvironmentAndStrategyVariants(t *testing.T) {
+	t.Parallel()
+	srv, client := newTestClient(t)
+
+	if _, _, err := client.FeatureToggles.CreateFeature("default", api.FeatureToggle{Name: "checkout"}); err != nil {
+		t.Fatalf("CreateFeature() error = %v", err)
+	}
+	_, _, err := client.Variants.SetVariantsForEnvironments("default", "checkout", []string{"development", "production"}, []api.Variant{
+		{Name: "blue"},
+		{Name: "green"},
+	})
+	if err != nil {
+		t.Fatalf("SetVariantsForEnvironments() error = %v", err)
+	}
+	if _, _, err := client.Variants.SetEnvironmentVariants("default", "checkout", "production", []api.Variant{{Name: "red"}}); err != nil {
+		t.Fatalf("SetEnvironmentVariants() error = %v", err)
+	}
+
+	development, _, err := client.Variants.GetEnvironmentVariants("default", "checkout", "development")
+	if err != nil {
+		t.Fatalf("GetEnvironmentVariants() error = %v", err)
+	}
+	if len(development.Variants) != 2 || development.Variants[0].Weight != 500 {
+		t.Errorf("development variants = %+v", development.Variants)
+	}
+	production, _, err := client.Variants.GetEnvironmentVariants("default", "checkout", "production")
+	if err != nil {
+		t.Fatalf("GetEnvironmentVariants() error = %v", err)
+	}
+	if len(production.Variants) != 1 || production.Variants[0].Name != "red" {
+		t.Errorf("production variants = %+v", production.Variants)
+	}
+	if _, _, err := client.Variants.SetVariantsForEnvironments("default", "checkout", []string{"staging"}, []api.Variant{{Name: "red"}}); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("SetVariantsForEnvironments() on an unknown environment error = %v, want ErrNotFound", err)
+	}
+
+	strategy, _, err := client.FeatureToggles.AddStrategyToFeature("default", "checkout", "production", api.FeatureStrategy{
+		Name:     "default",
+		Variants: []api.Variant{{Name: "a", Stickiness: "userId"}, {Name: "b", Stickiness: "userId"}},
+	})
+	if err != nil {
+		t.Fatalf("AddStrategyToFeature() error = %v", err)
+	}
+	if len(strategy.Variants) != 2 || strategy.Variants[1].Weight != 500 {
+		t.Errorf("AddStrategyToFeature() variants = %+v", strategy.Variants)
+	}
+	strategy.Variants = strategy.Variants[:1]
+	if _, _, err := client.FeatureToggles.UpdateFeatureStrategy("default", "checkout", "production", *strategy); err != nil {
+		t.Fatalf("UpdateFeatureStrategy() error = %v", err)
+	}
+	feature, _ := srv.Feature("checkout")
+	stored := feature.Environments[1].Strategies[0].Variants
+	if len(stored) != 1 || stored[0].Weight != 1000 {
+		t.Errorf("stored strategy variants = %+v", stored)
+	}
+}
+
+func TestServer_SearchFeatures(t *testing.T) {
+	t.Parallel()
+	_, client := newTestClient(t)
+
+	if _, _, err := client.Projects.CreateProject(api.Project{Id: "payments", Name: "Payments"}); err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	for _, f := range []struct{ project, name, kind string }{
+		{"default", "search-box", "release"},
+		{"payments", "checkout-v2", "experiment"},
+		{"payments", "checkout-kill", "kill-switch"},
+	} {
+		if _, _, err := client.FeatureToggles.CreateFeature(f.project, api.FeatureToggle{Name: f.name, Type: f.kind}); err != nil {
+			t.Fatalf("CreateFeature() error = %v", err)
+		}
+	}
+	if _, _, err := client.FeatureToggles.EnableFeatureOnEnvironment("payments", "checkout-v2", "production", true); err != nil {
+		t.Fatalf("EnableFeatureOnEnvironment() error = %v", err)
+	}
+	if _, _, err := client.FeatureTags.CreateFeatureTags("checkout-kill", api.FeatureTag{Type: "simple", Value: "team-a"}); err != nil {
+		t.Fatalf("CreateFeatureTags() error = %v", err)
+	}
+
+	names := func(opts api.SearchFeaturesOptions) []string {
+		t.Helper()
+		result, _, err := client.FeatureToggles.SearchFeatures(opts)
+		if err != nil {
+			t.Fatalf("SearchFeatures() error = %v", err)
+		}
+		var names []string
+		for _, feature := range result.Features {
+			names = append(names, feature.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name string
+		opts api.SearchFeaturesOptions
+		want string
+	}{
+		{"All", api.SearchFeaturesOptions{}, "checkout-kill,checkout-v2,search-box"},
+		{"Query", api.SearchFeaturesOptions{Query: "checkout"}, "checkout-kill,checkout-v2"},
+		{"Project", api.SearchFeaturesOptions{Projects: []string{"default"}}, "search-box"},
+		{"Types", api.SearchFeaturesOptions{Types: []string{"release", "kill-switch"}}, "checkout-kill,search-box"},
+		{"Tag", api.SearchFeaturesOptions{Tags: []api.FeatureTag{{Type: "simple", Value: "team-a"}}}, "checkout-kill"},
+		{"Status", api.SearchFeaturesOptions{Status: []api.EnvironmentStatus{{Environment: "production", Enabled: true}}}, "checkout-v2"},
+		{"CreatedAfterTomorrow", api.SearchFeaturesOptions{CreatedAfter: time.Now().AddDate(0, 0, 2)}, ""},
+		{"Page", api.SearchFeaturesOptions{SortBy: "name", SortOrder: "desc", Offset: 1, Limit: 1}, "checkout-v2"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(names(tt.opts), ","); got != tt.want {
+			t.Errorf("%s: SearchFeatures() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	result, _, err := client.FeatureToggles.SearchFeatures(api.SearchFeaturesOptions{Limit: 2})
+	if err != nil {
+		t.Fatalf("SearchFeatures() error = %v", err)
+	}
+	if result.Total != 3 || len(result.Features) != 2 {
+		t.Errorf("SearchFeatures() total = %d with %d features, want 3 with 2", result.Total, len(result.Features))
+	}
+}
+
+func TestServer_Archive(t *testing.T) {
+	t.Parallel()
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	srv := NewServer(WithClock(func() time.Time { return clock }))
+	t.Cleanup(srv.Close)
+	client, err := srv.NewClient()
+	if err != nil {
+		t.Fatalf("Server.NewClient() error = %v", err)
+	}
+
+	for _, name := range []string{"old", "older", "recent"} {
+		if _, _, err := client.FeatureToggles.CreateFeature("default", api.FeatureToggle{Name: name}); err != nil {
+			t.Fatalf("CreateFeature() error = %v", err)
+		}
+	}
+	archive := func(name string, at time.Time) {
+		t.Helper()
+		clock = at
+		if _, _, err := client.FeatureToggles.ArchiveFeature("default", name); err != nil {
+			t.Fatalf("ArchiveFeature() error = %v", err)
+		}
+	}
+	archive("older", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	archive("old", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	archive("recent", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	archived, _, err := client.FeatureToggles.GetArchivedFeaturesByProject("default")
+	if err != nil {
+		t.Fatalf("GetArchivedFeaturesByProject() error = %v", err)
+	}
+	if len(*archived) != 3 || (*archived)[0].ArchivedAt != "2024-02-01T00:00:00Z" {
+		t.Errorf("GetArchivedFeaturesByProject() got = %+v", *archived)
+	}
+
+	if _, _, err := client.FeatureToggles.ReviveFeature("recent"); err != nil {
+		t.Fatalf("ReviveFeature() error = %v", err)
+	}
+	if feature, ok := srv.Feature("recent"); !ok || feature.Archived || feature.ArchivedAt != "" {
+		t.Errorf("revived feature = %+v, %v", feature, ok)
+	}
+	if _, _, err := client.FeatureToggles.ReviveFeatures("default", []string{"recent"}); !errors.Is(err, api.ErrValidation) {
+		t.Errorf("ReviveFeatures() of a live feature error = %v, want ErrValidation", err)
+	}
+
+	deleted, _, err := client.FeatureToggles.DeleteArchivedFeaturesOlderThan("default", time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("DeleteArchivedFeaturesOlderThan() error = %v", err)
+	}
+	if len(deleted) != 1 || deleted[0] != "older" {
+		t.Errorf("DeleteArchivedFeaturesOlderThan() deleted %v, want older", deleted)
+	}
+	if remaining := srv.ArchivedFeatures(); len(remaining) != 1 || remaining[0].Name != "old" {
+		t.Errorf("archive holds %+v, want old", remaining)
+	}
+
+	if _, _, err := client.FeatureToggles.ReviveFeatures("default", []string{"old"}); err != nil {
+		t.Fatalf("ReviveFeatures() error = %v", err)
+	}
+	all, _, err := client.FeatureToggles.GetArchivedFeatures()
+	if err != nil {
+		t.Fatalf("GetArchivedFeatures() error = %v", err)
+	}
+	if len(*all) != 0 {
+		t.Errorf("GetArchivedFeatures() got = %+v, want an empty archive", *all)
+	}
+}
+
+func TestServer_ArchivedFeatureStrategies(t *testing.T) {
+	t.Parallel()
+	_, client := newTestClient(t)
+
+	if _, _, err := client.FeatureToggles.CreateFeature("default", api.FeatureToggle{Name: "legacy"}); err != nil {
+		t.Fatalf("CreateFeature() error = %v", err)
+	}
+	if _, _, err := client.FeatureToggles.AddStrategyToFeature("default", "legacy", "production", api.FeatureStrategy{Name: "default"}); err != nil {
+		t.Fatalf("AddStrategyToFeature() error = %v", err)
+	}
+	if _, _, err := client.FeatureToggles.ArchiveFeature("default", "legacy"); err != nil {
+		t.Fatalf("ArchiveFeature() error = %v", err)
+	}
+
+	archived, _, err := client.FeatureToggles.GetArchivedFeatures()
+	if err != nil {
+		t.Fatalf("GetArchivedFeatures() error = %v", err)
+	}
+	for _, env := range (*archived)[0].Environments {
+		if len(env.Strategies) != 0 {
+			t.Errorf("archive lists the strategies of %s", env.Name)
+		}
+	}
+	strategies, _, err := client.FeatureToggles.GetFeatureStrategies("default", "legacy", "production")
+	if err != nil {
+		t.Fatalf("GetFeatureStrategies() error = %v", err)
+	}
+	if len(*strategies) != 1 || (*strategies)[0].Name != "default" {
+		t.Errorf("GetFeatureStrategies() got = %+v", *strategies)
+	}
+	if _, _, err := client.Variants.GetEnvironmentVariants("default", "legacy", "production"); err != nil {
+		t.Errorf("GetEnvironmentVariants() error = %v", err)
+	}
+}
+
+func TestServer_CloneFeature(t *testing.T) {
+	t.Parallel()
+	srv, client := newTestClient(t)
+
+	if _, _, err := client.FeatureToggles.CreateFeature("default", api.FeatureToggle{Name: "checkout", Type: "experiment"}); err != nil {
+		t.Fatalf("CreateFeature() error = %v", err)
+	}
+	rollout := api.NewFeatureStrategy(api.FlexibleRolloutParams{Rollout: 25, GroupId: "checkout"})
+	if _, _, err := client.FeatureToggles.AddStrategyToFeature("default", "checkout", "production", rollout); err != nil {
+		t.Fatalf("AddStrategyToFeature() error = %v", err)
+	}
+	if _, _, err := client.FeatureToggles.EnableFeatureOnEnvironment("default", "checkout", "production", true); err != nil {
+		t.Fatalf("EnableFeatureOnEnvironment() error = %v", err)
+	}
+
+	result, _, err := client.FeatureToggles.CloneFeature("default", "checkout", api.CloneFeatureOptions{
+		Name:             "checkout-copy",
+		ReplaceGroupId:   true,
+		CopyEnabledState: true,
+	})
+	if err != nil {
+		t.Fatalf("CloneFeature() error = %v", err)
+	}
+	if result.Replayed {
+		t.Error("CloneFeature() replayed although the clone endpoint exists")
+	}
+	production := result.Feature.Environments[1]
+	if !production.Enabled || len(production.Strategies) != 1 {
+		t.Fatalf("cloned production environment = %+v", production)
+	}
+	var params api.FlexibleRolloutParams
+	if err := production.Strategies[0].DecodeParameters(&params); err != nil || params.GroupId != "checkout-copy" || params.Rollout != 25 {
+		t.Errorf("cloned strategy parameters = %+v, %v", params, err)
+	}
+	if original, _ := srv.Feature("checkout"); original.Environments[1].Strategies[0].ID == production.Strategies[0].ID {
+		t.Error("the clone shares strategy ids with the original")
+	}
+
+	if _, _, err := client.FeatureToggles.CloneFeature("default", "checkout", api.CloneFeatureOptions{Name: "checkout-copy"}); !errors.Is(err, api.ErrConflict) {
+		t.Errorf("CloneFeature() onto an existing name error = %v, want ErrConflict", err)
+	}
+}
+
+func TestServer_MoveFeatures(t *testing.T) {
+	t.Parallel()
+	srv, client := newTestClient(t)
+
+	for _, id := range []string{"payments", "legacy"} {
+		if _, _, err := client.Projects.CreateProject(api.Project{Id: id, Name: id}); err != nil {
+			t.Fatalf("CreateProject() error = %v", err)
+		}
+	}
+	if _, _, err := client.Projects.RemoveEnvironmentFromProject("legacy", "production"); err != nil {
+		t.Fatalf("RemoveEnvironmentFromProject() error = %v", err)
+	}
+	for _, name := range []string{"pay-checkout", "pay-refunds", "search"} {
+		if _, _, err := client.FeatureToggles.CreateFeature("default", api.FeatureToggle{Name: name, Type: "release"}); err != nil {
+			t.Fatalf("CreateFeature() error = %v", err)
+		}
+	}
+
+	payments := func(f api.FeatureToggle) bool { return strings.HasPrefix(f.Name, "pay-") }
+	result, _, err := client.FeatureToggles.MoveFeatures("default", "payments", payments)
+	if err != nil {
+		t.Fatalf("MoveFeatures() error = %v", err)
+	}
+	if !reflect.DeepEqual(result.Moved, []string{"pay-checkout", "pay-refunds"}) {
+		t.Errorf("MoveFeatures() moved %v", result.Moved)
+	}
+	if feature, _ := srv.Feature("pay-refunds"); feature.Project != "payments" {
+		t.Errorf("pay-refunds is in project %q, want payments", feature.Project)
+	}
+	if feature, _ := srv.Feature("search"); feature.Project != "default" {
+		t.Errorf("search is in project %q, want default", feature.Project)
+	}
+
+	result, _, err = client.FeatureToggles.MoveFeatures("default", "legacy", nil)
+	if !errors.Is(err, api.ErrIncompleteMove) {
+		t.Fatalf("MoveFeatures() error = %v, want ErrIncompleteMove", err)
+	}
+	if len(result.Moved) != 0 || len(result.Conflicts) != 1 || !reflect.DeepEqual(result.Conflicts[0].MissingEnvironments, []string{"production"}) {
+		t.Errorf("MoveFeatures() result = %+v, want a conflict on production", result)
+	}
+
+	// the server refuses as well when the client side check is skipped
+	if _, _, err := client.FeatureToggles.ChangeFeatureProject("default", "search", "legacy"); !errors.Is(err, api.ErrConflict) {
+		t.Errorf("ChangeFeatureProject() error = %v, want ErrConflict", err)
+	}
+}
+
+func TestServer_PatchFeature(t *testing.T) {
+	t.Parallel()
+	srv, client := newTestClient(t)
+
+	original := api.FeatureToggle{Name: "checkout", Type: "release", Description: "New checkout"}
+	if _, _, err := client.FeatureToggles.CreateFeature("default", original); err != nil {
+		t.Fatalf("CreateFeature() error = %v", err)
+	}
+	// only the stale flag changes, the description survives
+	stale := original
+	stale.Stale = true
+	feature, _, err := client.FeatureToggles.PatchFeature("default", "checkout", api.DiffFeature(original, stale))
+	if err != nil {
+		t.Fatalf("PatchFeature() error = %v", err)
+	}
+	if !feature.Stale || feature.Description != "New checkout" {
+		t.Errorf("PatchFeature() = %+v, want stale with the description kept", feature)
+	}
+
+	ops := []api.PatchOperation{{Op: api.PatchTest, Path: "/stale", Value: false}, {Op: api.PatchReplace, Path: "/description", Value: ""}}
+	if _, _, err := client.FeatureToggles.PatchFeature("default", "checkout", ops); !errors.Is(err, api.ErrValidation) {
+		t.Errorf("PatchFeature() with a failing test error = %v, want ErrValidation", err)
+	}
+	if stored, _ := srv.Feature("checkout"); stored.Description != "New checkout" {
+		t.Errorf("a failed patch changed the description to %q", stored.Description)
+	}
+
+	added, _, err := client.FeatureToggles.AddStrategyToFeature("default", "checkout", "production",
+		api.NewFeatureStrategy(api.FlexibleRolloutParams{Rollout: 10, Stickiness: "default"}))
+	if err != nil {
+		t.Fatalf("AddStrategyToFeature() error = %v", err)
+	}
+	changed := *added
+	changed.Parameters = api.FlexibleRolloutParams{Rollout: 80, Stickiness: "default"}
+	changed.Constraints = []api.Constraint{{ContextName: "appName", Operator: api.OperatorIn, Values: []string{"web"}}}
+	ops, err = api.DiffFeatureStrategy(*added, changed)
+	if err != nil {
+		t.Fatalf("DiffFeatureStrategy() error = %v", err)
+	}
+	if _, _, err := client.FeatureToggles.PatchFeatureStrategy("default", "checkout", "production", added.ID, ops); err != nil {
+		t.Fatalf("PatchFeatureStrategy() error = %v", err)
+	}
+
+	strategy, _, err := client.FeatureToggles.PatchFeatureStrategy("default", "checkout", "production", added.ID, nil)
+	if err != nil {
+		t.Fatalf("PatchFeatureStrategy() without operations error = %v", err)
+	}
+	var params api.FlexibleRolloutParams
+	if err := strategy.DecodeParameters(&params); err != nil || params.Rollout != 80 || len(strategy.Constraints) != 1 {
+		t.Errorf("patched strategy = %+v with %+v, %v", strategy, params, err)
+	}
+	if rest, _ := api.DiffFeatureStrategy(*strategy, changed); len(rest) != 0 {
+		t.Errorf("patched strategy still differs by %+v", rest)
+	}
+}
+
+func TestServer_Lifecycle(t *testing.T) {
+	t.Parallel()
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	srv := NewServer(WithClock(func() time.Time { return clock }))
+	t.Cleanup(srv.Close)
+	client, err := srv.NewClient()
+	if err != nil {
+		t.Fatalf("Server.NewClient() error = %v", err)
+	}
+	if _, _, err := client.Projects.CreateProject(api.Project{Id: "payments", Name: "Payments"}); err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	create := func(project string, name string, featureType string, at time.Time) {
+		t.Helper()
+		clock = at
+		if _, _, err := client.FeatureToggles.CreateFeature(project, api.FeatureToggle{Name: name, Type: featureType}); err != nil {
+			t.Fatalf("CreateFeature() error = %v", err)
+		}
+	}
+	create("default", "old-release", "release", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	create("default", "new-release", "release", time.Date(2024, 2, 20, 0, 0, 0, 0, time.UTC))
+	create("default", "ops", "operational", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	create("default", "kill", "kill-switch", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	create("payments", "refunds", "release", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	report, _, err := client.FeatureToggles.GetLifecycleReport(api.LifecycleOptions{Now: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("GetLifecycleReport() error = %v", err)
+	}
+	var groups []string
+	for _, group := range report.Groups {
+		groups = append(groups, group.Project+"/"+group.Type+":"+strconv.Itoa(group.Overdue)+"/"+strconv.Itoa(len(group.Features)))
+	}
+	want := []string{"default/kill-switch:0/1", "default/operational:1/1", "default/release:1/2", "payments/release:1/1"}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("GetLifecycleReport() groups = %v, want %v", groups, want)
+	}
+	var overdue []string
+	for _, feature := range report.Overdue() {
+		overdue = append(overdue, feature.Name+":"+strconv.Itoa(feature.DaysOverdue))
+	}
+	if want := []string{"ops:22", "old-release:20", "refunds:20"}; !reflect.DeepEqual(overdue, want) {
+		t.Errorf("Overdue() = %v, want %v", overdue, want)
+	}
+
+	if _, _, err := client.FeatureToggles.MarkFeaturesStale("default", []string{"old-release", "ops"}, true); err != nil {
+		t.Fatalf("MarkFeaturesStale() error = %v", err)
+	}
+	if _, _, err := client.FeatureToggles.MarkFeaturesStale("default", []string{"ops"}, false); err != nil {
+		t.Fatalf("MarkFeaturesStale() error = %v", err)
+	}
+	for name, stale := range map[string]bool{"old-release": true, "ops": false, "new-release": false} {
+		if feature, _ := srv.Feature(name); feature.Stale != stale {
+			t.Errorf("%s stale = %v, want %v", name, feature.Stale, stale)
+		}
+	}
+	if _, _, err := client.FeatureToggles.MarkFeaturesStale("default", []string{"refunds"}, true); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("MarkFeaturesStale() for another project's feature error = %v, want ErrNotFound", err)
+	}
+
+	report, _, err = client.FeatureToggles.GetLifecycleReport(api.LifecycleOptions{Projects: []string{"payments"}, Now: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)})
+	if err != nil || len(report.Groups) != 1 || len(report.Overdue()) != 0 {
+		t.Errorf("GetLifecycleReport() for payments = %+v, %v", report, err)
+	}
+}
+
+func TestServer_StrategyOrder(t *testing.T) {
+	t.Parallel()
+	_, client := newTestClient(t)
+
+	if _, _, err := client.FeatureToggles.CreateFeature("default", api.FeatureToggle{Name: "checkout"}); err != nil {
+		t.Fatalf("CreateFeature() error = %v", err)
+	}
+	var ids []string
+	for i, name := range []string{"default", "userWithId", "remoteAddress"} {
+		strategy, _, err := client.FeatureToggles.AddStrategyToFeature("default", "checkout", "production", api.FeatureStrategy{Name: name, SortOrder: i})
+		if err != nil {
+			t.Fatalf("AddStrategyToFeature() error = %v", err)
+		}
+		ids = append(ids, strategy.ID)
+	}
+	order := func() []string {
+		t.Helper()
+		feature, _, err := client.FeatureToggles.GetFeatureByName("default", "checkout")
+		if err != nil {
+			t.Fatalf("GetFeatureByName() error = %v", err)
+		}
+		var names []string
+		for _, strategy := range feature.Environments[1].Strategies {
+			names = append(names, strategy.Name)
+		}
+		return names
+	}
+
+	if _, _, err := client.FeatureToggles.SetStrategySortOrder("default", "checkout", "production", []string{ids[2], ids[0], ids[1]}); err != nil {
+		t.Fatalf("SetStrategySortOrder() error = %v", err)
+	}
+	if got, want := order(), []string{"remoteAddress", "default", "userWithId"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("order after SetStrategySortOrder() = %v, want %v", got, want)
+	}
+
+	if _, _, err := client.FeatureToggles.MoveStrategy("default", "checkout", "production", ids[1], -1); err != nil {
+		t.Fatalf("MoveStrategy() error = %v", err)
+	}
+	if got, want := order(), []string{"remoteAddress", "userWithId", "default"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("order after moving userWithId up = %v, want %v", got, want)
+	}
+	if _, _, err := client.FeatureToggles.MoveStrategy("default", "checkout", "production", ids[2], 10); err != nil {
+		t.Fatalf("MoveStrategy() error = %v", err)
+	}
+	if got, want := order(), []string{"userWithId", "default", "remoteAddress"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("order after moving remoteAddress past the end = %v, want %v", got, want)
+	}
+
+	if _, _, err := client.FeatureToggles.SetStrategySortOrder("default", "checkout", "production", []string{"missing"}); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("SetStrategySortOrder() with an unknown id error = %v, want ErrNotFound", err)
+	}
+	if _, _, err := client.FeatureToggles.MoveStrategy("default", "checkout", "development", ids[0], 1); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("MoveStrategy() in the wrong environment error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestServer_PromoteFeature(t *testing.T) {
+	t.Parallel()
+	srv := NewServer(WithEnvironment("staging", "test"))
+	t.Cleanup(srv.Close)
+	client, err := srv.NewClient()
+	if err != nil {
+		t.Fatalf("Server.NewClient() error = %v", err)
+	}
+
+	if _, _, err := client.FeatureToggles.CreateFeature("default", api.FeatureToggle{Name: "checkout"}); err != nil {
+		t.Fatalf("CreateFeature() error = %v", err)
+	}
+	rollout := api.NewFeatureStrategy(api.FlexibleRolloutParams{Rollout: 30, Stickiness: "default", GroupId: "checkout"})
+	rollout.Constraints = []api.Constraint{{ContextName: "appName", Operator: api.OperatorIn, Values: []string{"web"}}}
+	rollout.Segments = []int{4}
+	rollout.Variants = []api.Variant{{Name: "blue", WeightType: api.WeightTypeVariable, Stickiness: "default"}}
+	add := func(environment string, strategy api.FeatureStrategy) {
+		t.Helper()
+		if _, _, err := client.FeatureToggles.AddStrategyToFeature("default", "checkout", environment, strategy); err != nil {
+			t.Fatalf("AddStrategyToFeature() error = %v", err)
+		}
+	}
+	add("staging", rollout)
+	add("staging", api.NewFeatureStrategy(api.UserWithIdParams{UserIds: []string{"1"}}))
+	add("production", api.FeatureStrategy{Name: "default"})
+	add("production", api.NewFeatureStrategy(api.UserWithIdParams{UserIds: []string{"1"}}))
+	add("production", api.NewFeatureStrategy(api.RemoteAddressParams{IPs: []string{"10.0.0.1"}}))
+	if _, _, err := client.FeatureToggles.EnableFeatureOnEnvironment("default", "checkout", "staging", true); err != nil {
+		t.Fatalf("EnableFeatureOnEnvironment() error = %v", err)
+	}
+	before, _ := srv.Feature("checkout")
+
+	opts := api.PromoteOptions{CopyEnabledState: true, DryRun: true}
+	plan, _, err := client.FeatureToggles.PromoteFeature("default", "checkout", "staging", "production", opts)
+	if err != nil {
+		t.Fatalf("PromoteFeature() dry run error = %v", err)
+	}
+	var kinds []string
+	for _, change := range plan.Changes {
+		kinds = append(kinds, change.Kind)
+	}
+	want := []string{api.PromotionUpdateStrategy, api.PromotionDeleteStrategy, api.PromotionSetEnabled}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("PromoteFeature() planned %v, want %v", kinds, want)
+	}
+	if after, _ := srv.Feature("checkout"); !reflect.DeepEqual(after, before) || plan.Applied != 0 {
+		t.Errorf("dry run changed the feature or applied %d changes", plan.Applied)
+	}
+
+	opts.DryRun = false
+	result, _, err := client.FeatureToggles.PromoteFeature("default", "checkout", "staging", "production", opts)
+	if err != nil {
+		t.Fatalf("PromoteFeature() error = %v", err)
+	}
+	if result.Applied != 3 {
+		t.Errorf("PromoteFeature() applied %d changes, want 3", result.Applied)
+	}
+	feature, _ := srv.Feature("checkout")
+	staging, production := feature.Environments[2], feature.Environments[1]
+	if !production.Enabled || len(production.Strategies) != len(staging.Strategies) {
+		t.Fatalf("production after promotion = %+v", production)
+	}
+	for i := range staging.Strategies {
+		if diff, _ := api.DiffFeatureStrategy(production.Strategies[i], staging.Strategies[i]); len(diff) != 0 {
+			t.Errorf("production strategy %d differs from staging by %+v", i, diff)
+		}
+	}
+
+	result, _, err = client.FeatureToggles.PromoteFeature("default", "checkout", "staging", "production", opts)
+	if err != nil || len(result.Changes) != 0 {
+		t.Errorf("PromoteFeature() of promoted environments = %+v, %v, want no changes", result, err)
+	}
+}
+
+func TestServer_InstanceSettings(t *testing.T) {
+	t.Parallel()
+	_, client := newTestClient(t)
+
+	if _, _, err := client.TagTypes.CreateTagType(api.TagType{Name: "team", Description: "Owning team"}); err != nil {
+		t.Fatalf("CreateTagType() error = %v", err)
+	}
+	if _, _, err := client.TagTypes.CreateTagType(api.TagType{Name: "team"}); !errors.Is(err, api.ErrConflict) {
+		t.Errorf("CreateTagType() twice error = %v, want ErrConflict", err)
+	}
+	if _, _, err := client.TagTypes.UpdateTagType(api.TagType{Name: "team", Description: "Owning squad"}); err != nil {
+		t.Fatalf("UpdateTagType() error = %v", err)
+	}
+	tagTypes, _, err := client.TagTypes.GetAllTagTypes()
+	if err != nil || len(*tagTypes) != 2 || (*tagTypes)[1].Description != "Owning squad" {
+		t.Errorf("GetAllTagTypes() = %+v, %v", tagTypes, err)
+	}
+
+	region := api.ContextField{Name: "region", Stickiness: true, SortOrder: 5, LegalValues: []api.LegalValue{{Value: "eu"}}}
+	if _, _, err := client.ContextFields.CreateContextField(region); err != nil {
+		t.Fatalf("CreateContextField() error = %v", err)
+	}
+	region.LegalValues = append(region.LegalValues, api.LegalValue{Value: "us"})
+	if _, _, err := client.ContextFields.UpdateContextField(region); err != nil {
+		t.Fatalf("UpdateContextField() error = %v", err)
+	}
+	fields, _, err := client.ContextFields.GetAllContextFields()
+	if err != nil {
+		t.Fatalf("GetAllContextFields() error = %v", err)
+	}
+	var names []string
+	for _, field := range *fields {
+		names = append(names, field.Name)
+	}
+	if want := []string{"environment", "userId", "appName", "region"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("GetAllContextFields() = %v, want %v", names, want)
+	}
+	if got := (*fields)[3].LegalValues; len(got) != 2 {
+		t.Errorf("region legal values = %+v, want eu and us", got)
+	}
+
+	segment, _, err := client.Segments.CreateSegment(api.Segment{Name: "beta", Constraints: []api.Constraint{{ContextName: "region", Operator: api.OperatorIn, Values: []string{"eu"}}}})
+	if err != nil {
+		t.Fatalf("CreateSegment() error = %v", err)
+	}
+	if _, _, err := client.Segments.CreateSegment(api.Segment{Name: "bad", Constraints: []api.Constraint{{ContextName: "region", Operator: "LIKE"}}}); !errors.Is(err, api.ErrValidation) {
+		t.Errorf("CreateSegment() with a bad constraint error = %v, want ErrValidation", err)
+	}
+	if _, _, err := client.FeatureToggles.CreateFeature("default", api.FeatureToggle{Name: "checkout"}); err != nil {
+		t.Fatalf("CreateFeature() error = %v", err)
+	}
+	if _, _, err := client.FeatureToggles.AddStrategyToFeature("default", "checkout", "production", api.FeatureStrategy{Name: "default", Segments: []int{segment.ID}}); err != nil {
+		t.Fatalf("AddStrategyToFeature() error = %v", err)
+	}
+	if _, _, err := client.Segments.DeleteSegment(segment.ID); !errors.Is(err, api.ErrConflict) {
+		t.Errorf("DeleteSegment() of a used segment error = %v, want ErrConflict", err)
+	}
+
+	featureType, _, err := client.FeatureTypes.UpdateFeatureTypeLifetime("release", 90)
+	if err != nil || featureType.LifetimeDays != 90 {
+		t.Errorf("UpdateFeatureTypeLifetime() = %+v, %v", featureType, err)
+	}
+	if _, _, err := client.FeatureTypes.UpdateFeatureTypeLifetime("missing", 1); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("UpdateFeatureTypeLifetime(missing) error = %v, want ErrNotFound", err)
+	}
+}
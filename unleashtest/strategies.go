@@ -0,0 +1,91 @@
+package unleashtest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/sighphyre/go-unleash-api/api"
+)
+
+func (s *Server) getStrategies(w http.ResponseWriter, r *http.Request, p params) {
+	names := make([]string, 0, len(s.strategies))
+	for name := range s.strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	strategies := make([]api.Strategy, 0, len(names))
+	for _, name := range names {
+		strategies = append(strategies, *s.strategies[name])
+	}
+	writeJSON(w, http.StatusOK, api.AllStrategiesResponse{Version: 1, Strategies: strategies})
+}
+
+func (s *Server) getStrategy(w http.ResponseWriter, r *http.Request, p params) {
+	strategy, ok := s.strategies[p["strategy"]]
+	if !ok {
+		writeNotFound(w, "Could not find strategy with name "+p["strategy"])
+		return
+	}
+	writeJSON(w, http.StatusOK, strategy)
+}
+
+func (s *Server) createStrategy(w http.ResponseWriter, r *http.Request, p params) {
+	var strategy api.Strategy
+	if !decodeBody(w, r, &strategy) {
+		return
+	}
+	if strategy.Name == "" || !urlFriendly.MatchString(strategy.Name) {
+		writeValidationError(w, `"name" must be URL friendly`)
+		return
+	}
+	if _, exists := s.strategies[strategy.Name]; exists {
+		writeError(w, http.StatusConflict, "NameExistsError", "Strategy with name "+strategy.Name+" already exists")
+		return
+	}
+
+	strategy.Editable = true
+	strategy.Deprecated = false
+	if strategy.Parameters == nil {
+		strategy.Parameters = []api.StrategyParameter{}
+	}
+	s.strategies[strategy.Name] = &strategy
+	writeJSON(w, http.StatusCreated, strategy)
+}
+
+func (s *Server) updateStrategy(w http.ResponseWriter, r *http.Request, p params) {
+	existing, ok := s.strategies[p["strategy"]]
+	if !ok {
+		writeNotFound(w, "Could not find strategy with name "+p["strategy"])
+		return
+	}
+	if !existing.Editable {
+		writeError(w, http.StatusForbidden, "InvalidOperationError", "Built-in strategies can not be changed")
+		return
+	}
+	var strategy api.Strategy
+	if !decodeBody(w, r, &strategy) {
+		return
+	}
+
+	existing.DisplayName = strategy.DisplayName
+	existing.Description = strategy.Description
+	existing.Parameters = strategy.Parameters
+	writeJSON(w, http.StatusOK, existing)
+}
+
+func (s *Server) setStrategyDeprecated(deprecated bool) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, p params) {
+		strategy, ok := s.strategies[p["strategy"]]
+		if !ok {
+			writeNotFound(w, "Could not find strategy with name "+p["strategy"])
+			return
+		}
+		if deprecated && strategy.Name == "default" {
+			writeError(w, http.StatusForbidden, "InvalidOperationError", "You can not deprecate the default strategy")
+			return
+		}
+		strategy.Deprecated = deprecated
+		writeJSON(w, http.StatusOK, nil)
+	}
+}
@@ -0,0 +1,109 @@
+package unleashtest
+
+import (
+	"net/http"
+
+	"github.com/sighphyre/go-unleash-api/api"
+)
+
+func (s *Server) featureExists(w http.ResponseWriter, name string) bool {
+	_, live := s.features[name]
+	_, archived := s.archived[name]
+	if !live && !archived {
+		writeNotFound(w, "Could not find feature toggle with name "+name)
+		return false
+	}
+	return true
+}
+
+func (s *Server) getFeatureTags(w http.ResponseWriter, r *http.Request, p params) {
+	if !s.featureExists(w, p["feature"]) {
+		return
+	}
+	writeJSON(w, http.StatusOK, api.FeatureTagsResponse{Version: 1, Tags: s.featureTags(p["feature"])})
+}
+
+func (s *Server) addFeatureTag(w http.ResponseWriter, r *http.Request, p params) {
+	if !s.featureExists(w, p["feature"]) {
+		return
+	}
+	var tag api.FeatureTag
+	if !decodeBody(w, r, &tag) {
+		return
+	}
+	if !validTag(w, tag) {
+		return
+	}
+	if s.hasTag(p["feature"], tag) {
+		writeError(w, http.StatusConflict, "NameExistsError", "The feature already has the tag "+tag.Type+":"+tag.Value)
+		return
+	}
+
+	s.tags[p["feature"]] = append(s.tags[p["feature"]], tag)
+	writeJSON(w, http.StatusCreated, tag)
+}
+
+func (s *Server) updateFeatureTags(w http.ResponseWriter, r *http.Request, p params) {
+	if !s.featureExists(w, p["feature"]) {
+		return
+	}
+	var body api.UpdateFeatureTagsBody
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	for _, tag := range append(append([]api.FeatureTag{}, body.AddedTags...), body.RemovedTags...) {
+		if !validTag(w, tag) {
+			return
+		}
+	}
+
+	for _, tag := range body.RemovedTags {
+		s.removeTag(p["feature"], tag)
+	}
+	for _, tag := range body.AddedTags {
+		if !s.hasTag(p["feature"], tag) {
+			s.tags[p["feature"]] = append(s.tags[p["feature"]], tag)
+		}
+	}
+	writeJSON(w, http.StatusOK, api.FeatureTagsResponse{Version: 1, Tags: s.featureTags(p["feature"])})
+}
+
+func (s *Server) deleteFeatureTag(w http.ResponseWriter, r *http.Request, p params) {
+	if !s.featureExists(w, p["feature"]) {
+		return
+	}
+	s.removeTag(p["feature"], api.FeatureTag{Type: p["type"], Value: p["value"]})
+	writeJSON(w, http.StatusOK, nil)
+}
+
+func validTag(w http.ResponseWriter, tag api.FeatureTag) bool {
+	if tag.Type == "" || tag.Value == "" {
+		writeValidationError(w, `tags need both a "type" and a "value"`)
+		return false
+	}
+	return true
+}
+
+func (s *Server) featureTags(feature string) []api.FeatureTag {
+	tags := append([]api.FeatureTag{}, s.tags[feature]...)
+	return tags
+}
+
+func (s *Server) hasTag(feature string, tag api.FeatureTag) bool {
+	for _, existing := range s.tags[feature] {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) removeTag(feature string, tag api.FeatureTag) {
+	tags := s.tags[feature][:0]
+	for _, existing := range s.tags[feature] {
+		if existing != tag {
+			tags = append(tags, existing)
+		}
+	}
+	s.tags[feature] = tags
+}
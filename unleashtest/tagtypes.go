@@ -0,0 +1,78 @@
+package unleashtest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/sighphyre/go-unleash-api/api"
+)
+
+func (s *Server) getTagTypes(w http.ResponseWriter, r *http.Request, p params) {
+	names := make([]string, 0, len(s.tagTypes))
+	for name := range s.tagTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tagTypes := make([]api.TagType, 0, len(names))
+	for _, name := range names {
+		tagTypes = append(tagTypes, *s.tagTypes[name])
+	}
+	writeJSON(w, http.StatusOK, struct {
+		Version  int           `json:"version"`
+		TagTypes []api.TagType `json:"tagTypes"`
+	}{1, tagTypes})
+}
+
+func (s *Server) getTagType(w http.ResponseWriter, r *http.Request, p params) {
+	tagType, ok := s.tagTypes[p["name"]]
+	if !ok {
+		writeNotFound(w, "Could not find tag-type with name: "+p["name"])
+		return
+	}
+	writeJSON(w, http.StatusOK, struct {
+		Version int         `json:"version"`
+		TagType api.TagType `json:"tagType"`
+	}{1, *tagType})
+}
+
+func (s *Server) createTagType(w http.ResponseWriter, r *http.Request, p params) {
+	var tagType api.TagType
+	if !decodeBody(w, r, &tagType) {
+		return
+	}
+	if tagType.Name == "" || !urlFriendly.MatchString(tagType.Name) {
+		writeValidationError(w, `"name" must be URL friendly`)
+		return
+	}
+	if _, exists := s.tagTypes[tagType.Name]; exists {
+		writeError(w, http.StatusConflict, "NameExistsError", "There's already a tag type with the name "+tagType.Name)
+		return
+	}
+	s.tagTypes[tagType.Name] = &tagType
+	writeJSON(w, http.StatusCreated, tagType)
+}
+
+func (s *Server) updateTagType(w http.ResponseWriter, r *http.Request, p params) {
+	existing, ok := s.tagTypes[p["name"]]
+	if !ok {
+		writeNotFound(w, "Could not find tag-type with name: "+p["name"])
+		return
+	}
+	var tagType api.TagType
+	if !decodeBody(w, r, &tagType) {
+		return
+	}
+	existing.Description = tagType.Description
+	existing.Icon = tagType.Icon
+	writeJSON(w, http.StatusOK, existing)
+}
+
+func (s *Server) deleteTagType(w http.ResponseWriter, r *http.Request, p params) {
+	if _, ok := s.tagTypes[p["name"]]; !ok {
+		writeNotFound(w, "Could not find tag-type with name: "+p["name"])
+		return
+	}
+	delete(s.tagTypes, p["name"])
+	writeJSON(w, http.StatusOK, nil)
+}
@@ -0,0 +1,102 @@
+package unleashtest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/sighphyre/go-unleash-api/api"
+)
+
+func (s *Server) getApiTokens(w http.ResponseWriter, r *http.Request, p params) {
+	secrets := make([]string, 0, len(s.tokens))
+	for secret := range s.tokens {
+		secrets = append(secrets, secret)
+	}
+	sort.Strings(secrets)
+
+	tokens := make([]api.ApiToken, 0, len(secrets))
+	for _, secret := range secrets {
+		tokens = append(tokens, *s.tokens[secret])
+	}
+	writeJSON(w, http.StatusOK, api.AllApiTokensResponse{Tokens: tokens})
+}
+
+func (s *Server) createApiToken(w http.ResponseWriter, r *http.Request, p params) {
+	var token api.ApiToken
+	if !decodeBody(w, r, &token) {
+		return
+	}
+	if token.Username == "" {
+		writeValidationError(w, `"username" is required`)
+		return
+	}
+	token.Type = strings.ToLower(token.Type)
+	switch token.Type {
+	case "admin":
+		token.Environment = "*"
+		token.Projects = []string{"*"}
+	case "client", "frontend":
+		if token.Environment == "" {
+			token.Environment = "development"
+		}
+		if len(token.Projects) == 0 {
+			token.Projects = []string{"*"}
+		}
+	default:
+		writeValidationError(w, `"type" must be one of admin, client or frontend`)
+		return
+	}
+	for _, projectId := range token.Projects {
+		if _, ok := s.projects[projectId]; !ok && projectId != "*" {
+			writeValidationError(w, "Project "+projectId+" does not exist")
+			return
+		}
+	}
+
+	project := token.Projects[0]
+	if len(token.Projects) > 1 {
+		project = "[]"
+	}
+	token.Secret = project + ":" + token.Environment + "." + randomHex(28)
+	token.CreatedAt = s.now()
+	s.tokens[token.Secret] = &token
+	writeJSON(w, http.StatusCreated, token)
+}
+
+func (s *Server) updateApiToken(w http.ResponseWriter, r *http.Request, p params) {
+	token, ok := s.tokens[p["secret"]]
+	if !ok {
+		writeNotFound(w, "Could not find API token")
+		return
+	}
+	var body api.ApiToken
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if body.ExpiresAt == "" {
+		writeValidationError(w, `"expiresAt" is required`)
+		return
+	}
+	token.ExpiresAt = body.ExpiresAt
+	writeJSON(w, http.StatusOK, nil)
+}
+
+func (s *Server) deleteApiToken(w http.ResponseWriter, r *http.Request, p params) {
+	if _, ok := s.tokens[p["secret"]]; !ok {
+		writeNotFound(w, "Could not find API token")
+		return
+	}
+	delete(s.tokens, p["secret"])
+	writeJSON(w, http.StatusOK, nil)
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, (n+1)/2)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buf)[:n]
+}
@@ -0,0 +1,113 @@
+package unleashtest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sighphyre/go-unleash-api/api"
+)
+
+func (s *Server) lookupUser(w http.ResponseWriter, p params) (*api.UserDetails, bool) {
+	id, err := strconv.Atoi(p["user"])
+	if err != nil {
+		writeValidationError(w, "id must be a number")
+		return nil, false
+	}
+	user, ok := s.users[id]
+	if !ok {
+		writeNotFound(w, "Could not find user with id "+p["user"])
+		return nil, false
+	}
+	return user, true
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request, p params) {
+	user, ok := s.lookupUser(w, p)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) searchUsers(w http.ResponseWriter, r *http.Request, p params) {
+	q := strings.ToLower(r.URL.Query().Get("q"))
+
+	ids := make([]int, 0, len(s.users))
+	for id := range s.users {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	users := []api.UserDetails{}
+	for _, id := range ids {
+		user := s.users[id]
+		if strings.Contains(strings.ToLower(user.Name), q) ||
+			strings.Contains(strings.ToLower(user.Username), q) ||
+			strings.Contains(strings.ToLower(user.Email), q) {
+			users = append(users, *user)
+		}
+	}
+	writeJSON(w, http.StatusOK, users)
+}
+
+func (s *Server) createUser(w http.ResponseWriter, r *http.Request, p params) {
+	var body api.User
+	if !decodeBody(w, r, &body) {
+		return
+	}
+	if body.Email == "" && body.Username == "" {
+		writeValidationError(w, "You must specify username or email")
+		return
+	}
+	for _, user := range s.users {
+		if (body.Email != "" && strings.EqualFold(user.Email, body.Email)) ||
+			(body.Username != "" && user.Username == body.Username) {
+			writeError(w, http.StatusConflict, "NameExistsError", "User already exists")
+			return
+		}
+	}
+
+	s.sequence++
+	user := &api.UserDetails{
+		Id:        s.sequence,
+		Name:      body.Name,
+		Username:  body.Username,
+		Email:     body.Email,
+		CreatedAt: s.now(),
+		EmailSent: body.SendEmail,
+		RootRole:  body.RootRole,
+	}
+	s.users[user.Id] = user
+	writeJSON(w, http.StatusCreated, user)
+}
+
+func (s *Server) updateUser(w http.ResponseWriter, r *http.Request, p params) {
+	user, ok := s.lookupUser(w, p)
+	if !ok {
+		return
+	}
+	var body api.User
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	user.Name = body.Name
+	if body.Email != "" {
+		user.Email = body.Email
+	}
+	if body.RootRole != 0 {
+		user.RootRole = body.RootRole
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) deleteUser(w http.ResponseWriter, r *http.Request, p params) {
+	user, ok := s.lookupUser(w, p)
+	if !ok {
+		return
+	}
+	delete(s.users, user.Id)
+	writeJSON(w, http.StatusOK, nil)
+}